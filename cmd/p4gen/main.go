@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Command p4gen generates typed Go bindings for the tables, actions and controller packet metadata described
+// by a P4Info file, e.g.
+//
+//	p4gen -p4info test/p4info.txt -package fabric -out test/fabric/p4info.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/onosproject/onos-control/pkg/codegen"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	infoPath := flag.String("p4info", "", "path of the P4Info text file")
+	packageName := flag.String("package", "", "name of the generated Go package")
+	outPath := flag.String("out", "", "path of the generated Go file; standard output if not specified")
+	flag.Parse()
+
+	if *infoPath == "" || *packageName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := generate(*infoPath, *packageName, *outPath); err != nil {
+		fmt.Fprintf(os.Stderr, "p4gen: %+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(infoPath string, packageName string, outPath string) error {
+	info, err := p4utils.LoadP4Info(infoPath)
+	if err != nil {
+		return err
+	}
+	code, err := codegen.Generate(info, packageName, filepath.Base(infoPath))
+	if err != nil {
+		return err
+	}
+	if outPath == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return os.WriteFile(outPath, code, 0644)
+}
@@ -0,0 +1,408 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"math/big"
+	"net"
+	"strings"
+)
+
+// EntityBuilder builds pipeline entities by referring to tables, match fields, actions and action parameters
+// by their names, as defined by the pipeline info, e.g. as returned by DeviceControl.Pipeline(). Objects can be
+// referred to by either their fully qualified names or their aliases.
+//
+// Values can be given as integers, byte slices, net.IP, net.HardwareAddr or strings; strings are parsed as
+// IP addresses, MAC addresses, serializable enum member names or numbers, in decimal or 0x-prefixed hexadecimal
+// form. All values are encoded into the canonical P4Runtime byte string representation.
+type EntityBuilder struct {
+	info *p4info.P4Info
+}
+
+// NewEntityBuilder returns a new entity builder for the given pipeline info
+func NewEntityBuilder(info *p4info.P4Info) *EntityBuilder {
+	return &EntityBuilder{info: info}
+}
+
+// TableEntryBuilder accumulates the match fields and the action of a table entry. The first error encountered is
+// retained and reported when the entry is built.
+type TableEntryBuilder struct {
+	info  *p4info.P4Info
+	table *p4info.Table
+	entry *p4api.TableEntry
+	err   error
+}
+
+// ParamValue is a named value of an action parameter
+type ParamValue struct {
+	Name  string
+	Value interface{}
+}
+
+// Param returns the named value of an action parameter
+func Param(name string, value interface{}) ParamValue {
+	return ParamValue{Name: name, Value: value}
+}
+
+// Table starts building an entry of the named table
+func (b *EntityBuilder) Table(name string) *TableEntryBuilder {
+	tb := &TableEntryBuilder{info: b.info}
+	if tb.table = findTableByName(b.info, name); tb.table == nil {
+		tb.err = errors.NewInvalid("Unknown table %s", name)
+		return tb
+	}
+	tb.entry = &p4api.TableEntry{TableId: tb.table.Preamble.Id}
+	return tb
+}
+
+// Exact adds an exact match of the named field
+func (tb *TableEntryBuilder) Exact(field string, value interface{}) *TableEntryBuilder {
+	return tb.match(field, p4info.MatchField_EXACT, func(mf *p4info.MatchField) (*p4api.FieldMatch, error) {
+		v, err := encodeValue(tb.info, mf.GetTypeName(), mf.Bitwidth, value)
+		if err != nil {
+			return nil, err
+		}
+		return &p4api.FieldMatch{FieldId: mf.Id, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: v}}}, nil
+	})
+}
+
+// Ternary adds a ternary match of the named field using the given value and mask
+func (tb *TableEntryBuilder) Ternary(field string, value interface{}, mask interface{}) *TableEntryBuilder {
+	return tb.match(field, p4info.MatchField_TERNARY, func(mf *p4info.MatchField) (*p4api.FieldMatch, error) {
+		v, err := encodeValue(tb.info, mf.GetTypeName(), mf.Bitwidth, value)
+		if err != nil {
+			return nil, err
+		}
+		m, err := encodeValue(tb.info, nil, maskBitwidth(tb.info, mf, v), mask)
+		if err != nil {
+			return nil, err
+		}
+		if len(v) > len(m) || !bytesEqualMasked(v, m) {
+			return nil, errors.NewInvalid("value %v has bits outside of mask %v", value, mask)
+		}
+		return &p4api.FieldMatch{FieldId: mf.Id, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: v, Mask: m}}}, nil
+	})
+}
+
+// LPM adds a longest-prefix match of the named field using the given value and prefix length; the value must not
+// have any bits set beyond the prefix
+func (tb *TableEntryBuilder) LPM(field string, value interface{}, prefixLen int32) *TableEntryBuilder {
+	return tb.match(field, p4info.MatchField_LPM, func(mf *p4info.MatchField) (*p4api.FieldMatch, error) {
+		if prefixLen < 0 || prefixLen > mf.Bitwidth {
+			return nil, errors.NewInvalid("prefix length %d exceeds bitwidth %d", prefixLen, mf.Bitwidth)
+		}
+		v, err := encodeValue(tb.info, mf.GetTypeName(), mf.Bitwidth, value)
+		if err != nil {
+			return nil, err
+		}
+		host := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(mf.Bitwidth-prefixLen)), big.NewInt(1))
+		if host.And(host, new(big.Int).SetBytes(v)).Sign() != 0 {
+			return nil, errors.NewInvalid("value %v has bits beyond prefix length %d", value, prefixLen)
+		}
+		return &p4api.FieldMatch{FieldId: mf.Id, FieldMatchType: &p4api.FieldMatch_Lpm{Lpm: &p4api.FieldMatch_LPM{Value: v, PrefixLen: prefixLen}}}, nil
+	})
+}
+
+// Range adds a range match of the named field using the given inclusive bounds
+func (tb *TableEntryBuilder) Range(field string, low interface{}, high interface{}) *TableEntryBuilder {
+	return tb.match(field, p4info.MatchField_RANGE, func(mf *p4info.MatchField) (*p4api.FieldMatch, error) {
+		l, err := encodeValue(tb.info, mf.GetTypeName(), mf.Bitwidth, low)
+		if err != nil {
+			return nil, err
+		}
+		h, err := encodeValue(tb.info, mf.GetTypeName(), mf.Bitwidth, high)
+		if err != nil {
+			return nil, err
+		}
+		return &p4api.FieldMatch{FieldId: mf.Id, FieldMatchType: &p4api.FieldMatch_Range_{Range: &p4api.FieldMatch_Range{Low: l, High: h}}}, nil
+	})
+}
+
+// Optional adds an optional match of the named field
+func (tb *TableEntryBuilder) Optional(field string, value interface{}) *TableEntryBuilder {
+	return tb.match(field, p4info.MatchField_OPTIONAL, func(mf *p4info.MatchField) (*p4api.FieldMatch, error) {
+		v, err := encodeValue(tb.info, mf.GetTypeName(), mf.Bitwidth, value)
+		if err != nil {
+			return nil, err
+		}
+		return &p4api.FieldMatch{FieldId: mf.Id, FieldMatchType: &p4api.FieldMatch_Optional_{Optional: &p4api.FieldMatch_Optional{Value: v}}}, nil
+	})
+}
+
+func (tb *TableEntryBuilder) match(field string, matchType p4info.MatchField_MatchType,
+	encode func(mf *p4info.MatchField) (*p4api.FieldMatch, error)) *TableEntryBuilder {
+	if tb.err != nil {
+		return tb
+	}
+	mf := findMatchFieldByName(tb.table, field)
+	if mf == nil {
+		tb.err = errors.NewInvalid("Table %s has no match field %s", tb.table.Preamble.Name, field)
+		return tb
+	}
+	if mf.GetMatchType() != matchType {
+		tb.err = errors.NewInvalid("Table %s match field %s is %s, not %s", tb.table.Preamble.Name, field, mf.GetMatchType(), matchType)
+		return tb
+	}
+	fm, err := encode(mf)
+	if err != nil {
+		tb.err = errors.NewInvalid("Table %s match field %s: %s", tb.table.Preamble.Name, field, err.Error())
+		return tb
+	}
+	tb.entry.Match = append(tb.entry.Match, fm)
+	return tb
+}
+
+// Priority sets the priority of the entry
+func (tb *TableEntryBuilder) Priority(priority int32) *TableEntryBuilder {
+	if tb.err == nil {
+		tb.entry.Priority = priority
+	}
+	return tb
+}
+
+// Default marks the entry as the default entry of the table
+func (tb *TableEntryBuilder) Default() *TableEntryBuilder {
+	if tb.err == nil {
+		tb.entry.IsDefaultAction = true
+	}
+	return tb
+}
+
+// Action sets the named action of the entry along with its named parameters; all parameters are required.
+func (tb *TableEntryBuilder) Action(name string, params ...ParamValue) *TableEntryBuilder {
+	if tb.err != nil {
+		return tb
+	}
+	action, err := buildAction(tb.info, name, params)
+	if err != nil {
+		tb.err = err
+		return tb
+	}
+	if !HasActionRef(tb.table, action.ActionId) {
+		tb.err = errors.NewInvalid("Action %s is not allowed for table %s", name, tb.table.Preamble.Name)
+		return tb
+	}
+	tb.entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	return tb
+}
+
+// TableEntry returns the built table entry or the first error encountered while building it
+func (tb *TableEntryBuilder) TableEntry() (*p4api.TableEntry, error) {
+	if tb.err != nil {
+		return nil, tb.err
+	}
+	return tb.entry, nil
+}
+
+// Entity returns the built table entry as an entity or the first error encountered while building it
+func (tb *TableEntryBuilder) Entity() (*p4api.Entity, error) {
+	entry, err := tb.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the built table entry or the first error encountered
+// while building it
+func (tb *TableEntryBuilder) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := tb.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// Builds the named action with the given named parameters
+func buildAction(info *p4info.P4Info, name string, params []ParamValue) (*p4api.Action, error) {
+	ai := findActionByName(info, name)
+	if ai == nil {
+		return nil, errors.NewInvalid("Unknown action %s", name)
+	}
+	action := &p4api.Action{ActionId: ai.Preamble.Id}
+	given := make(map[string]bool)
+	for _, pv := range params {
+		var param *p4info.Action_Param
+		for _, p := range ai.Params {
+			if p.Name == pv.Name {
+				param = p
+			}
+		}
+		if param == nil {
+			return nil, errors.NewInvalid("Action %s has no parameter %s", ai.Preamble.Name, pv.Name)
+		}
+		if given[pv.Name] {
+			return nil, errors.NewInvalid("Action %s parameter %s given more than once", ai.Preamble.Name, pv.Name)
+		}
+		given[pv.Name] = true
+		value, err := encodeValue(info, param.GetTypeName(), param.Bitwidth, pv.Value)
+		if err != nil {
+			return nil, errors.NewInvalid("Action %s parameter %s: %s", ai.Preamble.Name, pv.Name, err.Error())
+		}
+		action.Params = append(action.Params, &p4api.Action_Param{ParamId: param.Id, Value: value})
+	}
+	var missing []string
+	for _, p := range ai.Params {
+		if !given[p.Name] {
+			missing = append(missing, p.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, errors.NewInvalid("Action %s is missing parameters %s", ai.Preamble.Name, strings.Join(missing, ", "))
+	}
+	return action, nil
+}
+
+func findTableByName(info *p4info.P4Info, name string) *p4info.Table {
+	for _, table := range info.GetTables() {
+		if table.Preamble.Name == name || table.Preamble.Alias == name {
+			return table
+		}
+	}
+	return nil
+}
+
+func findMatchFieldByName(table *p4info.Table, name string) *p4info.MatchField {
+	for _, mf := range table.MatchFields {
+		if mf.Name == name {
+			return mf
+		}
+	}
+	return nil
+}
+
+func findActionByName(info *p4info.P4Info, name string) *p4info.Action {
+	for _, action := range info.GetActions() {
+		if action.Preamble.Name == name || action.Preamble.Alias == name {
+			return action
+		}
+	}
+	return nil
+}
+
+// Encodes the given value into the canonical byte string of a value with the given bitwidth; values of
+// translated types are validated against their SDN bitwidth and values of serializable enums may be given
+// by member names
+func encodeValue(info *p4info.P4Info, typeName *p4info.P4NamedType, bitwidth int32, value interface{}) ([]byte, error) {
+	if typeName != nil {
+		if enum := info.GetTypeInfo().GetSerializableEnums()[typeName.Name]; enum != nil {
+			if s, ok := value.(string); ok {
+				name := strings.TrimPrefix(s, typeName.Name+".")
+				for _, member := range enum.Members {
+					if member.Name == name {
+						return canonicalValue(member.Value), nil
+					}
+				}
+			}
+		}
+		if translated := info.GetTypeInfo().GetNewTypes()[typeName.Name].GetTranslatedType(); translated != nil {
+			if translated.GetSdnString() != nil {
+				if s, ok := value.(string); ok {
+					return []byte(s), nil
+				}
+				return nil, errors.NewInvalid("value of %s must be a string", typeName.Name)
+			}
+			bitwidth = translated.GetSdnBitwidth()
+		}
+	}
+
+	n, err := valueAsInt(value)
+	if err != nil {
+		return nil, err
+	}
+	if n.Sign() < 0 {
+		return nil, errors.NewInvalid("value %v is negative", value)
+	}
+	if n.BitLen() > int(bitwidth) {
+		return nil, errors.NewInvalid("value %v exceeds bitwidth %d", value, bitwidth)
+	}
+	if b := n.Bytes(); len(b) > 0 {
+		return b, nil
+	}
+	return []byte{0}, nil
+}
+
+// Returns true if values of the named type are translated into strings by the SDN
+func isSdnString(info *p4info.P4Info, typeName *p4info.P4NamedType) bool {
+	return info.GetTypeInfo().GetNewTypes()[typeName.GetName()].GetTranslatedType().GetSdnString() != nil
+}
+
+// Returns the bitwidth of masks of the given match field value; masks of string-translated values cover the
+// whole string, which may be longer than the field bitwidth
+func maskBitwidth(info *p4info.P4Info, mf *p4info.MatchField, value []byte) int32 {
+	if isSdnString(info, mf.GetTypeName()) && int32(8*len(value)) > mf.Bitwidth {
+		return int32(8 * len(value))
+	}
+	return mf.Bitwidth
+}
+
+// Interprets the given value as an arbitrary precision integer
+func valueAsInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int8:
+		return big.NewInt(int64(v)), nil
+	case int16:
+		return big.NewInt(int64(v)), nil
+	case int32:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case []byte:
+		return new(big.Int).SetBytes(v), nil
+	case net.IP:
+		if ip4 := v.To4(); ip4 != nil {
+			return new(big.Int).SetBytes(ip4), nil
+		}
+		return new(big.Int).SetBytes(v), nil
+	case net.HardwareAddr:
+		return new(big.Int).SetBytes(v), nil
+	case string:
+		if ip := net.ParseIP(v); ip != nil {
+			return valueAsInt(ip)
+		}
+		if mac, err := net.ParseMAC(v); err == nil {
+			return valueAsInt(mac)
+		}
+		// Numbers are decimal unless explicitly prefixed as hexadecimal; leading zeros do not denote octal
+		digits, base := v, 10
+		if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+			digits, base = v[2:], 16
+		}
+		if !strings.HasPrefix(digits, "+") && !strings.HasPrefix(digits, "-") {
+			if n, ok := new(big.Int).SetString(digits, base); ok {
+				return n, nil
+			}
+		}
+		return nil, errors.NewInvalid("unable to parse value %q", v)
+	default:
+		return nil, errors.NewInvalid("unsupported value type %T", value)
+	}
+}
+
+// Returns true if the value has no bits set outside of the given mask; both are canonical and the value is no longer
+func bytesEqualMasked(value []byte, mask []byte) bool {
+	offset := len(mask) - len(value)
+	for i := range value {
+		if value[i]&mask[offset+i] != value[i] {
+			return false
+		}
+	}
+	return true
+}
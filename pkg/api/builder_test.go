@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+)
+
+func TestEntityBuilder(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	builder := NewEntityBuilder(info)
+
+	update, err := builder.Table("FabricIngress.stats.flows").
+		Ternary("ipv4_src", "10.0.0.0", "255.0.0.0").
+		Ternary("ipv4_dst", net.ParseIP("10.1.2.0"), []byte{255, 255, 255, 0}).
+		Ternary("ip_proto", 6, 0xff).
+		Exact("ig_port", uint32(260)).
+		Priority(10).
+		Action("FabricIngress.stats.count", Param("flow_id", "0x0102")).
+		Update(p4api.Update_INSERT)
+	assert.NoError(t, err)
+	entry := update.Entity.GetTableEntry()
+	assert.Equal(t, uint32(flowsTable), entry.TableId)
+	assert.Equal(t, int32(10), entry.Priority)
+	assert.Len(t, entry.Match, 4)
+	assert.Equal(t, []byte{10, 0, 0, 0}, entry.Match[0].GetTernary().Value)
+	assert.Equal(t, []byte{255, 0, 0, 0}, entry.Match[0].GetTernary().Mask)
+	assert.Equal(t, []byte{10, 1, 2, 0}, entry.Match[1].GetTernary().Value)
+	assert.Equal(t, []byte{6}, entry.Match[2].GetTernary().Value)
+	assert.Equal(t, uint32(6), entry.Match[3].FieldId)
+	assert.Equal(t, []byte{1, 4}, entry.Match[3].GetExact().Value)
+	assert.Equal(t, uint32(21929788), entry.Action.GetAction().ActionId)
+	assert.Equal(t, []byte{1, 2}, entry.Action.GetAction().Params[0].Value)
+
+	entity, err := builder.Table("FabricIngress.forwarding.bridging").
+		Exact("vlan_id", 0).
+		Ternary("eth_dst", "00:00:00:00:00:01", "ff:ff:ff:ff:ff:ff").
+		Action("FabricIngress.forwarding.set_next_id_bridging", Param("next_id", 5)).
+		Entity()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0}, entity.GetTableEntry().Match[0].GetExact().Value)
+	assert.Equal(t, []byte{1}, entity.GetTableEntry().Match[1].GetTernary().Value)
+	assert.Equal(t, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, entity.GetTableEntry().Match[1].GetTernary().Mask)
+
+	entity, err = builder.Table("FabricIngress.forwarding.routing_v4").LPM("ipv4_dst", "10.0.0.0", 8).Entity()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(8), entity.GetTableEntry().Match[0].GetLpm().PrefixLen)
+
+	entity, err = builder.Table("FabricIngress.forwarding.routing_v4").LPM("ipv4_dst", "10.0.0.1", 32).Entity()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{10, 0, 0, 1}, entity.GetTableEntry().Match[0].GetLpm().Value)
+
+	// Numeric strings are decimal, even with leading zeros, unless prefixed with 0x
+	entity, err = builder.Table("FabricIngress.forwarding.bridging").
+		Exact("vlan_id", "010").
+		Ternary("eth_dst", "0X10", "0xff").
+		Action("FabricIngress.forwarding.set_next_id_bridging", Param("next_id", "0x10")).
+		Entity()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{10}, entity.GetTableEntry().Match[0].GetExact().Value)
+	assert.Equal(t, []byte{16}, entity.GetTableEntry().Match[1].GetTernary().Value)
+	assert.Equal(t, []byte{16}, entity.GetTableEntry().Action.GetAction().Params[0].Value)
+}
+
+func TestEntityBuilderErrors(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	builder := NewEntityBuilder(info)
+
+	cases := []*TableEntryBuilder{
+		builder.Table("FabricIngress.stats.bogus"),
+		builder.Table("FabricIngress.stats.flows").Exact("bogus", 1),
+		builder.Table("FabricIngress.stats.flows").Exact("ipv4_src", 1),
+		builder.Table("FabricIngress.stats.flows").Ternary("ip_proto", 256, 0xff),
+		builder.Table("FabricIngress.stats.flows").Ternary("ipv4_src", "10.0.0.1", "255.0.0.0"),
+		builder.Table("FabricIngress.stats.flows").Ternary("ipv4_src", "bogus", "255.0.0.0"),
+		builder.Table("FabricIngress.stats.flows").Ternary("ip_proto", "0b1", 0xff),
+		builder.Table("FabricIngress.stats.flows").Ternary("ip_proto", "0o7", 0xff),
+		builder.Table("FabricIngress.stats.flows").Ternary("ip_proto", "0x-1", 0xff),
+		builder.Table("FabricIngress.stats.flows").Ternary("ip_proto", "1_0", 0xff),
+		builder.Table("FabricIngress.forwarding.routing_v4").LPM("ipv4_dst", "10.0.0.1", 8),
+		builder.Table("FabricIngress.forwarding.routing_v4").LPM("ipv4_dst", "10.0.0.0", 33),
+		builder.Table("FabricIngress.stats.flows").Action("FabricIngress.bogus"),
+		builder.Table("FabricIngress.stats.flows").Action("FabricIngress.stats.count"),
+		builder.Table("FabricIngress.stats.flows").Action("FabricIngress.stats.count", Param("flow_id", 1), Param("bogus", 1)),
+		builder.Table("FabricIngress.stats.flows").Action("FabricIngress.forwarding.set_next_id_bridging", Param("next_id", 5)),
+	}
+	for i, tb := range cases {
+		_, err = tb.Entity()
+		assert.True(t, errors.IsInvalid(err), "case %d: %+v", i, err)
+	}
+}
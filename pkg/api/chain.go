@@ -0,0 +1,274 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Provenance associates each translated low-level entity, by its index, with the indexes of the
+// high-level entities from which it originated.
+type Provenance [][]int
+
+// TracingTranslator is an abstraction of a pipeline translator capable of reporting which high-level
+// entities each of the translated low-level entities originated from.
+type TracingTranslator interface {
+	PipelineTranslator
+
+	// TranslateWithProvenance translates the given high-level pipeline entities into low-level pipeline ones
+	// and reports the provenance of each of the low-level entities.
+	TranslateWithProvenance(entities *[]p4api.Entity) (*[]p4api.Entity, Provenance, error)
+}
+
+// TranslateWithProvenance translates the given entities using the specified translator and returns the provenance
+// of the translated entities. If the translator is not capable of tracing, a one-to-one correspondence is assumed
+// when the number of entities is preserved; otherwise, each translated entity is attributed to all original ones.
+func TranslateWithProvenance(translator PipelineTranslator, entities *[]p4api.Entity) (*[]p4api.Entity, Provenance, error) {
+	if tracing, ok := translator.(TracingTranslator); ok {
+		return tracing.TranslateWithProvenance(entities)
+	}
+
+	translated, err := translator.Translate(entities)
+	if err != nil {
+		return nil, nil, err
+	}
+	if translated == nil {
+		return nil, Provenance{}, nil
+	}
+
+	provenance := make(Provenance, len(*translated))
+	for i := range provenance {
+		if len(*translated) == len(*entities) {
+			provenance[i] = []int{i}
+		} else {
+			provenance[i] = allIndexes(len(*entities))
+		}
+	}
+	return translated, provenance, nil
+}
+
+func allIndexes(n int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
+
+// Provides translation via a sequence of translators, each feeding its low-level entities to the next one
+type translatorChain struct {
+	TracingTranslator
+	stages []PipelineTranslator
+}
+
+// NewTranslatorChain returns a new translator composed of the given sequence of translators. Returns error if
+// there are no translators or if the low-level pipeline of any translator does not match the high-level
+// pipeline of the translator following it.
+func NewTranslatorChain(stages ...PipelineTranslator) (TracingTranslator, error) {
+	if len(stages) == 0 {
+		return nil, errors.NewInvalid("Translator chain requires at least one translator")
+	}
+	for i := 1; i < len(stages); i++ {
+		if !proto.Equal(stages[i-1].ToPipeline(), stages[i].FromPipeline()) {
+			return nil, errors.NewInvalid("Translator chain stage %d target pipeline does not match stage %d source pipeline", i-1, i)
+		}
+	}
+	return &translatorChain{stages: stages}, nil
+}
+
+// Translate translates the given high-level pipeline entities through all stages of the chain.
+func (t *translatorChain) Translate(entities *[]p4api.Entity) (*[]p4api.Entity, error) {
+	translated, _, err := t.TranslateWithProvenance(entities)
+	return translated, err
+}
+
+// TranslateWithProvenance translates the given high-level pipeline entities through all stages of the chain,
+// composing the provenance of each stage to associate the final entities with the original ones.
+func (t *translatorChain) TranslateWithProvenance(entities *[]p4api.Entity) (*[]p4api.Entity, Provenance, error) {
+	current := entities
+	var provenance Provenance
+	for i, stage := range t.stages {
+		translated, stageProvenance, err := TranslateWithProvenance(stage, current)
+		if err != nil {
+			return nil, nil, errors.New(errors.TypeOf(err), "Translator chain stage %d failed: %s", i, err.Error())
+		}
+		if translated == nil {
+			translated = &[]p4api.Entity{}
+		}
+		provenance = composeProvenance(provenance, stageProvenance)
+		current = translated
+	}
+	return current, provenance, nil
+}
+
+// Composes the provenance of the previous stages with the provenance of the next stage
+func composeProvenance(previous Provenance, next Provenance) Provenance {
+	if previous == nil {
+		return next
+	}
+	composed := make(Provenance, len(next))
+	for i, origins := range next {
+		seen := make(map[int]bool)
+		for _, o := range origins {
+			if o < 0 || o >= len(previous) {
+				continue
+			}
+			for _, po := range previous[o] {
+				if !seen[po] {
+					seen[po] = true
+					composed[i] = append(composed[i], po)
+				}
+			}
+		}
+	}
+	return composed
+}
+
+// Update translator capable of reverting the changes of its record of derived entities made by a translation
+type revertingTranslator interface {
+	UpdateTranslator
+	translateUpdates(updates *[]p4api.Update) (*[]p4api.Update, func(), error)
+}
+
+// TranslateUpdates translates the given high-level pipeline updates through all stages of the chain. Stages keeping
+// a record of derived entities translate the updates incrementally, while the other stages translate the entity of
+// each update on its own, retaining the update type. If any of the stages fails, the records of derived entities of
+// the preceding stages are reverted. Returns a not supported error if any of the stages keeps a record of derived
+// entities which it is not capable of reverting, i.e. for update translators other than those of this package.
+func (t *translatorChain) TranslateUpdates(updates *[]p4api.Update) (*[]p4api.Update, error) {
+	translated, _, err := t.translateUpdates(updates)
+	return translated, err
+}
+
+func (t *translatorChain) translateUpdates(updates *[]p4api.Update) (*[]p4api.Update, func(), error) {
+	for i, stage := range t.stages {
+		if _, ok := stage.(UpdateTranslator); ok {
+			if _, ok := stage.(revertingTranslator); !ok {
+				return nil, nil, errors.NewNotSupported("Translator chain stage %d is not capable of reverting update translation", i)
+			}
+		}
+	}
+
+	reverts := make([]func(), 0, len(t.stages))
+	revert := func() {
+		for j := len(reverts) - 1; j >= 0; j-- {
+			reverts[j]()
+		}
+	}
+	current := updates
+	for i, stage := range t.stages {
+		var translated *[]p4api.Update
+		var err error
+		if reverting, ok := stage.(revertingTranslator); ok {
+			var stageRevert func()
+			if translated, stageRevert, err = reverting.translateUpdates(current); err == nil {
+				reverts = append(reverts, stageRevert)
+			}
+		} else {
+			translated, err = translateEachUpdate(stage, current)
+		}
+		if err != nil {
+			revert()
+			return nil, nil, errors.New(errors.TypeOf(err), "Translator chain stage %d failed: %s", i, err.Error())
+		}
+		current = translated
+	}
+	return current, revert, nil
+}
+
+// Translates the entity of each of the given updates on its own, retaining the type of the update
+func translateEachUpdate(translator PipelineTranslator, updates *[]p4api.Update) (*[]p4api.Update, error) {
+	translated := make([]p4api.Update, 0, len(*updates))
+	for i := range *updates {
+		update := &(*updates)[i]
+		entities, err := translator.Translate(&[]p4api.Entity{{Entity: update.Entity.Entity}})
+		if err != nil {
+			return nil, err
+		}
+		if entities == nil {
+			continue
+		}
+		for j := range *entities {
+			translated = append(translated, p4api.Update{Type: update.Type, Entity: &p4api.Entity{Entity: (*entities)[j].Entity}})
+		}
+	}
+	return &translated, nil
+}
+
+// Derived returns the low-level entities presently derived from the given high-level entity by all stages of the
+// chain; stages not keeping a record of derived entities translate the entities afresh.
+func (t *translatorChain) Derived(entity *p4api.Entity) []*p4api.Entity {
+	current := []*p4api.Entity{entity}
+	for _, stage := range t.stages {
+		next := make([]*p4api.Entity, 0, len(current))
+		for _, e := range current {
+			if updating, ok := stage.(UpdateTranslator); ok {
+				next = append(next, updating.Derived(e)...)
+				continue
+			}
+			translated, err := stage.Translate(&[]p4api.Entity{{Entity: e.Entity}})
+			if err != nil || translated == nil {
+				continue
+			}
+			for i := range *translated {
+				next = append(next, &p4api.Entity{Entity: (*translated)[i].Entity})
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// TranslatePacketOut translates the given high-level packet-out through all stages of the chain.
+func (t *translatorChain) TranslatePacketOut(packetOut *p4api.PacketOut) (*p4api.PacketOut, error) {
+	var err error
+	for i, stage := range t.stages {
+		if packetOut, err = stage.TranslatePacketOut(packetOut); err != nil {
+			return nil, errors.New(errors.TypeOf(err), "Translator chain stage %d failed: %s", i, err.Error())
+		}
+	}
+	return packetOut, nil
+}
+
+// TranslatePacketIn translates the given low-level packet-in through all stages of the chain in reverse order.
+func (t *translatorChain) TranslatePacketIn(packetIn *p4api.PacketIn) (*p4api.PacketIn, error) {
+	var err error
+	for i := len(t.stages) - 1; i >= 0; i-- {
+		if packetIn, err = t.stages[i].TranslatePacketIn(packetIn); err != nil {
+			return nil, errors.New(errors.TypeOf(err), "Translator chain stage %d failed: %s", i, err.Error())
+		}
+	}
+	return packetIn, nil
+}
+
+// TranslateToLogical translates the given low-level pipeline entities through all stages of the chain in reverse
+// order. Returns a not supported error if any of the stages is not capable of inverse translation.
+func (t *translatorChain) TranslateToLogical(entities *[]p4api.Entity) (*[]p4api.Entity, error) {
+	current := entities
+	for i := len(t.stages) - 1; i >= 0; i-- {
+		translated, err := TranslateToLogical(t.stages[i], current)
+		if err != nil {
+			return nil, errors.New(errors.TypeOf(err), "Translator chain stage %d failed: %s", i, err.Error())
+		}
+		if translated == nil {
+			translated = &[]p4api.Entity{}
+		}
+		current = translated
+	}
+	return current, nil
+}
+
+// FromPipeline returns the P4 information describing the high-level pipeline of the first translator.
+func (t *translatorChain) FromPipeline() *p4info.P4Info {
+	return t.stages[0].FromPipeline()
+}
+
+// ToPipeline returns the P4 information describing the low-level target pipeline of the last translator.
+func (t *translatorChain) ToPipeline() *p4info.P4Info {
+	return t.stages[len(t.stages)-1].ToPipeline()
+}
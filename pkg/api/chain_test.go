@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// Translator that duplicates every entity it is given; fails if so configured
+type duplicatingTranslator struct {
+	PipelineTranslator
+	fail bool
+}
+
+func (t *duplicatingTranslator) Translate(entities *[]p4api.Entity) (*[]p4api.Entity, error) {
+	if t.fail {
+		return nil, errors.NewNotSupported("unsupported entity")
+	}
+	translated := make([]p4api.Entity, 0, 2*len(*entities))
+	for i := range *entities {
+		translated = append(translated, p4api.Entity{Entity: (*entities)[i].Entity}, p4api.Entity{Entity: (*entities)[i].Entity})
+	}
+	return &translated, nil
+}
+
+// Traces the duplication, i.e. entities 2i and 2i+1 originate from entity i
+func (t *duplicatingTranslator) TranslateWithProvenance(entities *[]p4api.Entity) (*[]p4api.Entity, Provenance, error) {
+	translated, err := t.Translate(entities)
+	if err != nil {
+		return nil, nil, err
+	}
+	provenance := make(Provenance, len(*translated))
+	for i := range provenance {
+		provenance[i] = []int{i / 2}
+	}
+	return translated, provenance, nil
+}
+
+func testEntities(n int) *[]p4api.Entity {
+	entities := make([]p4api.Entity, n)
+	for i := range entities {
+		entities[i].Entity = &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{TableId: uint32(i + 1)}}
+	}
+	return &entities
+}
+
+func TestTranslatorChain(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	chain, err := NewTranslatorChain(NewIdentityTranslator(info),
+		&duplicatingTranslator{PipelineTranslator: NewIdentityTranslator(info)},
+		&duplicatingTranslator{PipelineTranslator: NewIdentityTranslator(info)})
+	assert.NoError(t, err)
+	assert.Same(t, info, chain.FromPipeline())
+	assert.Same(t, info, chain.ToPipeline())
+
+	translated, provenance, err := chain.TranslateWithProvenance(testEntities(3))
+	assert.NoError(t, err)
+	assert.Len(t, *translated, 12)
+	assert.Len(t, provenance, 12)
+	for i, origins := range provenance {
+		assert.Equal(t, []int{i / 4}, origins)
+		assert.Equal(t, uint32(i/4+1), (*translated)[i].GetTableEntry().TableId)
+	}
+}
+
+func TestTranslatorChainErrors(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	_, err = NewTranslatorChain()
+	assert.True(t, errors.IsInvalid(err))
+
+	_, err = NewTranslatorChain(NewIdentityTranslator(info), NewIdentityTranslator(&p4info.P4Info{}))
+	assert.True(t, errors.IsInvalid(err))
+
+	chain, err := NewTranslatorChain(NewIdentityTranslator(info),
+		&duplicatingTranslator{PipelineTranslator: NewIdentityTranslator(info), fail: true})
+	assert.NoError(t, err)
+	_, err = chain.Translate(testEntities(3))
+	assert.True(t, errors.IsNotSupported(err))
+	assert.Contains(t, err.Error(), "stage 1")
+}
+
+func TestUntracedProvenance(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	_, provenance, err := TranslateWithProvenance(NewIdentityTranslator(info), testEntities(3))
+	assert.NoError(t, err)
+	assert.Equal(t, Provenance{{0}, {1}, {2}}, provenance)
+}
+
+func TestTranslatorChainToLogical(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	chain, err := NewTranslatorChain(NewIdentityTranslator(info), NewIdentityTranslator(info))
+	assert.NoError(t, err)
+	logical, err := TranslateToLogical(chain, testEntities(3))
+	assert.NoError(t, err)
+	assert.Len(t, *logical, 3)
+
+	// The duplicating translator is not capable of inverse translation
+	chain, err = NewTranslatorChain(NewIdentityTranslator(info), &duplicatingTranslator{PipelineTranslator: NewIdentityTranslator(info)})
+	assert.NoError(t, err)
+	_, err = TranslateToLogical(chain, testEntities(3))
+	assert.True(t, errors.IsNotSupported(err))
+}
+
+func TestTranslatorChainUpdates(t *testing.T) {
+	mapping, info := newTestMappingTranslator(t)
+	duplicating := &duplicatingTranslator{PipelineTranslator: NewIdentityTranslator(info)}
+	chain, err := NewTranslatorChain(mapping, duplicating)
+	assert.NoError(t, err)
+	updating, ok := chain.(UpdateTranslator)
+	assert.True(t, ok)
+
+	inserts := []*p4api.Update{
+		generateUpdate(info, portVlanTable, 24158268, p4api.Update_INSERT),
+		generateUpdate(info, bridgingTable, bridgingAction, p4api.Update_INSERT),
+	}
+	translated, err := updating.TranslateUpdates(asUpdates(inserts...))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, countByTable(translated, p4api.Update_INSERT)[aclTable])
+	assert.Len(t, updating.Derived(inserts[0].Entity), 2)
+
+	// Failure of the later stage must revert the record of the mapping stage
+	duplicating.fail = true
+	update := generateUpdate(info, bridgingTable, bridgingAction, p4api.Update_INSERT)
+	_, err = updating.TranslateUpdates(asUpdates(update))
+	assert.True(t, errors.IsNotSupported(err))
+	assert.Contains(t, err.Error(), "stage 1")
+	assert.Len(t, mapping.Derived(update.Entity), 0)
+	assert.Len(t, mapping.Derived(inserts[0].Entity), 1)
+
+	// Update translators of other packages are not capable of reverting their record
+	chain, err = NewTranslatorChain(&foreignTranslator{UpdateTranslator: mapping}, NewIdentityTranslator(info))
+	assert.NoError(t, err)
+	_, err = chain.(UpdateTranslator).TranslateUpdates(asUpdates(update))
+	assert.True(t, errors.IsNotSupported(err))
+}
+
+// Update translator hiding the reverting capability of the translator it wraps
+type foreignTranslator struct {
+	UpdateTranslator
+}
@@ -0,0 +1,19 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "time"
+
+// Checkpoint describes a named checkpoint of the logical intent of a device
+type Checkpoint struct {
+	// Name is the name of the checkpoint, unique for the device
+	Name string `json:"name"`
+	// Created is the time the checkpoint was taken
+	Created time.Time `json:"created"`
+	// Entities is the number of logical entities captured by the checkpoint
+	Entities int `json:"entities"`
+	// Size is the size of the checkpoint in bytes
+	Size int `json:"size"`
+}
@@ -36,12 +36,25 @@ type DeviceControl interface {
 	// State returns the current state of the controller
 	State() State
 
-	// Read receives a query and returns back all requested control entries on the given channel
-	Read(ctx context.Context, entities *[]p4api.Entity, ch chan<- []*p4api.Entity) error
+	// Read receives a query and returns an iterator over all requested control entries; the entries are
+	// produced as they are consumed and any errors are reported by the iterator for each of the queries
+	Read(ctx context.Context, entities *[]p4api.Entity) EntityIterator
 
-	// Write applies a set of updates to the device
+	// Write applies a set of updates to the device transactionally; the translated changes are applied to the
+	// device and the updates are persisted in the store, or neither of them takes effect. Only table entries are
+	// supported; updates of other entities fail with a NotSupported error.
 	Write(ctx context.Context, request *[]p4api.Update) error
 
+	// WriteIntent applies a set of updates to the device transactionally, like Write, associating the inserted and
+	// modified entities with the given intent
+	WriteIntent(ctx context.Context, intent *Intent, request *[]p4api.Update) error
+
+	// ReadIntent returns an iterator over all control entries belonging to the given intent
+	ReadIntent(ctx context.Context, id string) EntityIterator
+
+	// DeleteIntent deletes all control entries belonging to the given intent as a single transactional write
+	DeleteIntent(ctx context.Context, id string) error
+
 	// EmitPacket requests emission of the specified packet onto the data-plane
 	EmitPacket(ctx context.Context, packetOut *p4api.PacketOut) error
 
@@ -54,8 +67,56 @@ type DeviceControl interface {
 	// Version returns the P4Runtime version of the target
 	Version() string
 
+	// SetTranslator replaces the pipeline translator used for the device, e.g. when the physical pipeline changes.
+	// All physical entities are re-derived from the persisted logical intent and the difference is applied to the
+	// device in make-before-break fashion. If the new translation fails, any partially applied changes are rolled
+	// back and the original translator remains in effect.
+	SetTranslator(ctx context.Context, translator PipelineTranslator) error
+
+	// Checkpoint captures the current logical intent of the device under the given name, for later rollback
+	Checkpoint(ctx context.Context, name string) (*Checkpoint, error)
+
+	// Checkpoints returns descriptors of all checkpoints of the device, ordered by their creation time
+	Checkpoints(ctx context.Context) ([]*Checkpoint, error)
+
+	// DeleteCheckpoint deletes the given checkpoint
+	DeleteCheckpoint(ctx context.Context, name string) error
+
+	// Rollback restores the logical intent captured by the given checkpoint; the difference from the current intent
+	// is applied as a single transactional write
+	Rollback(ctx context.Context, name string) error
+
+	// NewSession starts a new session for staging updates, which are applied to the device only once committed
+	NewSession() Session
+
+	// Drift reads the device and reports how its entities differ from those derived from the logical intent, i.e.
+	// which are missing, unexpected or differing in some of their fields; no changes are made to the device
+	Drift(ctx context.Context) (*DriftReport, error)
+
+	// Reconcile brings the entities of the device in line with those derived from the logical intent, treating the
+	// entries of each table according to its reconciliation policy, and reports the outcome per table
+	Reconcile(ctx context.Context) (*ReconcileReport, error)
+
 	// TODO: Add means for application to watch the state?
-	// TODO: Consider changing the read to use iterator pattern rather than a channel
+}
+
+// Session is an abstraction of an entity collecting updates of the logical intent of a device, which are applied
+// to the device and persisted all at once when committed, or not at all
+type Session interface {
+	// Stage validates the given updates against the pipeline and the logical intent, as changed by the updates
+	// staged so far, and adds them to the session
+	Stage(ctx context.Context, request *[]p4api.Update) error
+
+	// Diff returns the pending changes of the logical intent, i.e. the net effect of the staged updates on the
+	// current logical intent, which will be applied when the session is committed
+	Diff(ctx context.Context) ([]*p4api.Update, error)
+
+	// Commit applies the pending changes as a single transactional write and closes the session; the session remains
+	// open if the commit fails
+	Commit(ctx context.Context) error
+
+	// Discard drops all staged updates and closes the session
+	Discard()
 }
 
 // PacketHandler is an abstraction of an entity capable of handling an incoming packet-in
@@ -64,11 +125,30 @@ type PacketHandler interface {
 	Handle(packetIn *p4api.PacketIn) error
 }
 
+// AddOptions are the options of creation of a device control context
+type AddOptions struct {
+	// Adopt requests adoption of the entities present on the device into the store on first attach
+	Adopt bool
+}
+
+// AddOption sets an option of creation of a device control context
+type AddOption func(options *AddOptions)
+
+// WithAdoption requests that on first attach to the device, i.e. while no logical intent is persisted for it, the
+// table entries present on the device within the scope of the role are read, translated into logical entities where
+// possible and persisted as the logical intent, so that the reconciliation preserves the state of the device; other
+// entities are not adopted, as the store does not persist them
+func WithAdoption() AddOption {
+	return func(options *AddOptions) {
+		options.Adopt = true
+	}
+}
+
 // Devices is an abstraction of an entity capable of tracking device control contexts
 // of multiple devices on behalf of the control application.
 type Devices interface {
 	// Add requests creation of a new device flow control context using its P4Runtime connection endpoint
-	Add(ctx context.Context, id topo.ID, p4rtEndpoint string, translator PipelineTranslator) (DeviceControl, error)
+	Add(ctx context.Context, id topo.ID, p4rtEndpoint string, translator PipelineTranslator, opts ...AddOption) (DeviceControl, error)
 
 	// Remove requests removal of device control context
 	Remove(id topo.ID)
@@ -0,0 +1,230 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"sort"
+)
+
+// DriftReport describes how the entities present on a device differ from the entities derived from its intent
+type DriftReport struct {
+	// Missing are the entities derived from the intent which are not present on the device
+	Missing []*EntityDrift
+	// Unexpected are the entities present on the device which are not derived from the intent
+	Unexpected []*EntityDrift
+	// Differing are the entities present on the device whose attributes differ from those derived from the intent
+	Differing []*EntityDrift
+}
+
+// InSync returns true if the report contains no missing, unexpected or differing entities
+func (r *DriftReport) InSync() bool {
+	return len(r.Missing) == 0 && len(r.Unexpected) == 0 && len(r.Differing) == 0
+}
+
+// EntityDrift describes a single entity which is missing on the device, unexpected or differing
+type EntityDrift struct {
+	// Text is the entity rendered using the names of the P4 objects, as the device has it if it is unexpected and
+	// as expected otherwise
+	Text string
+	// Expected is the entity derived from the intent; nil if the entity is unexpected
+	Expected *p4api.Entity
+	// Actual is the entity present on the device; nil if the entity is missing
+	Actual *p4api.Entity
+	// Fields are the differing attributes of the entity; empty unless the entity is differing
+	Fields []*FieldDrift
+}
+
+// FieldDrift describes a single differing attribute of an entity
+type FieldDrift struct {
+	// Field is the name of the attribute, e.g. action or action.next_id for a parameter of the action
+	Field string
+	// Expected is the rendered value derived from the intent; empty if the attribute is not expected to be set
+	Expected string
+	// Actual is the rendered value present on the device; empty if the attribute is not set on the device
+	Actual string
+}
+
+// Attributes of table entries reflecting the state of the device rather than its configuration, which are
+// disregarded when looking for drift
+var tableEntryStateFields = map[protoreflect.Name]bool{
+	"counter_data":        true,
+	"meter_counter_data":  true,
+	"time_since_last_hit": true,
+}
+
+// NewDriftReport compares the entities expected on the device with the ones actually read from it, rendering any
+// differences using the names of the P4 objects defined by the pipeline info. Values are compared in their canonical
+// form, i.e. without leading zero bytes, and attributes reflecting the state of the device, e.g. the counter data of
+// table entries, are disregarded.
+func NewDriftReport(info *p4info.P4Info, expected []*p4api.Entity, actual []*p4api.Entity) *DriftReport {
+	present := make(map[string]*p4api.Entity, len(actual))
+	for _, e := range actual {
+		present[EntityKey(canonicalEntity(e))] = e
+	}
+
+	report := &DriftReport{}
+	for _, e := range expected {
+		ce := canonicalEntity(e)
+		key := EntityKey(ce)
+		a, ok := present[key]
+		if !ok {
+			report.Missing = append(report.Missing, &EntityDrift{Text: FormatEntity(info, e), Expected: e})
+			continue
+		}
+		delete(present, key)
+		if fields := diffFields(info, ce, canonicalEntity(a)); len(fields) > 0 {
+			report.Differing = append(report.Differing, &EntityDrift{Text: FormatEntity(info, e), Expected: e, Actual: a, Fields: fields})
+		}
+	}
+	for _, a := range actual {
+		if _, ok := present[EntityKey(canonicalEntity(a))]; ok {
+			report.Unexpected = append(report.Unexpected, &EntityDrift{Text: FormatEntity(info, a), Actual: a})
+		}
+	}
+
+	for _, drifts := range [][]*EntityDrift{report.Missing, report.Unexpected, report.Differing} {
+		sort.SliceStable(drifts, func(i, j int) bool { return drifts[i].Text < drifts[j].Text })
+	}
+	return report
+}
+
+// Returns a copy of the entity with the values of table entry matches and action parameters in canonical form
+func canonicalEntity(entity *p4api.Entity) *p4api.Entity {
+	if entity.GetTableEntry() == nil {
+		return entity
+	}
+	entity = proto.Clone(entity).(*p4api.Entity)
+	entry := entity.GetTableEntry()
+	for _, m := range entry.Match {
+		switch fm := m.FieldMatchType.(type) {
+		case *p4api.FieldMatch_Exact_:
+			fm.Exact.Value = canonicalValue(fm.Exact.Value)
+		case *p4api.FieldMatch_Ternary_:
+			fm.Ternary.Value, fm.Ternary.Mask = canonicalValue(fm.Ternary.Value), canonicalValue(fm.Ternary.Mask)
+		case *p4api.FieldMatch_Lpm:
+			fm.Lpm.Value = canonicalValue(fm.Lpm.Value)
+		case *p4api.FieldMatch_Range_:
+			fm.Range.Low, fm.Range.High = canonicalValue(fm.Range.Low), canonicalValue(fm.Range.High)
+		case *p4api.FieldMatch_Optional_:
+			fm.Optional.Value = canonicalValue(fm.Optional.Value)
+		}
+	}
+	actions := []*p4api.Action{entry.Action.GetAction()}
+	for _, pa := range entry.Action.GetActionProfileActionSet().GetActionProfileActions() {
+		actions = append(actions, pa.Action)
+	}
+	for _, action := range actions {
+		for _, p := range action.GetParams() {
+			p.Value = canonicalValue(p.Value)
+		}
+	}
+	return entity
+}
+
+// Returns the differing attributes of the two entities having the same key
+func diffFields(info *p4info.P4Info, expected *p4api.Entity, actual *p4api.Entity) []*FieldDrift {
+	if expected.GetTableEntry() == nil {
+		return diffMessages(expected.ProtoReflect(), actual.ProtoReflect(), nil)
+	}
+	e, a := expected.GetTableEntry(), actual.GetTableEntry()
+	fields := diffTableActions(info, e.Action, a.Action)
+	ignored := map[protoreflect.Name]bool{"table_id": true, "match": true, "priority": true, "is_default_action": true, "action": true}
+	for name := range tableEntryStateFields {
+		ignored[name] = true
+	}
+	return append(fields, diffMessages(e.ProtoReflect(), a.ProtoReflect(), ignored)...)
+}
+
+// Returns the differences of the table actions; parameters of the same action are compared one by one
+func diffTableActions(info *p4info.P4Info, expected *p4api.TableAction, actual *p4api.TableAction) []*FieldDrift {
+	if proto.Equal(expected, actual) {
+		return nil
+	}
+	e, a := expected.GetAction(), actual.GetAction()
+	ai := FindActionByID(info, e.GetActionId())
+	if e == nil || a == nil || e.ActionId != a.ActionId || ai == nil {
+		return []*FieldDrift{{Field: "action", Expected: formatTableAction(info, expected), Actual: formatTableAction(info, actual)}}
+	}
+
+	fields := make([]*FieldDrift, 0)
+	for _, p := range ai.Params {
+		ev, av := paramValue(e, p.Id), paramValue(a, p.Id)
+		if bytes.Equal(ev, av) {
+			continue
+		}
+		format := func(value []byte) string {
+			if value == nil {
+				return ""
+			}
+			return formatValue(info, p.Name, p.GetTypeName(), p.Bitwidth, value)
+		}
+		fields = append(fields, &FieldDrift{Field: "action." + p.Name, Expected: format(ev), Actual: format(av)})
+	}
+	if len(fields) == 0 {
+		// The actions differ in parameters unknown to the pipeline
+		fields = append(fields, &FieldDrift{Field: "action", Expected: formatTableAction(info, expected), Actual: formatTableAction(info, actual)})
+	}
+	return fields
+}
+
+func paramValue(action *p4api.Action, id uint32) []byte {
+	for _, p := range action.Params {
+		if p.ParamId == id {
+			return p.Value
+		}
+	}
+	return nil
+}
+
+// Returns the differences of the corresponding fields of the two messages, except for the ignored ones
+func diffMessages(expected protoreflect.Message, actual protoreflect.Message, ignored map[protoreflect.Name]bool) []*FieldDrift {
+	fields := make([]*FieldDrift, 0)
+	descriptors := expected.Descriptor().Fields()
+	for i := 0; i < descriptors.Len(); i++ {
+		fd := descriptors.Get(i)
+		if ignored[fd.Name()] {
+			continue
+		}
+		e, a := fieldOf(expected, fd), fieldOf(actual, fd)
+		if proto.Equal(e.Interface(), a.Interface()) {
+			continue
+		}
+		fields = append(fields, &FieldDrift{Field: string(fd.Name()), Expected: formatField(e, fd), Actual: formatField(a, fd)})
+	}
+	return fields
+}
+
+// Returns a message of the same type holding only the given field of the message
+func fieldOf(m protoreflect.Message, fd protoreflect.FieldDescriptor) protoreflect.Message {
+	field := m.New()
+	if m.Has(fd) {
+		field.Set(fd, m.Get(fd))
+	}
+	return field
+}
+
+// Renders the value of the only field of the given message; empty if the field is not set
+func formatField(m protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	if !m.Has(fd) {
+		return ""
+	}
+	switch {
+	case fd.IsList(), fd.IsMap():
+		return prototext.MarshalOptions{}.Format(m.Interface())
+	case fd.Kind() == protoreflect.MessageKind:
+		return prototext.MarshalOptions{}.Format(m.Get(fd).Message().Interface())
+	case fd.Kind() == protoreflect.BytesKind:
+		return fmt.Sprintf("0x%x", m.Get(fd).Bytes())
+	default:
+		return fmt.Sprint(m.Get(fd).Interface())
+	}
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDriftReport(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	parse := func(texts ...string) []*p4api.Entity {
+		entities := make([]*p4api.Entity, 0, len(texts))
+		for _, text := range texts {
+			entity, err := ParseEntity(info, text)
+			assert.NoError(t, err, text)
+			entities = append(entities, entity)
+		}
+		return entities
+	}
+
+	expected := parse(
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0/8 -> set_next_id_routing_v4(next_id=5)",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.1.0.0/16 -> set_next_id_routing_v4(next_id=6)",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.2.0.0/16 -> set_next_id_routing_v4(next_id=7)",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.3.0.0/16 -> set_next_id_routing_v4(next_id=8)",
+	)
+	actual := parse(
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0/8 -> set_next_id_routing_v4(next_id=5)",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.1.0.0/16 -> set_next_id_routing_v4(next_id=9)",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.3.0.0/16 -> nop_routing_v4()",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=192.168.0.0/16 -> set_next_id_routing_v4(next_id=5)",
+	)
+
+	// Values differing only in their leading zeros and state of the device must not be reported
+	actual[0].GetTableEntry().Match[0].GetLpm().Value = append([]byte{0}, actual[0].GetTableEntry().Match[0].GetLpm().Value...)
+	actual[0].GetTableEntry().CounterData = &p4api.CounterData{PacketCount: 42}
+	actual[1].GetTableEntry().Metadata = []byte{1}
+
+	assert.True(t, NewDriftReport(info, expected, expected).InSync())
+	report := NewDriftReport(info, expected, actual)
+	assert.False(t, report.InSync())
+
+	if assert.Len(t, report.Missing, 1) {
+		assert.Equal(t, "FabricIngress.forwarding.routing_v4 ipv4_dst=10.2.0.0/16 -> set_next_id_routing_v4(next_id=7)", report.Missing[0].Text)
+		assert.Same(t, expected[2], report.Missing[0].Expected)
+		assert.Nil(t, report.Missing[0].Actual)
+	}
+	if assert.Len(t, report.Unexpected, 1) {
+		assert.Equal(t, "FabricIngress.forwarding.routing_v4 ipv4_dst=192.168.0.0/16 -> set_next_id_routing_v4(next_id=5)", report.Unexpected[0].Text)
+		assert.Same(t, actual[3], report.Unexpected[0].Actual)
+	}
+	if assert.Len(t, report.Differing, 2) {
+		assert.Same(t, actual[1], report.Differing[0].Actual)
+		assert.Equal(t, []*FieldDrift{
+			{Field: "action.next_id", Expected: "6", Actual: "9"},
+			{Field: "metadata", Expected: "", Actual: "0x01"},
+		}, report.Differing[0].Fields)
+		assert.Equal(t, []*FieldDrift{
+			{Field: "action", Expected: "set_next_id_routing_v4(next_id=8)", Actual: "nop_routing_v4()"},
+		}, report.Differing[1].Fields)
+	}
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/proto"
+	"sort"
+)
+
+// EntityKey returns a key uniquely identifying the given entity within a device, disregarding its non-key attributes.
+func EntityKey(entity *p4api.Entity) string {
+	key := &p4api.Entity{}
+	switch {
+	case entity.GetTableEntry() != nil:
+		key.Entity = &p4api.Entity_TableEntry{TableEntry: tableEntryKey(entity.GetTableEntry())}
+	case entity.GetCounterEntry() != nil:
+		e := entity.GetCounterEntry()
+		key.Entity = &p4api.Entity_CounterEntry{CounterEntry: &p4api.CounterEntry{CounterId: e.CounterId, Index: e.Index}}
+	case entity.GetDirectCounterEntry() != nil:
+		e := entity.GetDirectCounterEntry()
+		key.Entity = &p4api.Entity_DirectCounterEntry{DirectCounterEntry: &p4api.DirectCounterEntry{TableEntry: tableEntryKey(e.TableEntry)}}
+	case entity.GetMeterEntry() != nil:
+		e := entity.GetMeterEntry()
+		key.Entity = &p4api.Entity_MeterEntry{MeterEntry: &p4api.MeterEntry{MeterId: e.MeterId, Index: e.Index}}
+	case entity.GetDirectMeterEntry() != nil:
+		e := entity.GetDirectMeterEntry()
+		key.Entity = &p4api.Entity_DirectMeterEntry{DirectMeterEntry: &p4api.DirectMeterEntry{TableEntry: tableEntryKey(e.TableEntry)}}
+	case entity.GetActionProfileMember() != nil:
+		e := entity.GetActionProfileMember()
+		key.Entity = &p4api.Entity_ActionProfileMember{ActionProfileMember: &p4api.ActionProfileMember{ActionProfileId: e.ActionProfileId, MemberId: e.MemberId}}
+	case entity.GetActionProfileGroup() != nil:
+		e := entity.GetActionProfileGroup()
+		key.Entity = &p4api.Entity_ActionProfileGroup{ActionProfileGroup: &p4api.ActionProfileGroup{ActionProfileId: e.ActionProfileId, GroupId: e.GroupId}}
+	case entity.GetPacketReplicationEngineEntry().GetMulticastGroupEntry() != nil:
+		e := entity.GetPacketReplicationEngineEntry().GetMulticastGroupEntry()
+		key.Entity = &p4api.Entity_PacketReplicationEngineEntry{PacketReplicationEngineEntry: &p4api.PacketReplicationEngineEntry{
+			Type: &p4api.PacketReplicationEngineEntry_MulticastGroupEntry{MulticastGroupEntry: &p4api.MulticastGroupEntry{MulticastGroupId: e.MulticastGroupId}}}}
+	case entity.GetPacketReplicationEngineEntry().GetCloneSessionEntry() != nil:
+		e := entity.GetPacketReplicationEngineEntry().GetCloneSessionEntry()
+		key.Entity = &p4api.Entity_PacketReplicationEngineEntry{PacketReplicationEngineEntry: &p4api.PacketReplicationEngineEntry{
+			Type: &p4api.PacketReplicationEngineEntry_CloneSessionEntry{CloneSessionEntry: &p4api.CloneSessionEntry{SessionId: e.SessionId}}}}
+	default:
+		key = entity
+	}
+	bytes, _ := proto.MarshalOptions{Deterministic: true}.Marshal(key)
+	return string(bytes)
+}
+
+// Returns a copy of the table entry with only its key attributes, with field matches in canonical order
+func tableEntryKey(entry *p4api.TableEntry) *p4api.TableEntry {
+	if entry == nil {
+		return nil
+	}
+	matches := make([]*p4api.FieldMatch, len(entry.Match))
+	copy(matches, entry.Match)
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].FieldId < matches[j].FieldId })
+	return &p4api.TableEntry{
+		TableId:         entry.TableId,
+		Match:           matches,
+		Priority:        entry.Priority,
+		IsDefaultAction: entry.IsDefaultAction,
+	}
+}
@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+// Intent identifies the application intent to which entities belong, e.g. all entries programmed for a host or a
+// tenant, allowing them to be read and deleted together
+type Intent struct {
+	// ID is the identifier of the intent, unique within the device
+	ID string `json:"id"`
+	// Labels are arbitrary attributes of the intent
+	Labels map[string]string `json:"labels,omitempty"`
+}
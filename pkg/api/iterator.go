@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"sync"
+)
+
+// EntityIterator provides pull-based iteration over the entities produced by a read of one or more queries.
+// Entities are produced only as they are consumed, and the iteration stops when the context is cancelled or
+// the iterator is closed.
+type EntityIterator interface {
+	// Next advances to the next entity, blocking until it is available; returns false when there are no more
+	// entities, either because all queries have been completed or because the iteration has been cut short
+	Next() bool
+
+	// Entity returns the current entity
+	Entity() *p4api.Entity
+
+	// Query returns the index of the query matched by the current entity
+	Query() int
+
+	// Revision returns the revision of the current entity, or zero if its source does not track revisions
+	Revision() Revision
+
+	// Err returns the first error encountered by any of the queries, if any; valid once Next returned false
+	Err() error
+
+	// Errors returns the errors encountered by the individual queries, indexed in the same way as the queries;
+	// valid once Next returned false
+	Errors() []error
+
+	// Close stops the iteration and releases any resources held by it
+	Close()
+}
+
+// Revision is the revision of a persisted entity; it changes with each update of the entity
+type Revision uint64
+
+// QueryReader reads entities matching the query with the given index, emitting each of them, along with its
+// revision if known, via the supplied function. The emit function blocks until the entity is consumed and returns
+// error if the iteration has been cancelled or closed, in which case the reader should return promptly.
+type QueryReader func(ctx context.Context, query int, emit func(entity *p4api.Entity, revision Revision) error) error
+
+type queryResult struct {
+	entity   *p4api.Entity
+	revision Revision
+	query    int
+}
+
+type entityIterator struct {
+	cancel  context.CancelFunc
+	results chan queryResult
+	current queryResult
+	errs    []error
+	once    sync.Once
+}
+
+// NewEntityIterator returns an iterator over the entities produced by the given reader for the specified number
+// of queries. The queries are read in order, each only as fast as its entities are consumed.
+func NewEntityIterator(ctx context.Context, queries int, reader QueryReader) EntityIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &entityIterator{
+		cancel:  cancel,
+		results: make(chan queryResult),
+		errs:    make([]error, queries),
+	}
+	go it.produce(ctx, reader)
+	return it
+}
+
+func (it *entityIterator) produce(ctx context.Context, reader QueryReader) {
+	defer close(it.results)
+	for i := range it.errs {
+		if err := ctx.Err(); err != nil {
+			it.errs[i] = err
+			continue
+		}
+		it.errs[i] = reader(ctx, i, func(entity *p4api.Entity, revision Revision) error {
+			select {
+			case it.results <- queryResult{entity: entity, revision: revision, query: i}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}
+}
+
+// Next advances to the next entity, blocking until it is available
+func (it *entityIterator) Next() bool {
+	result, ok := <-it.results
+	it.current = result
+	return ok
+}
+
+// Entity returns the current entity
+func (it *entityIterator) Entity() *p4api.Entity {
+	return it.current.entity
+}
+
+// Query returns the index of the query matched by the current entity
+func (it *entityIterator) Query() int {
+	return it.current.query
+}
+
+// Revision returns the revision of the current entity
+func (it *entityIterator) Revision() Revision {
+	return it.current.revision
+}
+
+// Err returns the first error encountered by any of the queries, if any
+func (it *entityIterator) Err() error {
+	for _, err := range it.errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Errors returns the errors encountered by the individual queries
+func (it *entityIterator) Errors() []error {
+	return it.errs
+}
+
+// Close stops the iteration, waiting for the reader to wind down
+func (it *entityIterator) Close() {
+	it.once.Do(func() {
+		it.cancel()
+		for range it.results {
+		}
+	})
+}
+
+// ReadAll consumes all entities from the given iterator and closes it. Returns the first error encountered by
+// any of the queries, if any.
+func ReadAll(it EntityIterator) ([]*p4api.Entity, error) {
+	defer it.Close()
+	entities := make([]*p4api.Entity, 0)
+	for it.Next() {
+		entities = append(entities, it.Entity())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
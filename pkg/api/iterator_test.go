@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+)
+
+func entityWithID(id uint32) *p4api.Entity {
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{TableId: id}}}
+}
+
+// Returns a reader producing the given number of entities per query, failing the second query after its first entity
+func countingReader(count int, produced *int32) QueryReader {
+	return func(ctx context.Context, query int, emit func(entity *p4api.Entity, revision Revision) error) error {
+		for i := 0; i < count; i++ {
+			if query == 1 && i == 1 {
+				return errors.NewNotFound("query %d failed", query)
+			}
+			atomic.AddInt32(produced, 1)
+			if err := emit(entityWithID(uint32(query*100+i)), Revision(i+1)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestEntityIterator(t *testing.T) {
+	var produced int32
+	it := NewEntityIterator(context.Background(), 3, countingReader(5, &produced))
+
+	queries := make([]int, 0)
+	for it.Next() {
+		queries = append(queries, it.Query())
+		assert.Equal(t, uint32(it.Query()), it.Entity().GetTableEntry().TableId/100)
+		assert.Equal(t, Revision(it.Entity().GetTableEntry().TableId%100+1), it.Revision())
+	}
+	assert.Equal(t, []int{0, 0, 0, 0, 0, 1, 2, 2, 2, 2, 2}, queries)
+	assert.True(t, errors.IsNotFound(it.Err()))
+	assert.NoError(t, it.Errors()[0])
+	assert.True(t, errors.IsNotFound(it.Errors()[1]))
+	assert.NoError(t, it.Errors()[2])
+	it.Close()
+
+	_, err := ReadAll(NewEntityIterator(context.Background(), 2, countingReader(5, &produced)))
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestEntityIteratorBackpressure(t *testing.T) {
+	var produced int32
+	it := NewEntityIterator(context.Background(), 1, countingReader(1000, &produced))
+	for i := 0; i < 10; i++ {
+		assert.True(t, it.Next())
+	}
+
+	// The reader cannot get ahead of the consumer by more than the entity it is trying to emit
+	assert.LessOrEqual(t, atomic.LoadInt32(&produced), int32(11))
+	it.Close()
+	assert.False(t, it.Next())
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+}
+
+func TestEntityIteratorCancel(t *testing.T) {
+	var produced int32
+	ctx, cancel := context.WithCancel(context.Background())
+	it := NewEntityIterator(ctx, 3, countingReader(1000, &produced))
+	assert.True(t, it.Next())
+	cancel()
+
+	for it.Next() {
+	}
+	for _, err := range it.Errors() {
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+	it.Close()
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+)
+
+// FindTableByID returns the table with the given ID; nil if there is no such table
+func FindTableByID(info *p4info.P4Info, id uint32) *p4info.Table {
+	for _, table := range info.GetTables() {
+		if table.Preamble.Id == id {
+			return table
+		}
+	}
+	return nil
+}
+
+// FindMatchFieldByID returns the match field of the table with the given ID; nil if there is no such field
+func FindMatchFieldByID(table *p4info.Table, id uint32) *p4info.MatchField {
+	for _, field := range table.GetMatchFields() {
+		if field.Id == id {
+			return field
+		}
+	}
+	return nil
+}
+
+// FindActionByID returns the action with the given ID; nil if there is no such action
+func FindActionByID(info *p4info.P4Info, id uint32) *p4info.Action {
+	for _, action := range info.GetActions() {
+		if action.Preamble.Id == id {
+			return action
+		}
+	}
+	return nil
+}
+
+// FindParamByID returns the parameter of the action with the given ID; nil if there is no such parameter
+func FindParamByID(action *p4info.Action, id uint32) *p4info.Action_Param {
+	for _, param := range action.GetParams() {
+		if param.Id == id {
+			return param
+		}
+	}
+	return nil
+}
+
+// HasActionRef returns true if the table refers to the action with the given ID, i.e. its entries may use the action
+func HasActionRef(table *p4info.Table, id uint32) bool {
+	for _, ref := range table.GetActionRefs() {
+		if ref.Id == id {
+			return true
+		}
+	}
+	return false
+}
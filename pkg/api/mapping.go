@@ -0,0 +1,504 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/proto"
+	"sort"
+	"sync"
+)
+
+// UpdateTranslator is an abstraction of a pipeline translator which remembers the low-level entities it derived
+// from each high-level entity, and is therefore capable of translating high-level updates, including modifications
+// and deletions, into the corresponding low-level updates.
+type UpdateTranslator interface {
+	PipelineTranslator
+
+	// TranslateUpdates translates the given high-level pipeline updates into low-level pipeline ones, updating the
+	// record of derived entities. If an error is returned, the record of derived entities remains unchanged.
+	TranslateUpdates(updates *[]p4api.Update) (*[]p4api.Update, error)
+
+	// Derived returns the low-level entities presently derived from the given high-level entity.
+	Derived(entity *p4api.Entity) []*p4api.Entity
+}
+
+// TableProjection describes how the entries of a logical table are projected onto a physical table.
+// A logical table with several projections is split across several physical tables (fan-out), and
+// a physical table receiving projections of several logical tables holds the cross-product of
+// their entries (fan-in).
+type TableProjection struct {
+	// LogicalTableID is the ID of the logical (high-level) table
+	LogicalTableID uint32
+	// PhysicalTableID is the ID of the physical (low-level) table
+	PhysicalTableID uint32
+	// Fields maps logical match field IDs onto physical ones; logical fields not present in the map are dropped.
+	// If nil, the field IDs are retained as they are.
+	Fields map[uint32]uint32
+	// Actions maps logical action IDs onto physical ones; actions not present in the map retain their ID
+	Actions map[uint32]uint32
+	// ProvidesAction indicates that, for fan-in, the logical table provides the action and priority of the merged
+	// entries; if none of the projections onto a physical table is so marked, the last one provides them
+	ProvidesAction bool
+}
+
+// Records a single derived low-level entity along with the keys of the high-level entities it originated from
+type derivation struct {
+	entity  *p4api.Entity
+	origins []string
+}
+
+// Provides translation of logical tables onto physical ones via fan-out and fan-in projections
+type tableMappingTranslator struct {
+	UpdateTranslator
+	*PacketMetadataTranslator
+	from       *p4info.P4Info
+	to         *p4info.P4Info
+	byLogical  map[uint32][]*TableProjection
+	byPhysical map[uint32][]*TableProjection
+
+	mu      sync.RWMutex
+	logical map[uint32]map[string]*p4api.TableEntry
+	derived map[string]*derivation
+	origins map[string]map[string]bool
+}
+
+// NewTableMappingTranslator returns a new translator which projects entries of logical tables onto physical tables
+// using the given projections. Entities of logical tables without any projections and entities other than table
+// entries are passed through as they are. Returns error if the projections do not comply with the pipelines.
+func NewTableMappingTranslator(from *p4info.P4Info, to *p4info.P4Info, projections []*TableProjection) (UpdateTranslator, error) {
+	t := &tableMappingTranslator{
+		PacketMetadataTranslator: NewPacketMetadataTranslator(from, to),
+		from:                     from,
+		to:                       to,
+		byLogical:                make(map[uint32][]*TableProjection),
+		byPhysical:               make(map[uint32][]*TableProjection),
+	}
+	t.reset()
+
+	for _, p := range projections {
+		if err := validateProjection(from, to, p); err != nil {
+			return nil, err
+		}
+		for _, other := range t.byPhysical[p.PhysicalTableID] {
+			if other.LogicalTableID == p.LogicalTableID {
+				return nil, errors.NewInvalid("Logical table %d is projected onto physical table %d more than once", p.LogicalTableID, p.PhysicalTableID)
+			}
+		}
+		t.byLogical[p.LogicalTableID] = append(t.byLogical[p.LogicalTableID], p)
+		t.byPhysical[p.PhysicalTableID] = append(t.byPhysical[p.PhysicalTableID], p)
+	}
+	return t, nil
+}
+
+// Validates that the tables, fields and actions of the projection exist in their respective pipelines
+func validateProjection(from *p4info.P4Info, to *p4info.P4Info, p *TableProjection) error {
+	logical := FindTableByID(from, p.LogicalTableID)
+	if logical == nil {
+		return errors.NewInvalid("No such logical table %d", p.LogicalTableID)
+	}
+	physical := FindTableByID(to, p.PhysicalTableID)
+	if physical == nil {
+		return errors.NewInvalid("No such physical table %d", p.PhysicalTableID)
+	}
+	for lf, pf := range p.Fields {
+		if FindMatchFieldByID(logical, lf) == nil {
+			return errors.NewInvalid("No such match field %d in logical table %s", lf, logical.Preamble.Name)
+		}
+		if FindMatchFieldByID(physical, pf) == nil {
+			return errors.NewInvalid("No such match field %d in physical table %s", pf, physical.Preamble.Name)
+		}
+	}
+	for la, pa := range p.Actions {
+		if !HasActionRef(logical, la) {
+			return errors.NewInvalid("No such action %d in logical table %s", la, logical.Preamble.Name)
+		}
+		if !HasActionRef(physical, pa) {
+			return errors.NewInvalid("No such action %d in physical table %s", pa, physical.Preamble.Name)
+		}
+	}
+	return nil
+}
+
+// Resets the record of logical entries and their derived entities
+func (t *tableMappingTranslator) reset() {
+	t.logical = make(map[uint32]map[string]*p4api.TableEntry)
+	t.derived = make(map[string]*derivation)
+	t.origins = make(map[string]map[string]bool)
+}
+
+// FromPipeline returns the P4 information describing the high-level pipeline
+func (t *tableMappingTranslator) FromPipeline() *p4info.P4Info {
+	return t.from
+}
+
+// ToPipeline returns the P4 information describing the low-level target pipeline
+func (t *tableMappingTranslator) ToPipeline() *p4info.P4Info {
+	return t.to
+}
+
+// TranslatePacketOut translates metadata of the given high-level packet-out into low-level ones
+func (t *tableMappingTranslator) TranslatePacketOut(packetOut *p4api.PacketOut) (*p4api.PacketOut, error) {
+	return t.PacketMetadataTranslator.TranslatePacketOut(packetOut)
+}
+
+// TranslatePacketIn translates metadata of the given low-level packet-in into high-level ones
+func (t *tableMappingTranslator) TranslatePacketIn(packetIn *p4api.PacketIn) (*p4api.PacketIn, error) {
+	return t.PacketMetadataTranslator.TranslatePacketIn(packetIn)
+}
+
+// Translate translates the complete set of the given high-level entities into low-level ones. The record of
+// derived entities is replaced with the one produced by this translation.
+func (t *tableMappingTranslator) Translate(entities *[]p4api.Entity) (*[]p4api.Entity, error) {
+	translated, _, err := t.TranslateWithProvenance(entities)
+	return translated, err
+}
+
+// TranslateWithProvenance translates the complete set of the given high-level entities into low-level ones and
+// reports provenance of each of the low-level entities. The record of derived entities is replaced with the one
+// produced by this translation.
+func (t *tableMappingTranslator) TranslateWithProvenance(entities *[]p4api.Entity) (*[]p4api.Entity, Provenance, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	logical, derived, origins := t.logical, t.derived, t.origins
+	t.reset()
+
+	indexes := make(map[string]int, len(*entities))
+	translated := make([]p4api.Entity, 0, len(*entities))
+	provenance := make(Provenance, 0, len(*entities))
+	for i := range *entities {
+		entity := &p4api.Entity{Entity: (*entities)[i].Entity}
+		if !t.isMapped(entity) {
+			translated = append(translated, p4api.Entity{Entity: entity.Entity})
+			provenance = append(provenance, []int{i})
+			continue
+		}
+
+		key := EntityKey(entity)
+		indexes[key] = i
+		updates, err := t.insert(entity.GetTableEntry(), key, nil)
+		if err != nil {
+			t.logical, t.derived, t.origins = logical, derived, origins
+			return nil, nil, err
+		}
+		for _, u := range updates {
+			translated = append(translated, p4api.Entity{Entity: u.Entity.Entity})
+			sources := make([]int, 0, 1)
+			for _, o := range t.derived[EntityKey(u.Entity)].origins {
+				sources = append(sources, indexes[o])
+			}
+			provenance = append(provenance, sources)
+		}
+	}
+	return &translated, provenance, nil
+}
+
+// TranslateUpdates translates the given high-level pipeline updates into low-level pipeline ones, updating the
+// record of derived entities.
+func (t *tableMappingTranslator) TranslateUpdates(updates *[]p4api.Update) (*[]p4api.Update, error) {
+	translated, _, err := t.translateUpdates(updates)
+	return translated, err
+}
+
+// Translates the given updates like TranslateUpdates and returns a function reverting the changes of the record of
+// derived entities made by the translation
+func (t *tableMappingTranslator) translateUpdates(updates *[]p4api.Update) (*[]p4api.Update, func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	undo := make([]func(), 0, len(*updates))
+	revert := func() {
+		for j := len(undo) - 1; j >= 0; j-- {
+			undo[j]()
+		}
+	}
+	translated := make([]p4api.Update, 0, len(*updates))
+	for i := range *updates {
+		update := &(*updates)[i]
+		if !t.isMapped(update.Entity) {
+			translated = append(translated, p4api.Update{Type: update.Type, Entity: update.Entity})
+			continue
+		}
+
+		var derivedUpdates []*p4api.Update
+		var err error
+		key := EntityKey(update.Entity)
+		entry := update.Entity.GetTableEntry()
+		switch update.Type {
+		case p4api.Update_INSERT:
+			derivedUpdates, err = t.insert(entry, key, &undo)
+		case p4api.Update_MODIFY:
+			derivedUpdates, err = t.modify(entry, key, &undo)
+		case p4api.Update_DELETE:
+			derivedUpdates, err = t.delete(entry, key, &undo)
+		default:
+			err = errors.NewInvalid("Unsupported update type %s", update.Type)
+		}
+		if err != nil {
+			revert()
+			return nil, nil, err
+		}
+		for _, u := range derivedUpdates {
+			translated = append(translated, p4api.Update{Type: u.Type, Entity: u.Entity})
+		}
+	}
+	return &translated, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		revert()
+	}, nil
+}
+
+// Derived returns the low-level entities presently derived from the given high-level entity.
+func (t *tableMappingTranslator) Derived(entity *p4api.Entity) []*p4api.Entity {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if !t.isMapped(entity) {
+		return []*p4api.Entity{entity}
+	}
+	keys := make([]string, 0, len(t.origins[EntityKey(entity)]))
+	for key := range t.origins[EntityKey(entity)] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	derived := make([]*p4api.Entity, 0, len(keys))
+	for _, key := range keys {
+		derived = append(derived, t.derived[key].entity)
+	}
+	return derived
+}
+
+// Returns true if the entity is an entry of a logical table with projections
+func (t *tableMappingTranslator) isMapped(entity *p4api.Entity) bool {
+	entry := entity.GetTableEntry()
+	return entry != nil && len(t.byLogical[entry.TableId]) > 0
+}
+
+// Records the logical entry and produces updates inserting all entities derived from it
+func (t *tableMappingTranslator) insert(entry *p4api.TableEntry, key string, undo *[]func()) ([]*p4api.Update, error) {
+	if _, ok := t.logical[entry.TableId][key]; ok {
+		return nil, errors.NewAlreadyExists("Entry already exists in logical table %d", entry.TableId)
+	}
+	t.putLogical(entry, key, undo)
+
+	derivations := t.derive(entry, key)
+	updates := make([]*p4api.Update, 0, len(derivations))
+	for _, d := range derivations {
+		if err := t.record(d, undo); err != nil {
+			return nil, err
+		}
+		updates = append(updates, &p4api.Update{Type: p4api.Update_INSERT, Entity: d.entity})
+	}
+	return updates, nil
+}
+
+// Records the modified logical entry and produces updates transitioning the entities derived from it
+func (t *tableMappingTranslator) modify(entry *p4api.TableEntry, key string, undo *[]func()) ([]*p4api.Update, error) {
+	if _, ok := t.logical[entry.TableId][key]; !ok {
+		return nil, errors.NewNotFound("Entry not found in logical table %d", entry.TableId)
+	}
+	t.putLogical(entry, key, undo)
+
+	previous := make(map[string]bool, len(t.origins[key]))
+	for pk := range t.origins[key] {
+		previous[pk] = true
+	}
+
+	updates := make([]*p4api.Update, 0, len(previous))
+	for _, d := range t.derive(entry, key) {
+		pk := EntityKey(d.entity)
+		if previous[pk] {
+			delete(previous, pk)
+			if proto.Equal(t.derived[pk].entity, d.entity) {
+				continue
+			}
+			t.unrecord(pk, undo)
+			updates = append(updates, &p4api.Update{Type: p4api.Update_MODIFY, Entity: d.entity})
+		} else {
+			updates = append(updates, &p4api.Update{Type: p4api.Update_INSERT, Entity: d.entity})
+		}
+		if err := t.record(d, undo); err != nil {
+			return nil, err
+		}
+	}
+
+	// Whatever was derived previously, but is no longer, must be removed
+	for pk := range previous {
+		updates = append(updates, &p4api.Update{Type: p4api.Update_DELETE, Entity: t.derived[pk].entity})
+		t.unrecord(pk, undo)
+	}
+	return updates, nil
+}
+
+// Forgets the logical entry and produces updates deleting all entities derived from it
+func (t *tableMappingTranslator) delete(entry *p4api.TableEntry, key string, undo *[]func()) ([]*p4api.Update, error) {
+	previous, ok := t.logical[entry.TableId][key]
+	if !ok {
+		return nil, errors.NewNotFound("Entry not found in logical table %d", entry.TableId)
+	}
+	delete(t.logical[entry.TableId], key)
+	*undo = append(*undo, func() { t.logical[entry.TableId][key] = previous })
+
+	updates := make([]*p4api.Update, 0, len(t.origins[key]))
+	for pk := range t.origins[key] {
+		updates = append(updates, &p4api.Update{Type: p4api.Update_DELETE, Entity: t.derived[pk].entity})
+		t.unrecord(pk, undo)
+	}
+	return updates, nil
+}
+
+// Puts the logical entry into the record of logical entries
+func (t *tableMappingTranslator) putLogical(entry *p4api.TableEntry, key string, undo *[]func()) {
+	entries, ok := t.logical[entry.TableId]
+	if !ok {
+		entries = make(map[string]*p4api.TableEntry)
+		t.logical[entry.TableId] = entries
+	}
+	previous, existed := entries[key]
+	entries[key] = entry
+	if undo != nil {
+		*undo = append(*undo, func() {
+			if existed {
+				entries[key] = previous
+			} else {
+				delete(entries, key)
+			}
+		})
+	}
+}
+
+// Records the derivation; returns error if the derived entity is already derived from other logical entities
+func (t *tableMappingTranslator) record(d *derivation, undo *[]func()) error {
+	pk := EntityKey(d.entity)
+	if _, ok := t.derived[pk]; ok {
+		return errors.NewConflict("Entry in physical table %d is already derived from another logical entry", d.entity.GetTableEntry().TableId)
+	}
+	t.derived[pk] = d
+	for _, o := range d.origins {
+		keys, ok := t.origins[o]
+		if !ok {
+			keys = make(map[string]bool)
+			t.origins[o] = keys
+		}
+		keys[pk] = true
+	}
+	if undo != nil {
+		*undo = append(*undo, func() { t.forget(pk) })
+	}
+	return nil
+}
+
+// Removes the derivation from the record
+func (t *tableMappingTranslator) unrecord(pk string, undo *[]func()) {
+	d := t.forget(pk)
+	if undo != nil && d != nil {
+		*undo = append(*undo, func() { _ = t.record(d, nil) })
+	}
+}
+
+func (t *tableMappingTranslator) forget(pk string) *derivation {
+	d, ok := t.derived[pk]
+	if !ok {
+		return nil
+	}
+	delete(t.derived, pk)
+	for _, o := range d.origins {
+		delete(t.origins[o], pk)
+		if len(t.origins[o]) == 0 {
+			delete(t.origins, o)
+		}
+	}
+	return d
+}
+
+// Derives all physical entities from the given logical entry, using the presently recorded entries of other
+// logical tables for any cross-products
+func (t *tableMappingTranslator) derive(entry *p4api.TableEntry, key string) []*derivation {
+	derivations := make([]*derivation, 0, len(t.byLogical[entry.TableId]))
+	for _, p := range t.byLogical[entry.TableId] {
+		group := t.byPhysical[p.PhysicalTableID]
+
+		// Assemble the candidate entries for each of the logical tables participating in the physical table
+		combinations := [][]*p4api.TableEntry{{}}
+		combinationKeys := [][]string{{}}
+		for _, gp := range group {
+			candidates := t.logical[gp.LogicalTableID]
+			if gp.LogicalTableID == entry.TableId {
+				candidates = map[string]*p4api.TableEntry{key: entry}
+			}
+			next := make([][]*p4api.TableEntry, 0, len(combinations)*len(candidates))
+			nextKeys := make([][]string, 0, len(combinations)*len(candidates))
+			for i, c := range combinations {
+				for ck, ce := range candidates {
+					next = append(next, append(append([]*p4api.TableEntry{}, c...), ce))
+					nextKeys = append(nextKeys, append(append([]string{}, combinationKeys[i]...), ck))
+				}
+			}
+			combinations, combinationKeys = next, nextKeys
+		}
+
+		for i, c := range combinations {
+			derivations = append(derivations, &derivation{
+				entity:  &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: merge(p.PhysicalTableID, group, c)}},
+				origins: combinationKeys[i],
+			})
+		}
+	}
+	return derivations
+}
+
+// Merges the given logical entries, one for each of the projections, into a single physical table entry
+func merge(tableID uint32, projections []*TableProjection, entries []*p4api.TableEntry) *p4api.TableEntry {
+	merged := &p4api.TableEntry{TableId: tableID}
+	provider := len(projections) - 1
+	for i, p := range projections {
+		if p.ProvidesAction {
+			provider = i
+		}
+		merged.Match = append(merged.Match, p.projectMatches(entries[i].Match)...)
+	}
+	sort.SliceStable(merged.Match, func(i, j int) bool { return merged.Match[i].FieldId < merged.Match[j].FieldId })
+
+	source := entries[provider]
+	merged.Action = projections[provider].projectAction(source.Action)
+	merged.Priority = source.Priority
+	merged.IsDefaultAction = source.IsDefaultAction && len(merged.Match) == 0
+	merged.IdleTimeoutNs = source.IdleTimeoutNs
+	merged.Metadata = source.Metadata
+	return merged
+}
+
+// Projects the logical field matches onto the physical table fields
+func (p *TableProjection) projectMatches(matches []*p4api.FieldMatch) []*p4api.FieldMatch {
+	projected := make([]*p4api.FieldMatch, 0, len(matches))
+	for _, m := range matches {
+		if p.Fields == nil {
+			projected = append(projected, m)
+		} else if id, ok := p.Fields[m.FieldId]; ok {
+			pm := proto.Clone(m).(*p4api.FieldMatch)
+			pm.FieldId = id
+			projected = append(projected, pm)
+		}
+	}
+	return projected
+}
+
+// Projects the logical table action onto the physical table action
+func (p *TableProjection) projectAction(action *p4api.TableAction) *p4api.TableAction {
+	if action.GetAction() == nil {
+		return action
+	}
+	id, ok := p.Actions[action.GetAction().ActionId]
+	if !ok {
+		return action
+	}
+	pa := proto.Clone(action).(*p4api.TableAction)
+	pa.GetAction().ActionId = id
+	return pa
+}
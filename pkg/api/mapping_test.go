@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	testutils "github.com/onosproject/onos-net-lib/pkg/test"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const (
+	aclTable         = 39601850
+	flowsTable       = 41690810
+	classifierTable  = 36334997
+	portVlanTable    = 42758823
+	bridgingTable    = 36104978
+	bridgingAction   = 21791748
+	aclSetNextAction = 23623126
+)
+
+func newTestMappingTranslator(t *testing.T) (UpdateTranslator, *p4info.P4Info) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	translator, err := NewTableMappingTranslator(info, info, []*TableProjection{
+		// ACL split across the ingress flow stats and the slice classifier
+		{LogicalTableID: aclTable, PhysicalTableID: flowsTable,
+			Fields: map[uint32]uint32{6: 1, 7: 2}, Actions: map[uint32]uint32{aclSetNextAction: 21929788}},
+		{LogicalTableID: aclTable, PhysicalTableID: classifierTable,
+			Fields: map[uint32]uint32{1: 1, 6: 2, 7: 3}, Actions: map[uint32]uint32{aclSetNextAction: 23786376}},
+
+		// Port VLAN and bridging merged into the ACL; bridging provides the action
+		{LogicalTableID: portVlanTable, PhysicalTableID: aclTable, Fields: map[uint32]uint32{1: 1}},
+		{LogicalTableID: bridgingTable, PhysicalTableID: aclTable, Fields: map[uint32]uint32{1: 4, 2: 2},
+			Actions: map[uint32]uint32{bridgingAction: aclSetNextAction}, ProvidesAction: true},
+	})
+	assert.NoError(t, err)
+	return translator, info
+}
+
+func generateUpdate(info *p4info.P4Info, tableID uint32, actionID uint32, updateType p4api.Update_Type) *p4api.Update {
+	table := FindTableByID(info, tableID)
+	action := &p4api.TableAction{Type: &p4api.TableAction_Action{Action: &p4api.Action{ActionId: actionID}}}
+	entry := testutils.GenerateTableEntry(table, 10, action)
+	return &p4api.Update{Type: updateType, Entity: &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}}
+}
+
+// Produces a slice of updates, as accepted by the translator, from the given updates
+func asUpdates(updates ...*p4api.Update) *[]p4api.Update {
+	result := make([]p4api.Update, len(updates))
+	for i, u := range updates {
+		result[i].Type = u.Type
+		result[i].Entity = u.Entity
+	}
+	return &result
+}
+
+func countByTable(updates *[]p4api.Update, updateType p4api.Update_Type) map[uint32]int {
+	counts := make(map[uint32]int)
+	for i := range *updates {
+		if (*updates)[i].Type == updateType {
+			counts[(*updates)[i].Entity.GetTableEntry().TableId]++
+		}
+	}
+	return counts
+}
+
+func TestTableFanOutAndFanIn(t *testing.T) {
+	translator, info := newTestMappingTranslator(t)
+
+	inserts := []*p4api.Update{
+		generateUpdate(info, aclTable, aclSetNextAction, p4api.Update_INSERT),
+		generateUpdate(info, portVlanTable, 24158268, p4api.Update_INSERT),
+		generateUpdate(info, portVlanTable, 24158268, p4api.Update_INSERT),
+		generateUpdate(info, bridgingTable, bridgingAction, p4api.Update_INSERT),
+		generateUpdate(info, bridgingTable, bridgingAction, p4api.Update_INSERT),
+		generateUpdate(info, bridgingTable, bridgingAction, p4api.Update_INSERT),
+		generateUpdate(info, mplsTable(info), 30066030, p4api.Update_INSERT),
+	}
+	translated, err := translator.TranslateUpdates(asUpdates(inserts...))
+	assert.NoError(t, err)
+	counts := countByTable(translated, p4api.Update_INSERT)
+	assert.Equal(t, 1, counts[flowsTable])
+	assert.Equal(t, 1, counts[classifierTable])
+	assert.Equal(t, 6, counts[aclTable])
+	assert.Equal(t, 1, counts[mplsTable(info)])
+
+	// Validate the fan-out projections
+	derived := translator.Derived(inserts[0].Entity)
+	assert.Len(t, derived, 2)
+	for _, e := range derived {
+		assert.Len(t, e.GetTableEntry().Match, len(projectedFields(e.GetTableEntry().TableId)))
+		assert.NotEqual(t, uint32(aclSetNextAction), e.GetTableEntry().Action.GetAction().ActionId)
+	}
+
+	// Validate the fan-in cross-product
+	derived = translator.Derived(inserts[3].Entity)
+	assert.Len(t, derived, 2)
+	for _, e := range derived {
+		assert.Equal(t, uint32(aclTable), e.GetTableEntry().TableId)
+		assert.Len(t, e.GetTableEntry().Match, 3)
+		assert.Equal(t, uint32(aclSetNextAction), e.GetTableEntry().Action.GetAction().ActionId)
+	}
+
+	// Modifying the action of an entry providing the action must modify all its derived entries
+	modify := generateUpdate(info, bridgingTable, 28485346, p4api.Update_MODIFY)
+	modify.Entity.GetTableEntry().Match = inserts[3].Entity.GetTableEntry().Match
+	translated, err = translator.TranslateUpdates(asUpdates(modify))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, countByTable(translated, p4api.Update_MODIFY)[aclTable])
+
+	// Deleting a logical entry must delete all its derived entries
+	translated, err = translator.TranslateUpdates(asUpdates(
+		&p4api.Update{Type: p4api.Update_DELETE, Entity: inserts[0].Entity},
+		&p4api.Update{Type: p4api.Update_DELETE, Entity: inserts[1].Entity}))
+	assert.NoError(t, err)
+	counts = countByTable(translated, p4api.Update_DELETE)
+	assert.Equal(t, 1, counts[flowsTable])
+	assert.Equal(t, 1, counts[classifierTable])
+	assert.Equal(t, 3, counts[aclTable])
+	assert.Len(t, translator.Derived(inserts[0].Entity), 0)
+	assert.Len(t, translator.Derived(inserts[3].Entity), 1)
+}
+
+func TestTableMappingUpdateFailure(t *testing.T) {
+	translator, info := newTestMappingTranslator(t)
+
+	inserts := []*p4api.Update{
+		generateUpdate(info, portVlanTable, 24158268, p4api.Update_INSERT),
+		generateUpdate(info, bridgingTable, bridgingAction, p4api.Update_INSERT),
+	}
+	_, err := translator.TranslateUpdates(asUpdates(inserts...))
+	assert.NoError(t, err)
+
+	// Insert another entry, but fail due to deletion of a non-existent one; nothing must change
+	updates := []*p4api.Update{
+		generateUpdate(info, bridgingTable, bridgingAction, p4api.Update_INSERT),
+		generateUpdate(info, portVlanTable, 24158268, p4api.Update_DELETE),
+	}
+	_, err = translator.TranslateUpdates(asUpdates(updates...))
+	assert.True(t, errors.IsNotFound(err))
+	assert.Len(t, translator.Derived(inserts[0].Entity), 1)
+	assert.Len(t, translator.Derived(updates[0].Entity), 0)
+
+	// Full translation must replace the record of derived entities
+	entities := []p4api.Entity{{Entity: inserts[0].Entity.Entity}, {Entity: updates[0].Entity.Entity}, {Entity: inserts[1].Entity.Entity}}
+	translated, provenance, err := TranslateWithProvenance(translator, &entities)
+	assert.NoError(t, err)
+	assert.Len(t, *translated, 2)
+	assert.Equal(t, Provenance{{0, 1}, {0, 2}}, provenance)
+	assert.Len(t, translator.Derived(inserts[0].Entity), 2)
+}
+
+func TestTableMappingValidation(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	_, err = NewTableMappingTranslator(info, info, []*TableProjection{{LogicalTableID: 1, PhysicalTableID: aclTable}})
+	assert.True(t, errors.IsInvalid(err))
+	_, err = NewTableMappingTranslator(info, info, []*TableProjection{{LogicalTableID: aclTable, PhysicalTableID: flowsTable, Fields: map[uint32]uint32{1: 99}}})
+	assert.True(t, errors.IsInvalid(err))
+	_, err = NewTableMappingTranslator(info, info, []*TableProjection{{LogicalTableID: aclTable, PhysicalTableID: flowsTable, Actions: map[uint32]uint32{aclSetNextAction: 99}}})
+	assert.True(t, errors.IsInvalid(err))
+	_, err = NewTableMappingTranslator(info, info, []*TableProjection{{LogicalTableID: aclTable, PhysicalTableID: flowsTable, Actions: map[uint32]uint32{99: 21929788}}})
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func mplsTable(info *p4info.P4Info) uint32 {
+	return p4utils.FindTable(info, "FabricIngress.forwarding.mpls").Preamble.Id
+}
+
+func projectedFields(tableID uint32) []uint32 {
+	if tableID == flowsTable {
+		return []uint32{1, 2}
+	}
+	return []uint32{1, 2, 3}
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"strings"
+)
+
+const (
+	packetOutHeader = "packet_out"
+	packetInHeader  = "packet_in"
+)
+
+// PacketMetadataTranslator translates controller packet metadata of packet-out and packet-in messages between
+// the high-level and the low-level pipelines. Metadata are associated by their names; padding metadata are filled
+// in for packet-out messages and stripped from packet-in messages, so that applications never see them.
+type PacketMetadataTranslator struct {
+	packetOut *metadataMapping
+	packetIn  *metadataMapping
+}
+
+// Captures association of the high-level and the low-level metadata of a single controller header
+type metadataMapping struct {
+	header     string
+	physical   []*p4info.ControllerPacketMetadata_Metadata
+	toPhysical map[uint32]uint32
+	toLogical  map[uint32]uint32
+	padding    map[uint32]bool
+	ignored    map[uint32]bool
+}
+
+// NewPacketMetadataTranslator returns a new packet metadata translator between the given high-level and
+// low-level pipelines.
+func NewPacketMetadataTranslator(from *p4info.P4Info, to *p4info.P4Info) *PacketMetadataTranslator {
+	return &PacketMetadataTranslator{
+		packetOut: newMetadataMapping(packetOutHeader, from, to),
+		packetIn:  newMetadataMapping(packetInHeader, from, to),
+	}
+}
+
+func newMetadataMapping(header string, from *p4info.P4Info, to *p4info.P4Info) *metadataMapping {
+	logical := findControllerHeader(from, header)
+	physical := findControllerHeader(to, header)
+	if logical == nil || physical == nil {
+		return nil
+	}
+
+	m := &metadataMapping{
+		header:     header,
+		physical:   physical.Metadata,
+		toPhysical: make(map[uint32]uint32),
+		toLogical:  make(map[uint32]uint32),
+		padding:    make(map[uint32]bool),
+		ignored:    make(map[uint32]bool),
+	}
+	for _, lm := range logical.Metadata {
+		if IsPadding(lm) {
+			m.ignored[lm.Id] = true
+		}
+	}
+	for _, pm := range physical.Metadata {
+		if IsPadding(pm) {
+			m.padding[pm.Id] = true
+			continue
+		}
+		for _, lm := range logical.Metadata {
+			if lm.Name == pm.Name && !IsPadding(lm) {
+				m.toPhysical[lm.Id] = pm.Id
+				m.toLogical[pm.Id] = lm.Id
+			}
+		}
+	}
+	return m
+}
+
+func findControllerHeader(info *p4info.P4Info, name string) *p4info.ControllerPacketMetadata {
+	for _, cpm := range info.GetControllerPacketMetadata() {
+		if cpm.Preamble.Name == name {
+			return cpm
+		}
+	}
+	return nil
+}
+
+// IsPadding returns true if the metadata is annotated as padding or is named as such by the compiler
+func IsPadding(md *p4info.ControllerPacketMetadata_Metadata) bool {
+	for _, a := range md.Annotations {
+		if a == "@padding" {
+			return true
+		}
+	}
+	return strings.HasPrefix(md.Name, "_pad")
+}
+
+// TranslatePacketOut translates the given high-level packet-out into a low-level one, filling in any padding.
+// Any high-level padding metadata are ignored. Returns error if the packet-out carries metadata not defined by
+// the high-level pipeline.
+func (t *PacketMetadataTranslator) TranslatePacketOut(packetOut *p4api.PacketOut) (*p4api.PacketOut, error) {
+	m := t.packetOut
+	if m == nil {
+		return packetOut, nil
+	}
+
+	values := make(map[uint32][]byte, len(packetOut.Metadata))
+	for _, md := range packetOut.Metadata {
+		if m.ignored[md.MetadataId] {
+			continue
+		}
+		id, ok := m.toPhysical[md.MetadataId]
+		if !ok {
+			return nil, errors.NewInvalid("Unknown %s metadata %d", m.header, md.MetadataId)
+		}
+		values[id] = md.Value
+	}
+
+	metadata := make([]*p4api.PacketMetadata, 0, len(m.physical))
+	for _, pm := range m.physical {
+		if m.padding[pm.Id] {
+			metadata = append(metadata, &p4api.PacketMetadata{MetadataId: pm.Id, Value: []byte{0}})
+		} else if value, ok := values[pm.Id]; ok {
+			metadata = append(metadata, &p4api.PacketMetadata{MetadataId: pm.Id, Value: value})
+		}
+	}
+	return &p4api.PacketOut{Payload: packetOut.Payload, Metadata: metadata}, nil
+}
+
+// TranslatePacketIn translates the given low-level packet-in into a high-level one, stripping any padding and
+// any metadata not defined by the high-level pipeline.
+func (t *PacketMetadataTranslator) TranslatePacketIn(packetIn *p4api.PacketIn) (*p4api.PacketIn, error) {
+	m := t.packetIn
+	if m == nil {
+		return packetIn, nil
+	}
+
+	metadata := make([]*p4api.PacketMetadata, 0, len(packetIn.Metadata))
+	for _, md := range packetIn.Metadata {
+		if id, ok := m.toLogical[md.MetadataId]; ok {
+			metadata = append(metadata, &p4api.PacketMetadata{MetadataId: id, Value: md.Value})
+		}
+	}
+	return &p4api.PacketIn{Payload: packetIn.Payload, Metadata: metadata}, nil
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+// Produces a logical pipeline whose controller headers carry no padding and use different metadata IDs
+func logicalPipeline(info *p4info.P4Info) *p4info.P4Info {
+	logical := proto.Clone(info).(*p4info.P4Info)
+	for _, cpm := range logical.ControllerPacketMetadata {
+		metadata := make([]*p4info.ControllerPacketMetadata_Metadata, 0, len(cpm.Metadata))
+		for _, md := range cpm.Metadata {
+			if !IsPadding(md) {
+				md.Id = uint32(100 + len(metadata))
+				metadata = append(metadata, md)
+			}
+		}
+		cpm.Metadata = metadata
+	}
+	return logical
+}
+
+func TestPacketOutMetadata(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	translator := NewPacketMetadataTranslator(logicalPipeline(info), info)
+	packetOut, err := translator.TranslatePacketOut(&p4api.PacketOut{
+		Payload: []byte("payload"),
+		Metadata: []*p4api.PacketMetadata{
+			{MetadataId: 100, Value: []byte{0, 7}}, // egress_port
+			{MetadataId: 102, Value: []byte{1}},    // cpu_loopback_mode
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("payload"), packetOut.Payload)
+	assert.Equal(t, []*p4api.PacketMetadata{
+		{MetadataId: 1, Value: []byte{0}},
+		{MetadataId: 2, Value: []byte{0, 7}},
+		{MetadataId: 3, Value: []byte{0}},
+		{MetadataId: 5, Value: []byte{0}},
+		{MetadataId: 6, Value: []byte{1}},
+		{MetadataId: 8, Value: []byte{0}},
+		{MetadataId: 11, Value: []byte{0}},
+	}, packetOut.Metadata)
+
+	_, err = translator.TranslatePacketOut(&p4api.PacketOut{Metadata: []*p4api.PacketMetadata{{MetadataId: 1, Value: []byte{0}}}})
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestPacketInMetadata(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	translator := NewPacketMetadataTranslator(logicalPipeline(info), info)
+	packetIn, err := translator.TranslatePacketIn(&p4api.PacketIn{
+		Payload: []byte("payload"),
+		Metadata: []*p4api.PacketMetadata{
+			{MetadataId: 1, Value: []byte{0}},
+			{MetadataId: 2, Value: []byte{0, 3}},
+			{MetadataId: 3, Value: []byte{0}},
+			{MetadataId: 4, Value: []byte{1}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("payload"), packetIn.Payload)
+	assert.Equal(t, []*p4api.PacketMetadata{
+		{MetadataId: 100, Value: []byte{0, 3}},
+		{MetadataId: 101, Value: []byte{1}},
+	}, packetIn.Metadata)
+}
+
+func TestIdentityTranslatorPadding(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	chain, err := NewTranslatorChain(NewIdentityTranslator(info), NewIdentityTranslator(info))
+	assert.NoError(t, err)
+
+	packetOut, err := chain.TranslatePacketOut(&p4api.PacketOut{Metadata: []*p4api.PacketMetadata{{MetadataId: 2, Value: []byte{1}}}})
+	assert.NoError(t, err)
+	assert.Len(t, packetOut.Metadata, 6)
+
+	packetIn, err := chain.TranslatePacketIn(&p4api.PacketIn{Metadata: []*p4api.PacketMetadata{
+		{MetadataId: 1, Value: []byte{0}}, {MetadataId: 2, Value: []byte{1}}}})
+	assert.NoError(t, err)
+	assert.Equal(t, []*p4api.PacketMetadata{{MetadataId: 2, Value: []byte{1}}}, packetIn.Metadata)
+}
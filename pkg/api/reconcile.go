@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+// ReconcilePolicy determines how the reconciler treats the entries of a table which differ from the intent
+type ReconcilePolicy int
+
+const (
+	// ReconcileDeleteUnknown inserts missing entries, modifies differing ones and deletes entries unknown to the
+	// intent; this is the default policy
+	ReconcileDeleteUnknown ReconcilePolicy = iota
+	// ReconcileEnforce inserts missing entries and modifies differing ones, preserving entries unknown to the intent,
+	// e.g. those written by other tools
+	ReconcileEnforce
+	// ReconcileAdoptUnknown inserts missing entries and modifies differing ones, adopting entries unknown to the
+	// intent into the store where they can be translated into logical entities
+	ReconcileAdoptUnknown
+	// ReconcileObserveOnly only reports how the entries differ from the intent, without making any changes
+	ReconcileObserveOnly
+)
+
+func (p ReconcilePolicy) String() string {
+	switch p {
+	case ReconcileDeleteUnknown:
+		return "delete-unknown"
+	case ReconcileEnforce:
+		return "enforce"
+	case ReconcileAdoptUnknown:
+		return "adopt-unknown"
+	case ReconcileObserveOnly:
+		return "observe-only"
+	default:
+		return "unknown"
+	}
+}
+
+// ReconcileReport describes the outcome of a reconciliation of a device, table by table
+type ReconcileReport struct {
+	// Tables are the outcomes for the individual tables, ordered by their names
+	Tables []*TableReconciliation
+}
+
+// TableReconciliation describes the outcome of reconciliation of the entries of a single table; entities other than
+// table entries, e.g. action profile members, are reported together under an empty table name
+type TableReconciliation struct {
+	// Table is the name of the table in the pipeline of the device
+	Table string
+	// Policy is the policy applied to the table
+	Policy ReconcilePolicy
+	// Drift describes how the entries of the table differed from the intent prior to reconciliation
+	Drift *DriftReport
+	// Inserted is the number of missing entries inserted on the device
+	Inserted int
+	// Modified is the number of differing entries modified on the device
+	Modified int
+	// Deleted is the number of unknown entries deleted from the device
+	Deleted int
+	// Adopted is the number of unknown entries adopted into the store
+	Adopted int
+}
@@ -0,0 +1,343 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/encoding/prototext"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FormatEntity renders the given entity as human-readable text using the names of the P4 objects defined by
+// the pipeline info, e.g.
+//
+//	FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0/8 -> set_next_id_routing_v4(next_id=5)
+//
+// Exact and optional matches are rendered as field=value, ternary matches as field=value&&&mask, LPM matches as
+// field=value/length and range matches as field=low..high. Values of types translated into strings are rendered as
+// is, or as quoted Go string literals if they contain whitespace or delimiters. Entities other than table entries,
+// or those referring to objects unknown to the pipeline, are rendered in the protobuf text format.
+func FormatEntity(info *p4info.P4Info, entity *p4api.Entity) string {
+	entry := entity.GetTableEntry()
+	if entry == nil {
+		return prototext.MarshalOptions{}.Format(entity)
+	}
+	table := FindTableByID(info, entry.TableId)
+	if table == nil {
+		return prototext.MarshalOptions{}.Format(entity)
+	}
+
+	parts := []string{table.Preamble.Name}
+	if entry.IsDefaultAction {
+		parts = append(parts, "default")
+	}
+	for _, match := range entry.Match {
+		mf := FindMatchFieldByID(table, match.FieldId)
+		if mf == nil {
+			parts = append(parts, fmt.Sprintf("%d=?", match.FieldId))
+			continue
+		}
+		format := func(value []byte) string {
+			return formatValue(info, mf.Name, mf.GetTypeName(), mf.Bitwidth, value)
+		}
+		switch m := match.FieldMatchType.(type) {
+		case *p4api.FieldMatch_Exact_:
+			parts = append(parts, mf.Name+"="+format(m.Exact.Value))
+		case *p4api.FieldMatch_Ternary_:
+			parts = append(parts, mf.Name+"="+format(m.Ternary.Value)+"&&&"+formatValue(info, mf.Name, nil, mf.Bitwidth, m.Ternary.Mask))
+		case *p4api.FieldMatch_Lpm:
+			parts = append(parts, fmt.Sprintf("%s=%s/%d", mf.Name, format(m.Lpm.Value), m.Lpm.PrefixLen))
+		case *p4api.FieldMatch_Range_:
+			parts = append(parts, mf.Name+"="+format(m.Range.Low)+".."+format(m.Range.High))
+		case *p4api.FieldMatch_Optional_:
+			parts = append(parts, mf.Name+"="+format(m.Optional.Value))
+		}
+	}
+	if entry.Priority != 0 {
+		parts = append(parts, fmt.Sprintf("priority=%d", entry.Priority))
+	}
+
+	if action := formatTableAction(info, entry.Action); action != "" {
+		parts = append(parts, "->", action)
+	}
+	return strings.Join(parts, " ")
+}
+
+// FormatUpdate renders the given update as human-readable text, prefixing the formatted entity with the
+// update type, e.g. insert, modify or delete.
+func FormatUpdate(info *p4info.P4Info, update *p4api.Update) string {
+	return strings.ToLower(update.Type.String()) + " " + FormatEntity(info, update.Entity)
+}
+
+// Renders the table action; empty if no action is set
+func formatTableAction(info *p4info.P4Info, action *p4api.TableAction) string {
+	switch a := action.GetType().(type) {
+	case *p4api.TableAction_Action:
+		return formatAction(info, a.Action)
+	case *p4api.TableAction_ActionProfileMemberId:
+		return fmt.Sprintf("member(%d)", a.ActionProfileMemberId)
+	case *p4api.TableAction_ActionProfileGroupId:
+		return fmt.Sprintf("group(%d)", a.ActionProfileGroupId)
+	case *p4api.TableAction_ActionProfileActionSet:
+		return prototext.MarshalOptions{}.Format(a.ActionProfileActionSet)
+	}
+	return ""
+}
+
+func formatAction(info *p4info.P4Info, action *p4api.Action) string {
+	ai := FindActionByID(info, action.ActionId)
+	if ai == nil {
+		return prototext.MarshalOptions{}.Format(action)
+	}
+	params := make([]string, 0, len(action.Params))
+	for _, param := range action.Params {
+		for _, p := range ai.Params {
+			if p.Id == param.ParamId {
+				params = append(params, p.Name+"="+formatValue(info, p.Name, p.GetTypeName(), p.Bitwidth, param.Value))
+			}
+		}
+	}
+	name := ai.Preamble.Alias
+	if name == "" {
+		name = ai.Preamble.Name
+	}
+	return name + "(" + strings.Join(params, ", ") + ")"
+}
+
+// Renders the value as a serializable enum member, a string, an IP address or a MAC address when its type, width
+// and name suggest so, or as a decimal number otherwise
+func formatValue(info *p4info.P4Info, name string, typeName *p4info.P4NamedType, bitwidth int32, value []byte) string {
+	if enum := info.GetTypeInfo().GetSerializableEnums()[typeName.GetName()]; enum != nil {
+		for _, member := range enum.Members {
+			if new(big.Int).SetBytes(member.Value).Cmp(new(big.Int).SetBytes(value)) == 0 {
+				return typeName.Name + "." + member.Name
+			}
+		}
+	}
+	if isSdnString(info, typeName) {
+		return formatString(string(value))
+	}
+	name = strings.ToLower(name)
+	n := new(big.Int).SetBytes(value)
+	switch {
+	case bitwidth == 32 && strings.Contains(name, "ipv4") && n.BitLen() <= 32:
+		return net.IP(n.FillBytes(make([]byte, 4))).String()
+	case bitwidth == 128 && strings.Contains(name, "ipv6") && n.BitLen() <= 128:
+		return net.IP(n.FillBytes(make([]byte, 16))).String()
+	case bitwidth == 48 && (strings.Contains(name, "eth") || strings.Contains(name, "mac")) && n.BitLen() <= 48:
+		return net.HardwareAddr(n.FillBytes(make([]byte, 6))).String()
+	}
+	return n.String()
+}
+
+// Renders the string as is, unless it is empty or contains characters other than letters, digits and -_.:/@, in
+// which case it is rendered quoted, as a Go string literal
+func formatString(s string) string {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_.:/@", r) {
+			return strconv.Quote(s)
+		}
+	}
+	if s == "" {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// Returns the value of the given quoted string, as rendered by formatString, or the text itself if it is not quoted
+func unquote(text string) string {
+	if len(text) >= 2 && text[0] == '"' {
+		if s, err := strconv.Unquote(text); err == nil {
+			return s
+		}
+	}
+	return text
+}
+
+// Splits the text around each character for which sep returns true, except within quoted strings; empty parts are
+// dropped
+func splitQuoted(text string, sep func(r rune) bool) []string {
+	parts := make([]string, 0)
+	quoted, escaped := false, false
+	start := 0
+	for i, r := range text {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && sep(r):
+			if i > start {
+				parts = append(parts, text[start:i])
+			}
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	if start < len(text) {
+		parts = append(parts, text[start:])
+	}
+	return parts
+}
+
+// Slices the text around the first instance of sep outside of quoted strings
+func cutQuoted(text string, sep string) (string, string, bool) {
+	quoted, escaped := false, false
+	for i, r := range text {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && strings.HasPrefix(text[i:], sep):
+			return text[:i], text[i+len(sep):], true
+		}
+	}
+	return text, "", false
+}
+
+// ParseEntity parses the given text, as rendered by FormatEntity, into a table entry entity using the names of
+// the P4 objects defined by the pipeline info. Action profile action sets are not supported.
+func ParseEntity(info *p4info.P4Info, text string) (*p4api.Entity, error) {
+	matchText, actionText, hasAction := cutQuoted(text, "->")
+	fields := splitQuoted(matchText, unicode.IsSpace)
+	if len(fields) == 0 {
+		return nil, errors.NewInvalid("Missing table name in %q", text)
+	}
+
+	tb := NewEntityBuilder(info).Table(fields[0])
+	for _, field := range fields[1:] {
+		if field == "default" {
+			tb.Default()
+			continue
+		}
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, errors.NewInvalid("Expected field=value, got %q", field)
+		}
+		if tb.err == nil && name == "priority" && findMatchFieldByName(tb.table, name) == nil {
+			priority, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return nil, errors.NewInvalid("Invalid priority %q", value)
+			}
+			tb.Priority(int32(priority))
+			continue
+		}
+		parseMatch(tb, name, value)
+	}
+
+	if hasAction {
+		if err := parseTableAction(tb, strings.TrimSpace(actionText)); err != nil {
+			return nil, err
+		}
+	}
+	return tb.Entity()
+}
+
+// ParseUpdate parses the given text, as rendered by FormatUpdate, into an update; if the text does not start with
+// the update type, an insert is assumed.
+func ParseUpdate(info *p4info.P4Info, text string) (*p4api.Update, error) {
+	updateType := p4api.Update_INSERT
+	first, rest, _ := strings.Cut(strings.TrimSpace(text), " ")
+	if t, ok := p4api.Update_Type_value[strings.ToUpper(first)]; ok && t != int32(p4api.Update_UNSPECIFIED) {
+		updateType = p4api.Update_Type(t)
+		text = rest
+	}
+	entity, err := ParseEntity(info, text)
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// Adds the match of the named field, interpreting the value according to the field match type
+func parseMatch(tb *TableEntryBuilder, name string, value string) {
+	if tb.err != nil {
+		return
+	}
+	mf := findMatchFieldByName(tb.table, name)
+	if mf == nil {
+		tb.Exact(name, value) // records the descriptive error
+		return
+	}
+	switch mf.GetMatchType() {
+	case p4info.MatchField_TERNARY:
+		v, mask, ok := cutQuoted(value, "&&&")
+		v, mask = unquote(v), unquote(mask)
+		if !ok {
+			width := maskBitwidth(tb.info, mf, []byte(v))
+			mask = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(width)), big.NewInt(1)).String()
+		}
+		tb.Ternary(name, v, mask)
+	case p4info.MatchField_LPM:
+		v, length, ok := strings.Cut(value, "/")
+		prefixLen, err := strconv.ParseInt(length, 10, 32)
+		if !ok || err != nil {
+			tb.err = errors.NewInvalid("Table %s match field %s: expected value/length, got %q", tb.table.Preamble.Name, name, value)
+			return
+		}
+		tb.LPM(name, v, int32(prefixLen))
+	case p4info.MatchField_RANGE:
+		low, high, ok := cutQuoted(value, "..")
+		if !ok {
+			tb.err = errors.NewInvalid("Table %s match field %s: expected low..high, got %q", tb.table.Preamble.Name, name, value)
+			return
+		}
+		tb.Range(name, unquote(low), unquote(high))
+	case p4info.MatchField_OPTIONAL:
+		tb.Optional(name, unquote(value))
+	default:
+		tb.Exact(name, unquote(value))
+	}
+}
+
+// Parses the table action given as either name(param=value, ...), member(id) or group(id)
+func parseTableAction(tb *TableEntryBuilder, text string) error {
+	open := strings.Index(text, "(")
+	if open < 0 || !strings.HasSuffix(text, ")") {
+		return errors.NewInvalid("Expected action(param=value, ...), got %q", text)
+	}
+	name, args := strings.TrimSpace(text[:open]), text[open+1:len(text)-1]
+
+	if name == "member" || name == "group" {
+		id, err := strconv.ParseUint(strings.TrimSpace(args), 10, 32)
+		if err != nil {
+			return errors.NewInvalid("Invalid action profile %s ID %q", name, args)
+		}
+		if tb.err == nil {
+			if name == "member" {
+				tb.entry.Action = &p4api.TableAction{Type: &p4api.TableAction_ActionProfileMemberId{ActionProfileMemberId: uint32(id)}}
+			} else {
+				tb.entry.Action = &p4api.TableAction{Type: &p4api.TableAction_ActionProfileGroupId{ActionProfileGroupId: uint32(id)}}
+			}
+		}
+		return nil
+	}
+
+	var params []ParamValue
+	for _, arg := range splitQuoted(args, func(r rune) bool { return r == ',' }) {
+		if arg = strings.TrimSpace(arg); arg == "" {
+			continue
+		}
+		pn, pv, ok := strings.Cut(arg, "=")
+		if !ok {
+			return errors.NewInvalid("Expected param=value, got %q", arg)
+		}
+		params = append(params, Param(strings.TrimSpace(pn), unquote(strings.TrimSpace(pv))))
+	}
+	tb.Action(name, params...)
+	return nil
+}
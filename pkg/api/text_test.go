@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+func TestFormatAndParse(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	texts := []string{
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0/8 -> set_next_id_routing_v4(next_id=5)",
+		"FabricIngress.forwarding.bridging vlan_id=10 eth_dst=00:00:00:00:00:01&&&ff:ff:ff:ff:ff:ff priority=100 -> set_next_id_bridging(next_id=7)",
+		"FabricIngress.stats.flows ipv4_src=10.0.0.0&&&255.0.0.0 ig_port=260 priority=10 -> FabricIngress.stats.count(flow_id=3)",
+		"FabricIngress.next.hashed next_id=4 -> group(12)",
+		"FabricIngress.forwarding.routing_v4 default -> nop_routing_v4()",
+	}
+	for _, text := range texts {
+		entity, err := ParseEntity(info, text)
+		if !assert.NoError(t, err, text) {
+			continue
+		}
+		formatted := FormatEntity(info, entity)
+
+		// Formatting canonicalizes the values, e.g. MAC addresses; parsing the result must yield the same entity
+		reparsed, err := ParseEntity(info, formatted)
+		assert.NoError(t, err, formatted)
+		assert.True(t, proto.Equal(entity, reparsed), formatted)
+	}
+
+	entity, err := ParseEntity(info, texts[0])
+	assert.NoError(t, err)
+	assert.Equal(t, texts[0], FormatEntity(info, entity))
+
+	update, err := ParseUpdate(info, "delete "+texts[1])
+	assert.NoError(t, err)
+	assert.Equal(t, p4api.Update_DELETE, update.Type)
+	assert.Equal(t, int32(100), update.Entity.GetTableEntry().Priority)
+	assert.Equal(t, "delete FabricIngress.forwarding.bridging vlan_id=10 eth_dst=00:00:00:00:00:01&&&ff:ff:ff:ff:ff:ff priority=100 -> set_next_id_bridging(next_id=7)",
+		FormatUpdate(info, update))
+
+	update, err = ParseUpdate(info, texts[2])
+	assert.NoError(t, err)
+	assert.Equal(t, p4api.Update_INSERT, update.Type)
+}
+
+func TestFormatAndParseStrings(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	// Translate port IDs into strings, as done by pipelines using named ports
+	info.TypeInfo.NewTypes["FabricPortId_t"].Representation = &p4info.P4NewTypeSpec_TranslatedType{
+		TranslatedType: &p4info.P4NewTypeTranslation{Uri: "tna/PortId_t", SdnType: &p4info.P4NewTypeTranslation_SdnString_{SdnString: &p4info.P4NewTypeTranslation_SdnString{}}}}
+
+	for _, text := range []string{
+		"FabricIngress.stats.flows ig_port=eth1/1 priority=10 -> FabricIngress.stats.count(flow_id=3)",
+		`FabricIngress.stats.flows ig_port="port 1, slot \"a\" -> b" priority=10 -> FabricIngress.stats.count(flow_id=3)`,
+	} {
+		entity, err := ParseEntity(info, text)
+		if !assert.NoError(t, err, text) {
+			continue
+		}
+		assert.Equal(t, text, FormatEntity(info, entity))
+	}
+
+	entity, err := ParseEntity(info, "FabricIngress.stats.flows ig_port=eth1/1 priority=10 -> FabricIngress.stats.count(flow_id=3)")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("eth1/1"), entity.GetTableEntry().Match[0].GetExact().Value)
+
+	// Ternary values are unquoted as well and masked in full when no mask is given
+	entity, err = ParseEntity(info, `FabricIngress.acl.acl ig_port="eth 1&&&2" priority=10 -> FabricIngress.acl.drop()`)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("eth 1&&&2"), entity.GetTableEntry().Match[0].GetTernary().Value)
+	assert.Equal(t, bytes.Repeat([]byte{0xff}, 9), entity.GetTableEntry().Match[0].GetTernary().Mask)
+	text := FormatEntity(info, entity)
+	entity, err = ParseEntity(info, text)
+	assert.NoError(t, err, text)
+	assert.Equal(t, text, FormatEntity(info, entity))
+	assert.Equal(t, []byte("eth 1&&&2"), entity.GetTableEntry().Match[0].GetTernary().Value)
+}
+
+func TestParseErrors(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	for _, text := range []string{
+		"",
+		"FabricIngress.bogus ipv4_dst=10.0.0.0/8",
+		"FabricIngress.forwarding.routing_v4 bogus=1",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0/8 -> bogus(next_id=5)",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0/8 -> set_next_id_routing_v4(next_id)",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0/8 -> set_next_id_routing_v4",
+	} {
+		_, err := ParseEntity(info, text)
+		assert.True(t, errors.IsInvalid(err), "%q: %+v", text, err)
+	}
+}
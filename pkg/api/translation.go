@@ -5,6 +5,7 @@
 package api
 
 import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-net-lib/pkg/p4utils"
 	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
 	p4api "github.com/p4lang/p4runtime/go/p4/v1"
@@ -14,7 +15,8 @@ import (
 // entities into low-level pipeline ones.
 type PipelineTranslator interface {
 	// Translate translates the given high-level pipeline entities into low-level pipeline ones.
-	Translate(entities *[]p4api.Entity) *[]p4api.Entity
+	// Returns error if any of the entities cannot be translated.
+	Translate(entities *[]p4api.Entity) (*[]p4api.Entity, error)
 
 	// FromPipeline returns the P4 information describing the high-level pipeline
 	FromPipeline() *p4info.P4Info
@@ -22,19 +24,41 @@ type PipelineTranslator interface {
 	// ToPipeline returns the P4 information describing the low-level target pipeline
 	ToPipeline() *p4info.P4Info
 
-	// For now, let's assume that the high-level and low-level pipelines use the same meta-data definitions.
-	// If this doesn't hold, we will need to provide a mechanism for transcoding one into the other here.
+	// TranslatePacketOut translates the given high-level packet-out, including its metadata, into a low-level one.
+	TranslatePacketOut(packetOut *p4api.PacketOut) (*p4api.PacketOut, error)
+
+	// TranslatePacketIn translates the given low-level packet-in, including its metadata, into a high-level one.
+	TranslatePacketIn(packetIn *p4api.PacketIn) (*p4api.PacketIn, error)
+}
+
+// InverseTranslator is an abstraction of a pipeline translator capable of translating low-level pipeline entities,
+// e.g. as read from the device, back into the high-level pipeline entities from which they would have been derived.
+type InverseTranslator interface {
+	// TranslateToLogical translates the given low-level pipeline entities into high-level pipeline ones.
+	// Returns error if any of the entities cannot be translated.
+	TranslateToLogical(entities *[]p4api.Entity) (*[]p4api.Entity, error)
+}
+
+// TranslateToLogical translates the given low-level entities into high-level ones using the specified translator.
+// Returns a not supported error if the translator is not capable of inverse translation.
+func TranslateToLogical(translator PipelineTranslator, entities *[]p4api.Entity) (*[]p4api.Entity, error) {
+	inverse, ok := translator.(InverseTranslator)
+	if !ok {
+		return nil, errors.NewNotSupported("Translator does not support translation of low-level entities")
+	}
+	return inverse.TranslateToLogical(entities)
 }
 
 // Provides identity pipeline entity translation
 type identityTranslator struct {
 	PipelineTranslator
+	*PacketMetadataTranslator
 	p4info *p4info.P4Info
 }
 
 // NewIdentityTranslator returns a new identity pipeline translator for the specified pipeline info
 func NewIdentityTranslator(info *p4info.P4Info) PipelineTranslator {
-	return &identityTranslator{p4info: info}
+	return &identityTranslator{p4info: info, PacketMetadataTranslator: NewPacketMetadataTranslator(info, info)}
 }
 
 // NewIdentityTranslatorFromFile returns a new identity pipeline translator for pipeline info loaded from the given file
@@ -47,8 +71,13 @@ func NewIdentityTranslatorFromFile(path string) (PipelineTranslator, error) {
 }
 
 // Translate returns the same entities as what was provided to it.
-func (t *identityTranslator) Translate(entities *[]p4api.Entity) *[]p4api.Entity {
-	return entities
+func (t *identityTranslator) Translate(entities *[]p4api.Entity) (*[]p4api.Entity, error) {
+	return entities, nil
+}
+
+// TranslateToLogical returns the same entities as what was provided to it.
+func (t *identityTranslator) TranslateToLogical(entities *[]p4api.Entity) (*[]p4api.Entity, error) {
+	return entities, nil
 }
 
 // FromPipeline returns the P4 information describing the high-level pipeline; same as target pipeline
@@ -60,3 +89,13 @@ func (t *identityTranslator) FromPipeline() *p4info.P4Info {
 func (t *identityTranslator) ToPipeline() *p4info.P4Info {
 	return t.p4info
 }
+
+// TranslatePacketOut fills in any padding metadata of the given packet-out.
+func (t *identityTranslator) TranslatePacketOut(packetOut *p4api.PacketOut) (*p4api.PacketOut, error) {
+	return t.PacketMetadataTranslator.TranslatePacketOut(packetOut)
+}
+
+// TranslatePacketIn strips any padding metadata from the given packet-in.
+func (t *identityTranslator) TranslatePacketIn(packetIn *p4api.PacketIn) (*p4api.PacketIn, error) {
+	return t.PacketMetadataTranslator.TranslatePacketIn(packetIn)
+}
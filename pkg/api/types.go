@@ -0,0 +1,316 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/proto"
+	"strings"
+	"sync"
+)
+
+// TypeTranslator translates values of the user-defined types declared in the pipeline type_info between their
+// SDN-visible and data-plane representations. Values of serializable enums are translated between member names,
+// e.g. "CpuLoopbackMode_t.DIRECT", and member values. Values of @p4runtime_translation new types, e.g. ports,
+// are translated using the mapping tables populated via SetMapping; since these tables describe a particular
+// device, each device requires its own type translator. The translation applies to match fields, action
+// parameters and controller packet metadata whose type_name refers to such a type.
+//
+// TypeTranslator preserves the pipeline, and can therefore be composed with other translators via a chain.
+type TypeTranslator struct {
+	info *p4info.P4Info
+
+	mu          sync.RWMutex
+	toDataPlane map[string]map[string][]byte
+	toSDN       map[string]map[string][]byte
+}
+
+// NewTypeTranslator returns a new type translator for the types declared by the given pipeline info.
+func NewTypeTranslator(info *p4info.P4Info) *TypeTranslator {
+	return &TypeTranslator{
+		info:        info,
+		toDataPlane: make(map[string]map[string][]byte),
+		toSDN:       make(map[string]map[string][]byte),
+	}
+}
+
+// SetMapping maps the given SDN value of the specified translated type onto the given data-plane value, replacing
+// any previous mapping of either value. Returns error if the type is not a translated new type.
+func (t *TypeTranslator) SetMapping(typeName string, sdnValue []byte, dataPlaneValue []byte) error {
+	spec := t.translatedType(typeName)
+	if spec == nil {
+		return errors.NewInvalid("Type %s is not a translated type", typeName)
+	}
+	sdnKey := string(canonicalSDNValue(spec, sdnValue))
+	dpValue := canonicalValue(dataPlaneValue)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.toDataPlane[typeName] == nil {
+		t.toDataPlane[typeName] = make(map[string][]byte)
+		t.toSDN[typeName] = make(map[string][]byte)
+	}
+	if previous, ok := t.toDataPlane[typeName][sdnKey]; ok {
+		delete(t.toSDN[typeName], string(previous))
+	}
+	if previous, ok := t.toSDN[typeName][string(dpValue)]; ok {
+		delete(t.toDataPlane[typeName], string(previous))
+	}
+	t.toDataPlane[typeName][sdnKey] = dpValue
+	t.toSDN[typeName][string(dpValue)] = []byte(sdnKey)
+	return nil
+}
+
+// RemoveMapping removes mapping of the given SDN value of the specified translated type, if any.
+func (t *TypeTranslator) RemoveMapping(typeName string, sdnValue []byte) {
+	spec := t.translatedType(typeName)
+	if spec == nil {
+		return
+	}
+	sdnKey := string(canonicalSDNValue(spec, sdnValue))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if dpValue, ok := t.toDataPlane[typeName][sdnKey]; ok {
+		delete(t.toDataPlane[typeName], sdnKey)
+		delete(t.toSDN[typeName], string(dpValue))
+	}
+}
+
+// ToDataPlane translates the given SDN value of the specified type into its data-plane value. Values of types
+// not requiring translation are returned as they are. Returns error if the value cannot be translated.
+func (t *TypeTranslator) ToDataPlane(typeName string, value []byte) ([]byte, error) {
+	if enum := t.info.GetTypeInfo().GetSerializableEnums()[typeName]; enum != nil {
+		name := strings.TrimPrefix(string(value), typeName+".")
+		for _, member := range enum.Members {
+			if member.Name == name {
+				return member.Value, nil
+			}
+		}
+		for _, member := range enum.Members {
+			if bytes.Equal(canonicalValue(member.Value), canonicalValue(value)) {
+				return member.Value, nil
+			}
+		}
+		return nil, errors.NewInvalid("Value %q is not a member of %s", value, typeName)
+	}
+
+	spec := t.translatedType(typeName)
+	if spec == nil {
+		return value, nil
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	dpValue, ok := t.toDataPlane[typeName][string(canonicalSDNValue(spec, value))]
+	if !ok {
+		return nil, errors.NewNotFound("No data-plane value mapped for %s value %q", typeName, value)
+	}
+	return dpValue, nil
+}
+
+// ToSDN translates the given data-plane value of the specified type into its SDN value. Values of types
+// not requiring translation are returned as they are. Returns error if the value cannot be translated.
+func (t *TypeTranslator) ToSDN(typeName string, value []byte) ([]byte, error) {
+	if enum := t.info.GetTypeInfo().GetSerializableEnums()[typeName]; enum != nil {
+		for _, member := range enum.Members {
+			if bytes.Equal(canonicalValue(member.Value), canonicalValue(value)) {
+				return []byte(typeName + "." + member.Name), nil
+			}
+		}
+		return nil, errors.NewInvalid("Value %v is not a member of %s", value, typeName)
+	}
+
+	if t.translatedType(typeName) == nil {
+		return value, nil
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	sdnValue, ok := t.toSDN[typeName][string(canonicalValue(value))]
+	if !ok {
+		return nil, errors.NewNotFound("No SDN value mapped for %s value %v", typeName, value)
+	}
+	return sdnValue, nil
+}
+
+// Returns the translation spec of the named new type, or nil if the type is not a translated one
+func (t *TypeTranslator) translatedType(typeName string) *p4info.P4NewTypeTranslation {
+	return t.info.GetTypeInfo().GetNewTypes()[typeName].GetTranslatedType()
+}
+
+// Returns the canonical form of the SDN value; strings are taken as they are, bit strings are canonicalized
+func canonicalSDNValue(spec *p4info.P4NewTypeTranslation, value []byte) []byte {
+	if spec.GetSdnString() != nil {
+		return value
+	}
+	return canonicalValue(value)
+}
+
+// Returns the canonical bit string representation of the value, i.e. without any leading zero bytes
+func canonicalValue(value []byte) []byte {
+	i := 0
+	for i < len(value)-1 && value[i] == 0 {
+		i++
+	}
+	return value[i:]
+}
+
+// FromPipeline returns the P4 information describing the pipeline; same as target pipeline
+func (t *TypeTranslator) FromPipeline() *p4info.P4Info {
+	return t.info
+}
+
+// ToPipeline returns the P4 information describing the target pipeline; same as high-level pipeline
+func (t *TypeTranslator) ToPipeline() *p4info.P4Info {
+	return t.info
+}
+
+// Translate translates the typed values of the given entities from their SDN representation into the data-plane one.
+func (t *TypeTranslator) Translate(entities *[]p4api.Entity) (*[]p4api.Entity, error) {
+	return t.translateEntities(entities, t.ToDataPlane)
+}
+
+// TranslateToSDN translates the typed values of the given entities, e.g. as read from the device, from their
+// data-plane representation into the SDN one.
+func (t *TypeTranslator) TranslateToSDN(entities *[]p4api.Entity) (*[]p4api.Entity, error) {
+	return t.translateEntities(entities, t.ToSDN)
+}
+
+// TranslateToLogical translates the typed values of the given entities from their data-plane representation into
+// the SDN one; same as TranslateToSDN.
+func (t *TypeTranslator) TranslateToLogical(entities *[]p4api.Entity) (*[]p4api.Entity, error) {
+	return t.TranslateToSDN(entities)
+}
+
+// TranslatePacketOut translates the typed metadata of the given packet-out into their data-plane representation.
+func (t *TypeTranslator) TranslatePacketOut(packetOut *p4api.PacketOut) (*p4api.PacketOut, error) {
+	metadata, err := t.translateMetadata(packetOutHeader, packetOut.Metadata, t.ToDataPlane)
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.PacketOut{Payload: packetOut.Payload, Metadata: metadata}, nil
+}
+
+// TranslatePacketIn translates the typed metadata of the given packet-in into their SDN representation.
+func (t *TypeTranslator) TranslatePacketIn(packetIn *p4api.PacketIn) (*p4api.PacketIn, error) {
+	metadata, err := t.translateMetadata(packetInHeader, packetIn.Metadata, t.ToSDN)
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.PacketIn{Payload: packetIn.Payload, Metadata: metadata}, nil
+}
+
+type valueConverter func(typeName string, value []byte) ([]byte, error)
+
+func (t *TypeTranslator) translateEntities(entities *[]p4api.Entity, convert valueConverter) (*[]p4api.Entity, error) {
+	translated := make([]p4api.Entity, len(*entities))
+	for i := range *entities {
+		entity := proto.Clone(&(*entities)[i]).(*p4api.Entity)
+		if err := t.translateEntity(entity, convert); err != nil {
+			return nil, err
+		}
+		translated[i].Entity = entity.Entity
+	}
+	return &translated, nil
+}
+
+// Translates the typed values of the given entity in place
+func (t *TypeTranslator) translateEntity(entity *p4api.Entity, convert valueConverter) error {
+	if entry := entity.GetTableEntry(); entry != nil {
+		table := FindTableByID(t.info, entry.TableId)
+		if table == nil {
+			return nil
+		}
+		for _, match := range entry.Match {
+			if err := t.translateMatch(table, match, convert); err != nil {
+				return err
+			}
+		}
+		if action := entry.Action.GetAction(); action != nil {
+			return t.translateAction(action, convert)
+		}
+		for _, profileAction := range entry.Action.GetActionProfileActionSet().GetActionProfileActions() {
+			if err := t.translateAction(profileAction.Action, convert); err != nil {
+				return err
+			}
+		}
+	} else if member := entity.GetActionProfileMember(); member != nil && member.Action != nil {
+		return t.translateAction(member.Action, convert)
+	}
+	return nil
+}
+
+func (t *TypeTranslator) translateMatch(table *p4info.Table, match *p4api.FieldMatch, convert valueConverter) error {
+	typeName := FindMatchFieldByID(table, match.FieldId).GetTypeName().GetName()
+	if typeName == "" {
+		return nil
+	}
+	var err error
+	switch m := match.FieldMatchType.(type) {
+	case *p4api.FieldMatch_Exact_:
+		m.Exact.Value, err = convert(typeName, m.Exact.Value)
+	case *p4api.FieldMatch_Ternary_:
+		m.Ternary.Value, err = convert(typeName, m.Ternary.Value)
+	case *p4api.FieldMatch_Lpm:
+		m.Lpm.Value, err = convert(typeName, m.Lpm.Value)
+	case *p4api.FieldMatch_Optional_:
+		m.Optional.Value, err = convert(typeName, m.Optional.Value)
+	case *p4api.FieldMatch_Range_:
+		if m.Range.Low, err = convert(typeName, m.Range.Low); err == nil {
+			m.Range.High, err = convert(typeName, m.Range.High)
+		}
+	}
+	if err != nil {
+		return errors.New(errors.TypeOf(err), "Table %s field %d: %s", table.Preamble.Name, match.FieldId, err.Error())
+	}
+	return nil
+}
+
+func (t *TypeTranslator) translateAction(action *p4api.Action, convert valueConverter) error {
+	var info *p4info.Action
+	for _, a := range t.info.GetActions() {
+		if a.Preamble.Id == action.ActionId {
+			info = a
+			break
+		}
+	}
+	if info == nil {
+		return nil
+	}
+	for _, param := range action.Params {
+		for _, p := range info.Params {
+			if p.Id != param.ParamId || p.GetTypeName().GetName() == "" {
+				continue
+			}
+			value, err := convert(p.TypeName.Name, param.Value)
+			if err != nil {
+				return errors.New(errors.TypeOf(err), "Action %s param %s: %s", info.Preamble.Name, p.Name, err.Error())
+			}
+			param.Value = value
+		}
+	}
+	return nil
+}
+
+func (t *TypeTranslator) translateMetadata(header string, metadata []*p4api.PacketMetadata, convert valueConverter) ([]*p4api.PacketMetadata, error) {
+	cpm := findControllerHeader(t.info, header)
+	translated := make([]*p4api.PacketMetadata, 0, len(metadata))
+	for _, md := range metadata {
+		value := md.Value
+		for _, m := range cpm.GetMetadata() {
+			if m.Id != md.MetadataId || m.GetTypeName().GetName() == "" {
+				continue
+			}
+			var err error
+			if value, err = convert(m.TypeName.Name, md.Value); err != nil {
+				return nil, errors.New(errors.TypeOf(err), "Metadata %s of %s: %s", m.Name, header, err.Error())
+			}
+		}
+		translated = append(translated, &p4api.PacketMetadata{MetadataId: md.MetadataId, Value: value})
+	}
+	return translated, nil
+}
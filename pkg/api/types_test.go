@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const portType = "FabricPortId_t"
+
+func newTestTypeTranslator(t *testing.T) *TypeTranslator {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	// Declare the packet-out loopback mode as the serializable enum it represents
+	for _, md := range findControllerHeader(info, packetOutHeader).Metadata {
+		if md.Name == "cpu_loopback_mode" {
+			md.TypeName = &p4info.P4NamedType{Name: "CpuLoopbackMode_t"}
+		}
+	}
+
+	translator := NewTypeTranslator(info)
+	assert.NoError(t, translator.SetMapping(portType, []byte{0, 0, 0, 1}, []byte{1, 4}))
+	assert.NoError(t, translator.SetMapping(portType, []byte{2}, []byte{1, 8}))
+	return translator
+}
+
+func TestTypeValues(t *testing.T) {
+	translator := newTestTypeTranslator(t)
+
+	value, err := translator.ToDataPlane(portType, []byte{1})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 4}, value)
+	value, err = translator.ToSDN(portType, []byte{0, 1, 8})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{2}, value)
+
+	_, err = translator.ToDataPlane(portType, []byte{3})
+	assert.True(t, errors.IsNotFound(err))
+
+	// Remapping a value must drop its previous mapping
+	assert.NoError(t, translator.SetMapping(portType, []byte{1}, []byte{1, 12}))
+	_, err = translator.ToSDN(portType, []byte{1, 4})
+	assert.True(t, errors.IsNotFound(err))
+	translator.RemoveMapping(portType, []byte{1})
+	_, err = translator.ToDataPlane(portType, []byte{1})
+	assert.True(t, errors.IsNotFound(err))
+
+	assert.True(t, errors.IsInvalid(translator.SetMapping("CpuLoopbackMode_t", []byte{1}, []byte{1})))
+
+	value, err = translator.ToDataPlane("CpuLoopbackMode_t", []byte("CpuLoopbackMode_t.DIRECT"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1}, value)
+	value, err = translator.ToDataPlane("CpuLoopbackMode_t", []byte("INGRESS"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{2}, value)
+	value, err = translator.ToSDN("CpuLoopbackMode_t", []byte{0})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("CpuLoopbackMode_t.DISABLED"), value)
+	_, err = translator.ToDataPlane("CpuLoopbackMode_t", []byte("BOGUS"))
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestTypeTranslatorEntities(t *testing.T) {
+	translator := newTestTypeTranslator(t)
+
+	entities := []p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{
+		TableId: flowsTable,
+		Match: []*p4api.FieldMatch{
+			{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: []byte{10, 0, 0, 1}, Mask: []byte{255, 0, 0, 0}}}},
+			{FieldId: 6, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: []byte{1}}}},
+		},
+		Action: &p4api.TableAction{Type: &p4api.TableAction_Action{Action: &p4api.Action{ActionId: 21929788,
+			Params: []*p4api.Action_Param{{ParamId: 1, Value: []byte{7}}}}}},
+	}}}}
+
+	translated, err := translator.Translate(&entities)
+	assert.NoError(t, err)
+	entry := (*translated)[0].GetTableEntry()
+	assert.Equal(t, []byte{10, 0, 0, 1}, entry.Match[0].GetTernary().Value)
+	assert.Equal(t, []byte{1, 4}, entry.Match[1].GetExact().Value)
+	assert.Equal(t, []byte{7}, entry.Action.GetAction().Params[0].Value)
+	assert.Equal(t, []byte{1}, entities[0].GetTableEntry().Match[1].GetExact().Value)
+
+	sdn, err := translator.TranslateToSDN(translated)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1}, (*sdn)[0].GetTableEntry().Match[1].GetExact().Value)
+
+	entities[0].GetTableEntry().Match[1].GetExact().Value = []byte{9}
+	_, err = translator.Translate(&entities)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestTypeTranslatorPackets(t *testing.T) {
+	translator := newTestTypeTranslator(t)
+
+	chain, err := NewTranslatorChain(translator, NewIdentityTranslator(translator.ToPipeline()))
+	assert.NoError(t, err)
+
+	packetOut, err := chain.TranslatePacketOut(&p4api.PacketOut{Metadata: []*p4api.PacketMetadata{
+		{MetadataId: 2, Value: []byte{2}},
+		{MetadataId: 6, Value: []byte("CpuLoopbackMode_t.DIRECT")},
+	}})
+	assert.NoError(t, err)
+	values := make(map[uint32][]byte)
+	for _, md := range packetOut.Metadata {
+		values[md.MetadataId] = md.Value
+	}
+	assert.Equal(t, []byte{1, 8}, values[2])
+	assert.Equal(t, []byte{1}, values[6])
+
+	packetIn, err := chain.TranslatePacketIn(&p4api.PacketIn{Metadata: []*p4api.PacketMetadata{
+		{MetadataId: 1, Value: []byte{0}},
+		{MetadataId: 2, Value: []byte{1, 4}},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, []*p4api.PacketMetadata{{MetadataId: 2, Value: []byte{1}}}, packetIn.Metadata)
+}
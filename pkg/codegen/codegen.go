@@ -0,0 +1,449 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package codegen generates Go bindings for the tables, actions and controller packet metadata of a P4 pipeline
+package codegen
+
+import (
+	"bytes"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Generate produces the source of a Go package with the given name, providing typed bindings for the pipeline
+// described by the given P4Info. The source is the name of the P4Info file, as recorded in the generated header.
+func Generate(info *p4info.P4Info, packageName string, source string) ([]byte, error) {
+	model, err := newModel(info, packageName, source)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err = bindingsTemplate.Execute(buf, model); err != nil {
+		return nil, errors.NewInternal("Unable to generate bindings: %+v", err)
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.NewInternal("Unable to format generated bindings: %+v", err)
+	}
+	return code, nil
+}
+
+type model struct {
+	Package string
+	Source  string
+	Tables  []*tableModel
+	Actions []*actionModel
+	Headers []*headerModel
+}
+
+type tableModel struct {
+	Name    string
+	GoName  string
+	ID      uint32
+	Fields  []*valueModel
+	Actions []*actionModel
+}
+
+type actionModel struct {
+	Name   string
+	GoName string
+	ID     uint32
+	Params []*valueModel
+	Tables []*tableModel
+}
+
+type headerModel struct {
+	Name     string
+	GoName   string
+	Metadata []*valueModel
+}
+
+type valueModel struct {
+	Name     string
+	GoName   string
+	ArgName  string
+	ID       uint32
+	Kind     string
+	GoType   string
+	Bitwidth int32
+}
+
+func newModel(info *p4info.P4Info, packageName string, source string) (*model, error) {
+	m := &model{Package: packageName, Source: source}
+	// Every top-level identifier emitted for a P4 object must be unique, also with respect to the fixed ones
+	names := make(map[string]string)
+	for _, goName := range []string{"Value", "Ternary", "LPM", "Range", "Optional", "Action"} {
+		names[goName] = "the generated code"
+	}
+	declare := func(name string, goNames ...string) error {
+		for _, goName := range goNames {
+			if other, ok := names[goName]; ok {
+				return errors.NewInvalid("Names %s and %s both map onto Go identifier %s", other, name, goName)
+			}
+			names[goName] = name
+		}
+		return nil
+	}
+
+	actions := make(map[uint32]*actionModel)
+	for _, a := range info.GetActions() {
+		am := &actionModel{Name: a.Preamble.Name, GoName: goName(a.Preamble.Name) + "Action", ID: a.Preamble.Id}
+		if err := declare(am.Name, am.GoName, "New"+am.GoName, am.GoName+"ID"); err != nil {
+			return nil, err
+		}
+		for _, p := range a.Params {
+			am.Params = append(am.Params, newValueModel(info, p.Name, p.Id, "", p.GetTypeName(), p.Bitwidth))
+		}
+		actions[am.ID] = am
+		m.Actions = append(m.Actions, am)
+	}
+
+	for _, t := range info.GetTables() {
+		tm := &tableModel{Name: t.Preamble.Name, GoName: goName(t.Preamble.Name), ID: t.Preamble.Id}
+		if err := declare(tm.Name, tm.GoName+"Entry", tm.GoName+"TableID", tm.GoName+"TableAction"); err != nil {
+			return nil, err
+		}
+		for _, mf := range t.MatchFields {
+			tm.Fields = append(tm.Fields, newValueModel(info, mf.Name, mf.Id, matchKind(mf), mf.GetTypeName(), mf.Bitwidth))
+		}
+		for _, ref := range t.ActionRefs {
+			am, ok := actions[ref.Id]
+			if !ok {
+				return nil, errors.NewInvalid("Table %s refers to unknown action %d", t.Preamble.Name, ref.Id)
+			}
+			tm.Actions = append(tm.Actions, am)
+			am.Tables = append(am.Tables, tm)
+		}
+		m.Tables = append(m.Tables, tm)
+	}
+
+	for _, cpm := range info.GetControllerPacketMetadata() {
+		hm := &headerModel{Name: cpm.Preamble.Name, GoName: goName(cpm.Preamble.Name) + "Metadata"}
+		if err := declare(hm.Name, hm.GoName, "Decode"+hm.GoName); err != nil {
+			return nil, err
+		}
+		for _, md := range cpm.Metadata {
+			if !api.IsPadding(md) {
+				hm.Metadata = append(hm.Metadata, newValueModel(info, md.Name, md.Id, "", md.GetTypeName(), md.Bitwidth))
+			}
+		}
+		m.Headers = append(m.Headers, hm)
+	}
+
+	sort.Slice(m.Tables, func(i, j int) bool { return m.Tables[i].Name < m.Tables[j].Name })
+	sort.Slice(m.Actions, func(i, j int) bool { return m.Actions[i].Name < m.Actions[j].Name })
+	sort.Slice(m.Headers, func(i, j int) bool { return m.Headers[i].Name < m.Headers[j].Name })
+	return m, nil
+}
+
+func newValueModel(info *p4info.P4Info, name string, id uint32, kind string, typeName *p4info.P4NamedType, bitwidth int32) *valueModel {
+	goType := "uint64"
+	if translated := info.GetTypeInfo().GetNewTypes()[typeName.GetName()].GetTranslatedType(); translated != nil {
+		if translated.GetSdnString() != nil {
+			goType = "[]byte"
+		}
+		bitwidth = translated.GetSdnBitwidth()
+	}
+	if bitwidth > 64 {
+		goType = "[]byte"
+	}
+	arg := goName(name)
+	arg = string(unicode.ToLower(rune(arg[0]))) + arg[1:]
+	if token.IsKeyword(arg) {
+		arg += "_"
+	}
+	return &valueModel{Name: name, GoName: goName(name), ArgName: arg, ID: id, Kind: kind, GoType: goType, Bitwidth: bitwidth}
+}
+
+func matchKind(mf *p4info.MatchField) string {
+	switch mf.GetMatchType() {
+	case p4info.MatchField_TERNARY:
+		return "Ternary"
+	case p4info.MatchField_LPM:
+		return "LPM"
+	case p4info.MatchField_RANGE:
+		return "Range"
+	case p4info.MatchField_OPTIONAL:
+		return "Optional"
+	default:
+		return "Exact"
+	}
+}
+
+// Converts the given P4 name into an exported Go identifier, e.g. FabricIngress.stats.flows into FabricIngressStatsFlows
+func goName(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if sb.Len() == 0 && unicode.IsDigit(r) {
+			sb.WriteRune('X')
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+var bindingsTemplate = template.Must(template.New("bindings").Parse(`// Code generated by p4gen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+// Value is the type of match field, action parameter and packet metadata values; values of up to 64 bits
+// are represented as unsigned integers and wider values as byte strings
+type Value interface {
+	uint64 | []byte
+}
+
+// Ternary is the value and mask of a ternary match
+type Ternary[T Value] struct {
+	Value T
+	Mask  T
+}
+
+// LPM is the value and prefix length of a longest-prefix match
+type LPM[T Value] struct {
+	Value     T
+	PrefixLen int32
+}
+
+// Range is the inclusive bounds of a range match
+type Range[T Value] struct {
+	Low  T
+	High T
+}
+
+// Optional is the value of an optional match
+type Optional[T Value] struct {
+	Value T
+}
+
+// Action is an action of the pipeline
+type Action interface {
+	// P4Action returns the P4Runtime representation of the action; returns error if any of its parameters does not
+	// fit in its bitwidth
+	P4Action() (*p4api.Action, error)
+}
+
+// Encodes the value into its canonical byte string
+func encode[T Value](value T) []byte {
+	switch v := any(value).(type) {
+	case uint64:
+		b := make([]byte, 0, 8)
+		for shift := 56; shift >= 0; shift -= 8 {
+			if c := byte(v >> shift); c != 0 || len(b) > 0 || shift == 0 {
+				b = append(b, c)
+			}
+		}
+		return b
+	case []byte:
+		return v
+	}
+	return nil
+}
+
+// Accumulates the first error encountered while encoding values
+type encoder struct {
+	err error
+}
+
+// Encodes the value of the named field into its canonical byte string, recording an error if an integer value does
+// not fit in the given bitwidth; values with no bitwidth, i.e. strings, are not checked
+func encodeField[T Value](enc *encoder, value T, bitwidth int32, name string) []byte {
+	if v, ok := any(value).(uint64); ok && bitwidth > 0 && bitwidth < 64 && v>>bitwidth != 0 && enc.err == nil {
+		enc.err = errors.NewInvalid("Value %d of %s does not fit in %d bits", v, name, bitwidth)
+	}
+	return encode(value)
+}
+
+// Decodes the byte string into a value
+func decode[T Value](b []byte) T {
+	var value T
+	switch v := any(&value).(type) {
+	case *uint64:
+		for _, c := range b {
+			*v = *v<<8 | uint64(c)
+		}
+	case *[]byte:
+		*v = b
+	}
+	return value
+}
+
+// Pipeline table IDs
+const (
+{{- range .Tables}}
+	// {{.GoName}}TableID is the ID of table {{.Name}}
+	{{.GoName}}TableID uint32 = {{.ID}}
+{{- end}}
+)
+
+// Pipeline action IDs
+const (
+{{- range .Actions}}
+	// {{.GoName}}ID is the ID of action {{.Name}}
+	{{.GoName}}ID uint32 = {{.ID}}
+{{- end}}
+)
+{{range $t := .Tables}}
+// {{.GoName}}TableAction is an action allowed for table {{.Name}}
+type {{.GoName}}TableAction interface {
+	Action
+	is{{.GoName}}TableAction()
+}
+
+// {{.GoName}}Entry is an entry of table {{.Name}}
+type {{.GoName}}Entry struct {
+{{- range .Fields}}
+	{{if eq .Kind "Exact"}}{{.GoName}} {{.GoType}}{{else}}{{.GoName}} *{{.Kind}}[{{.GoType}}]{{end}}
+{{- end}}
+	Priority        int32
+	IsDefaultAction bool
+	Action          {{.GoName}}TableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *{{.GoName}}Entry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: {{.GoName}}TableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+{{- if .Fields}}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+{{- range .Fields}}
+{{- if eq .Kind "Exact"}}
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: {{.ID}}, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.{{.GoName}}, {{.Bitwidth}}, "{{.Name}}")}}})
+{{- else}}
+		if e.{{.GoName}} != nil {
+{{- if eq .Kind "Ternary"}}
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: {{.ID}}, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.{{.GoName}}.Value, {{.Bitwidth}}, "{{.Name}}"), Mask: encodeField(enc, e.{{.GoName}}.Mask, {{.Bitwidth}}, "{{.Name}}")}}})
+{{- else if eq .Kind "LPM"}}
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: {{.ID}}, FieldMatchType: &p4api.FieldMatch_Lpm{Lpm: &p4api.FieldMatch_LPM{Value: encodeField(enc, e.{{.GoName}}.Value, {{.Bitwidth}}, "{{.Name}}"), PrefixLen: e.{{.GoName}}.PrefixLen}}})
+{{- else if eq .Kind "Range"}}
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: {{.ID}}, FieldMatchType: &p4api.FieldMatch_Range_{Range: &p4api.FieldMatch_Range{Low: encodeField(enc, e.{{.GoName}}.Low, {{.Bitwidth}}, "{{.Name}}"), High: encodeField(enc, e.{{.GoName}}.High, {{.Bitwidth}}, "{{.Name}}")}}})
+{{- else}}
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: {{.ID}}, FieldMatchType: &p4api.FieldMatch_Optional_{Optional: &p4api.FieldMatch_Optional{Value: encodeField(enc, e.{{.GoName}}.Value, {{.Bitwidth}}, "{{.Name}}")}}})
+{{- end}}
+		}
+{{- end}}
+{{- end}}
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+{{- end}}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *{{.GoName}}Entry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *{{.GoName}}Entry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+{{end}}
+{{- range .Actions}}
+// {{.GoName}} is action {{.Name}}
+type {{.GoName}} struct {
+{{- range .Params}}
+	{{.GoName}} {{.GoType}}
+{{- end}}
+}
+
+// New{{.GoName}} returns action {{.Name}} with the given parameters
+func New{{.GoName}}({{range $i, $p := .Params}}{{if $i}}, {{end}}{{.ArgName}} {{.GoType}}{{end}}) *{{.GoName}} {
+	return &{{.GoName}}{ {{- range $i, $p := .Params}}{{if $i}}, {{end}}{{.GoName}}: {{.ArgName}}{{end -}} }
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *{{.GoName}}) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: {{.GoName}}ID}
+{{- if .Params}}
+	enc := &encoder{}
+{{- range .Params}}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: {{.ID}}, Value: encodeField(enc, a.{{.GoName}}, {{.Bitwidth}}, "{{.Name}}")})
+{{- end}}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+{{- end}}
+	return action, nil
+}
+{{$a := .}}
+{{- range .Tables}}
+func (a *{{$a.GoName}}) is{{.GoName}}TableAction() {}
+{{- end}}
+{{end}}
+{{- range .Headers}}
+// {{.GoName}} holds the metadata of controller header {{.Name}}, without any padding
+type {{.GoName}} struct {
+{{- range .Metadata}}
+	{{.GoName}} {{.GoType}}
+{{- end}}
+}
+
+// PacketMetadata returns the P4Runtime representation of the metadata; returns error if any of the values does
+// not fit in its bitwidth
+func (m *{{.GoName}}) PacketMetadata() ([]*p4api.PacketMetadata, error) {
+	enc := &encoder{}
+	metadata := []*p4api.PacketMetadata{
+{{- range .Metadata}}
+		{MetadataId: {{.ID}}, Value: encodeField(enc, m.{{.GoName}}, {{.Bitwidth}}, "{{.Name}}")},
+{{- end}}
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return metadata, nil
+}
+
+// Decode{{.GoName}} decodes the given P4Runtime metadata, ignoring any unknown metadata
+func Decode{{.GoName}}(metadata []*p4api.PacketMetadata) *{{.GoName}} {
+	m := &{{.GoName}}{}
+	for _, md := range metadata {
+		switch md.MetadataId {
+{{- range .Metadata}}
+		case {{.ID}}:
+			m.{{.GoName}} = decode[{{.GoType}}](md.Value)
+{{- end}}
+		}
+	}
+	return m
+}
+{{end}}`))
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package codegen
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"os"
+	"testing"
+)
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, "FabricIngressStatsFlows", goName("FabricIngress.stats.flows"))
+	assert.Equal(t, "SetNextIdRoutingV4", goName("set_next_id_routing_v4"))
+	assert.Equal(t, "Pad0", goName("_pad0"))
+	assert.Equal(t, "X8021q", goName("8021q"))
+}
+
+// Validates that the checked-in fabric bindings are up-to-date with the generator and the test pipeline
+func TestGenerateFabric(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	code, err := Generate(info, "fabric", "p4info.txt")
+	assert.NoError(t, err)
+	expected, err := os.ReadFile("../../test/fabric/p4info.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(code), "test/fabric/p4info.go is stale; run go generate ./test/fabric")
+}
+
+func TestGenerateNameClash(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	clone := proto.Clone(info.Tables[0]).(*p4info.Table)
+	clone.Preamble.Id++
+	clone.Preamble.Name = clone.Preamble.Name + "_"
+	info.Tables = append(info.Tables, clone)
+	_, err = Generate(info, "fabric", "p4info.txt")
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestGenerateNameClashAcrossKinds(t *testing.T) {
+	for _, name := range []string{
+		"FabricIngress.stats.flows.table", // FabricIngressStatsFlowsTableAction is the action interface of the table
+		"new.FabricIngress.stats.count",   // NewFabricIngressStatsCountAction is the constructor of another action
+	} {
+		info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+		assert.NoError(t, err)
+
+		clone := proto.Clone(info.Actions[0]).(*p4info.Action)
+		clone.Preamble.Id++
+		clone.Preamble.Name = name
+		info.Actions = append(info.Actions, clone)
+		_, err = Generate(info, "fabric", "p4info.txt")
+		assert.True(t, errors.IsInvalid(err), name)
+	}
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+// Seeds the empty store with the logical entities from which the programmable entities present on the device would
+// have been derived, so that they are preserved by the reconciliation; entities which cannot be translated into
+// logical ones are left out, as are entities other than table entries, which the store does not persist. Does nothing
+// if a logical intent is persisted already. Returns the number of adopted entities.
+func (d *deviceController) adoptDevice(ctx context.Context) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	intent, err := readIntent(ctx, d.store)
+	if err != nil {
+		return 0, err
+	}
+	if len(intent) > 0 {
+		log.Infof("Device %s: Logical intent of %d entities exists; skipping adoption", d.id, len(intent))
+		return 0, nil
+	}
+
+	present, err := d.southbound.Read(ctx, driftQuery())
+	if err != nil {
+		return 0, err
+	}
+	info := d.translator.ToPipeline()
+	adopted := make(map[string]bool)
+	updates := make([]*p4api.Update, 0, len(present))
+	skipped := 0
+	for _, e := range present {
+		if !isProgrammable(e) {
+			continue
+		}
+		if e.GetTableEntry() == nil {
+			log.Warnf("Device %s: Unable to adopt %s: only table entries can be adopted", d.id, api.FormatEntity(info, e))
+			skipped++
+			continue
+		}
+		logical, err := d.adopt(ctx, e)
+		if err == nil && adopted[api.EntityKey(logical)] {
+			err = errors.NewConflict("logical entity has been adopted already")
+		}
+		if err != nil {
+			log.Warnf("Device %s: Unable to adopt %s: %+v", d.id, api.FormatEntity(info, e), err)
+			skipped++
+			continue
+		}
+		adopted[api.EntityKey(logical)] = true
+		updates = append(updates, &p4api.Update{Type: p4api.Update_INSERT, Entity: logical})
+	}
+
+	if err = d.store.Write(ctx, updates); err != nil {
+		log.Warnf("Device %s: Unable to adopt %d entities: %+v", d.id, len(updates), err)
+		return 0, err
+	}
+	d.recordDerived(updates)
+	log.Infof("Device %s: Adopted %d entities; %d could not be adopted", d.id, len(updates), skipped)
+	return len(updates), nil
+}
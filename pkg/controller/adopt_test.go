@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAdoptDevice(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	device := parseEntities(t, info,
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0/8 -> set_next_id_routing_v4(next_id=5)",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.1.0.0/16 -> set_next_id_routing_v4(next_id=6)",
+		"FabricIngress.forwarding.mpls mpls_label=100 -> pop_mpls_and_next(next_id=3)",
+	)
+	// Entries not complying with the pipeline cannot be adopted
+	device = append(device, &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{TableId: 1234}}})
+	for _, e := range device {
+		sb.entities[api.EntityKey(e)] = e
+	}
+
+	adopted, err := d.adoptDevice(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, adopted)
+	intent, err := readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 3)
+	assert.Equal(t, 0, sb.writes)
+
+	// Adopted entities must be preserved by the reconciliation
+	report, err := d.Reconcile(ctx)
+	assert.NoError(t, err)
+	for _, table := range report.Tables {
+		assert.Empty(t, table.Drift.Missing, table.Table)
+		assert.Empty(t, table.Drift.Differing, table.Table)
+	}
+	assert.Len(t, sb.entities, 3)
+
+	// Adoption must not take place once there is a logical intent
+	extra := parseEntities(t, info, "FabricIngress.forwarding.routing_v4 ipv4_dst=10.2.0.0/16 -> set_next_id_routing_v4(next_id=7)")[0]
+	sb.entities[api.EntityKey(extra)] = extra
+	adopted, err = d.adoptDevice(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, adopted)
+	intent, err = readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 3)
+}
+
+func TestAdoptDeviceWithoutInverse(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	d.translator = &priorityTranslator{PipelineTranslator: api.NewIdentityTranslator(info)}
+	for _, u := range generateUpdates(info, 4) {
+		sb.entities[api.EntityKey(u.Entity)] = u.Entity
+	}
+
+	adopted, err := d.adoptDevice(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, adopted)
+	intent, err := readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Empty(t, intent)
+}
+
+func TestAdoptDeviceTableEntriesOnly(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	route := parseEntities(t, info, "FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0/8 -> set_next_id_routing_v4(next_id=5)")[0]
+	member := &p4api.Entity{Entity: &p4api.Entity_ActionProfileMember{ActionProfileMember: &p4api.ActionProfileMember{
+		ActionProfileId: 1, MemberId: 1, Action: &p4api.Action{ActionId: 1}}}}
+	for _, e := range []*p4api.Entity{route, member} {
+		sb.entities[api.EntityKey(e)] = e
+	}
+
+	// Entities other than table entries are not persisted by the store and must not be counted as adopted
+	adopted, err := d.adoptDevice(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, adopted)
+	intent, err := readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 1)
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-control/pkg/store"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+// Checkpoint captures the current logical intent of the device under the given name
+func (d *deviceController) Checkpoint(ctx context.Context, name string) (*api.Checkpoint, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.store.Checkpoint(ctx, name)
+}
+
+// Checkpoints returns descriptors of all checkpoints of the device, ordered by their creation time
+func (d *deviceController) Checkpoints(ctx context.Context) ([]*api.Checkpoint, error) {
+	return d.store.Checkpoints(ctx)
+}
+
+// DeleteCheckpoint deletes the given checkpoint
+func (d *deviceController) DeleteCheckpoint(ctx context.Context, name string) error {
+	return d.store.DeleteCheckpoint(ctx, name)
+}
+
+// Rollback restores the logical intent captured by the given checkpoint, applying its difference from the current
+// intent as a single transactional write; table entries are restored along with the intents to which they belonged.
+// Checkpoints holding entities other than table entries cannot be restored.
+func (d *deviceController) Rollback(ctx context.Context, name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	target, intents, err := d.store.CheckpointEntities(ctx, name)
+	if err != nil {
+		return err
+	}
+	current, err := readIntent(ctx, d.store)
+	if err != nil {
+		return err
+	}
+	diff := diffEntities(current, target)
+	updates := append(updatesOf(diff.breaks), updatesOf(diff.makes)...)
+	opts, err := d.restoreIntents(ctx, target, intents, diff, &updates)
+	if err != nil {
+		return err
+	}
+	log.Infof("Device %s: Rolling back to checkpoint %s; %d makes, %d breaks", d.id, name, len(diff.makes), len(diff.breaks))
+	return d.write(ctx, updates, opts...)
+}
+
+// Returns the write options associating the table entries made by the rollback with their intents in the checkpoint,
+// adding modifications of the entries which remain intact but whose intent has changed since; returns no options if
+// the checkpoint does not record intents
+func (d *deviceController) restoreIntents(ctx context.Context, target []*p4api.Entity, intents []*api.Intent, diff *entityDiff, updates *[]*p4api.Update) ([]store.WriteOption, error) {
+	if intents == nil {
+		return nil, nil
+	}
+	made := make(map[string]*p4api.Update, len(diff.makes))
+	for _, c := range diff.makes {
+		made[api.EntityKey(c.update.Entity)] = c.update
+	}
+	opts := make([]store.WriteOption, 0)
+	for i, entity := range target {
+		if entity.GetTableEntry() == nil {
+			continue
+		}
+		if update, ok := made[api.EntityKey(entity)]; ok {
+			opts = append(opts, store.WithIntent(update, intents[i]))
+			continue
+		}
+		intent, err := d.store.IntentOf(ctx, entity)
+		if err != nil {
+			return nil, err
+		}
+		if !sameIntent(intent, intents[i]) {
+			update := &p4api.Update{Type: p4api.Update_MODIFY, Entity: entity}
+			*updates = append(*updates, update)
+			opts = append(opts, store.WithIntent(update, intents[i]))
+		}
+	}
+	return opts, nil
+}
+
+// Returns true if the given intents, either of which may be nil, are the same
+func sameIntent(a *api.Intent, b *api.Intent) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.ID != b.ID || len(a.Labels) != len(b.Labels) {
+		return false
+	}
+	for k, v := range a.Labels {
+		if w, ok := b.Labels[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
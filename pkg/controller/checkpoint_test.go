@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+func TestRollback(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	updates := generateUpdates(info, 8)
+	seed(ctx, t, d, sb, updates)
+	host := &api.Intent{ID: "host"}
+	assert.NoError(t, d.WriteIntent(ctx, host, request(modifiesOf(updates[5:]))))
+
+	checkpoint, err := d.Checkpoint(ctx, "before")
+	assert.NoError(t, err)
+	assert.Equal(t, 8, checkpoint.Entities)
+
+	// Delete a few entries, modify some and insert new ones
+	changes := make([]*p4api.Update, 0)
+	for _, u := range updates[:3] {
+		changes = append(changes, &p4api.Update{Type: p4api.Update_DELETE, Entity: u.Entity})
+	}
+	for _, u := range updates[3:5] {
+		entry := proto.Clone(u.Entity.GetTableEntry()).(*p4api.TableEntry)
+		entry.Action.GetAction().Params[0].Value = []byte{2}
+		changes = append(changes, &p4api.Update{Type: p4api.Update_MODIFY, Entity: &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}})
+	}
+	changes = append(changes, generateUpdates(info, 4)...)
+	assert.NoError(t, d.Write(ctx, request(changes)))
+	assert.Len(t, sb.entities, 9)
+	assert.NoError(t, d.WriteIntent(ctx, &api.Intent{ID: "other"}, request(modifiesOf(updates[5:6]))))
+
+	// A failed rollback must leave both the device and the store intact
+	sb.failOn = sb.writes + 1
+	assert.Error(t, d.Rollback(ctx, "before"))
+	assert.Len(t, sb.entities, 9)
+	intent, err := readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 9)
+
+	assert.NoError(t, d.Rollback(ctx, "before"))
+	assert.Len(t, sb.entities, 8)
+	for _, u := range updates {
+		if assert.Contains(t, sb.entities, api.EntityKey(u.Entity)) {
+			assert.True(t, proto.Equal(u.Entity, sb.entities[api.EntityKey(u.Entity)]))
+		}
+	}
+	intent, err = readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 8)
+
+	// Entries must be restored along with the intents they belonged to
+	entities, err := api.ReadAll(d.ReadIntent(ctx, "host"))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 3)
+	entities, err = api.ReadAll(d.ReadIntent(ctx, "other"))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 0)
+
+	checkpoints, err := d.Checkpoints(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, checkpoints, 1)
+	assert.NoError(t, d.DeleteCheckpoint(ctx, "before"))
+	assert.True(t, errors.IsNotFound(d.Rollback(ctx, "before")))
+}
+
+// Returns modifications of the entities of the given updates, leaving them as they are
+func modifiesOf(updates []*p4api.Update) []*p4api.Update {
+	modifies := make([]*p4api.Update, 0, len(updates))
+	for _, u := range updates {
+		modifies = append(modifies, &p4api.Update{Type: p4api.Update_MODIFY, Entity: u.Entity})
+	}
+	return modifies
+}
@@ -12,24 +12,33 @@ import (
 	"github.com/onosproject/onos-control/pkg/store"
 	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
 	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"sync"
 )
 
 type deviceController struct {
 	api.DeviceControl
 	id         topo.ID
 	endpoint   string
-	translator api.PipelineTranslator
 	store      store.EntityStore
+	southbound southbound
 	version    string
 	state      api.State
+	policies   reconcilePolicies
+
+	mu         sync.RWMutex
+	translator api.PipelineTranslator
+	// derived indicates that the record of derived entities of an update translator reflects the persisted intent
+	derived bool
 }
 
-func newDeviceController(id topo.ID, endpoint string, entityStore store.EntityStore, translator api.PipelineTranslator) api.DeviceControl {
+func newDeviceController(id topo.ID, endpoint string, entityStore store.EntityStore, translator api.PipelineTranslator, sb southbound) *deviceController {
 	return &deviceController{
 		id:         id,
 		endpoint:   endpoint,
 		translator: translator,
 		store:      entityStore,
+		southbound: sb,
+		policies:   newReconcilePolicies(),
 	}
 }
 
@@ -39,33 +48,55 @@ func (d *deviceController) State() api.State {
 	return d.state
 }
 
-// Read receives a query and returns back all requested control entries on the given channel
-func (d *deviceController) Read(ctx context.Context, entities *[]p4api.Entity, ch chan<- []*p4api.Entity) error {
-	// TODO: Implement me
-	return nil
-}
-
-// Write applies a set of updates to the device
-func (d *deviceController) Write(ctx context.Context, request *[]p4api.Update) error {
-	// TODO: Implement me
-	// Write to the store; notify reconciler to translate and contact southbound
-	return nil
+// Read receives a query and returns an iterator over all requested control entries, as persisted in the store
+func (d *deviceController) Read(ctx context.Context, entities *[]p4api.Entity) api.EntityIterator {
+	query := make([]*p4api.Entity, len(*entities))
+	for i := range *entities {
+		query[i] = &(*entities)[i]
+	}
+	return d.store.Read(ctx, query)
 }
 
 // EmitPacket requests emission of the specified packet onto the data-plane
 func (d *deviceController) EmitPacket(ctx context.Context, packetOut *p4api.PacketOut) error {
-	// TODO: Implement me
-	// Go directly to the southbound
-	return nil
+	d.mu.RLock()
+	translator := d.translator
+	d.mu.RUnlock()
+
+	physical, err := translator.TranslatePacketOut(packetOut)
+	if err != nil {
+		return err
+	}
+	return d.southbound.EmitPacket(ctx, physical)
 }
 
 // HandlePackets starts handling the packet-in message using the supplied channel and packet handler
 func (d *deviceController) HandlePackets(ch chan<- *p4api.PacketIn, handler *api.PacketHandler) {
-	// Pass the channel and handler directly to the southbound
+	d.southbound.HandlePackets(func(packetIn *p4api.PacketIn) {
+		d.mu.RLock()
+		translator := d.translator
+		d.mu.RUnlock()
+
+		logical, err := translator.TranslatePacketIn(packetIn)
+		if err != nil {
+			log.Warnf("Device %s: Unable to translate packet-in: %+v", d.id, err)
+			return
+		}
+		if ch != nil {
+			ch <- logical
+		}
+		if handler != nil && *handler != nil {
+			if err = (*handler).Handle(logical); err != nil {
+				log.Warnf("Device %s: Unable to handle packet-in: %+v", d.id, err)
+			}
+		}
+	})
 }
 
 // Pipeline returns the P4 information describing the high-level device pipeline
 func (d *deviceController) Pipeline() *p4info.P4Info {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 	return d.translator.FromPipeline()
 }
 
@@ -7,34 +7,87 @@ package controller
 
 import (
 	"context"
-	"github.com/atomix/go-sdk/pkg/primitive"
 	"github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/onos-control/pkg/api"
 	"github.com/onosproject/onos-control/pkg/store"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-net-lib/pkg/p4rtclient"
 	p4api "github.com/p4lang/p4runtime/go/p4/v1"
 	"sync"
 )
 
+var log = logging.GetLogger("controller")
+
 type devicesController struct {
 	api.Devices
-	role   *p4api.Role
-	stores store.Stores
+	role     *p4api.Role
+	stores   store.Stores
+	conns    p4rtclient.ConnManager
+	policies reconcilePolicies
+	options  []store.StoresOption
+
+	// newSouthbound creates the southbound of the device with the given ID and P4Runtime endpoint
+	newSouthbound func(id topo.ID, endpoint string) southbound
 
 	mu      sync.RWMutex
 	devices map[topo.ID]*deviceController
 }
 
-// NewController creates a new controller for device control contexts using the supplied role descriptor
-// and pipeline translator
-func NewController(role *p4api.Role, client primitive.Client) api.Devices {
-	return &devicesController{
-		role:   role,
-		stores: store.NewStoreManager(client),
+// Option configures the controller of device control contexts
+type Option func(c *devicesController)
+
+// WithTablePolicy sets the policy by which the entries of the table with the given fully qualified name in the
+// pipeline of the devices are reconciled
+func WithTablePolicy(table string, policy api.ReconcilePolicy) Option {
+	return func(c *devicesController) {
+		c.policies.tables[table] = policy
+	}
+}
+
+// WithDefaultPolicy sets the policy by which the entries of tables without a policy of their own and the entities
+// other than table entries are reconciled; api.ReconcileDeleteUnknown unless specified otherwise
+func WithDefaultPolicy(policy api.ReconcilePolicy) Option {
+	return func(c *devicesController) {
+		c.policies.fallback = policy
 	}
 }
 
-// Add requests creation of a new device flow control context using its P4Runtime connection endpoint
-func (c *devicesController) Add(ctx context.Context, id topo.ID, p4rtEndpoint string, translator api.PipelineTranslator) (api.DeviceControl, error) {
+// WithStoresOptions sets the options with which the manager of the device entity stores is created, e.g.
+// store.WithStoreOptions(store.WithIndexes(...)) to index the entities of all devices
+func WithStoresOptions(opts ...store.StoresOption) Option {
+	return func(c *devicesController) {
+		c.options = append(c.options, opts...)
+	}
+}
+
+// NewController creates a new controller for device control contexts of the given application using the supplied
+// role descriptor, keeping the entity stores in the given backend within the namespace of the application and role
+func NewController(app string, role *p4api.Role, backend store.Backend, opts ...Option) api.Devices {
+	c := &devicesController{
+		role:     role,
+		conns:    p4rtclient.NewConnManager(),
+		policies: newReconcilePolicies(),
+		devices:  make(map[topo.ID]*deviceController),
+	}
+	c.newSouthbound = func(id topo.ID, endpoint string) southbound {
+		return newP4RTSouthbound(id, endpoint, c.role, c.conns)
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.stores = store.NewStoreManager(backend, store.NewNamespace(app, role), c.options...)
+	return c
+}
+
+// Add requests creation of a new device flow control context using its P4Runtime connection endpoint; if adoption
+// is requested and no logical intent is persisted for the device yet, the table entries present on the device are
+// adopted into the store
+func (c *devicesController) Add(ctx context.Context, id topo.ID, p4rtEndpoint string, translator api.PipelineTranslator, opts ...api.AddOption) (api.DeviceControl, error) {
+	options := &api.AddOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -46,13 +99,30 @@ func (c *devicesController) Add(ctx context.Context, id topo.ID, p4rtEndpoint st
 	if err != nil {
 		return nil, err
 	}
-	return newDeviceController(id, p4rtEndpoint, s, translator), nil
+	c.policies.check(id, translator.ToPipeline())
+	d := newDeviceController(id, p4rtEndpoint, s, translator, c.newSouthbound(id, p4rtEndpoint))
+	d.policies = c.policies
+	if options.Adopt {
+		if _, err = d.adoptDevice(ctx); err != nil {
+			_ = d.southbound.Close()
+			return nil, err
+		}
+	}
+	c.devices[id] = d
+	return d, nil
 }
 
-// Remove requests removal of device control context
+// Remove requests removal of device control context, closing its connection to the device
 func (c *devicesController) Remove(id topo.ID) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	d, ok := c.devices[id]
+	if !ok {
+		return
+	}
+	if err := d.southbound.Close(); err != nil {
+		log.Warnf("Device %s: Unable to disconnect: %+v", id, err)
+	}
 	delete(c.devices, id)
 }
 
@@ -60,7 +130,10 @@ func (c *devicesController) Remove(id topo.ID) {
 func (c *devicesController) Get(id topo.ID) api.DeviceControl {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.devices[id]
+	if d, ok := c.devices[id]; ok {
+		return d
+	}
+	return nil
 }
 
 // GetAll returns all device flow control entities presently registered with the manager
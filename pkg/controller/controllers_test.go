@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-control/pkg/store"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestControllerStoreOptions(t *testing.T) {
+	ctx := context.TODO()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	index := store.ActionParamIndex("FabricIngress.forwarding.set_next_id_routing_v4", "next_id")
+	c := NewController("test", &p4api.Role{Name: "test"}, store.NewMemoryBackend(),
+		WithStoresOptions(store.WithStoreOptions(store.WithIndexes(index))))
+	c.(*devicesController).newSouthbound = func(id topo.ID, endpoint string) southbound { return newFakeSouthbound() }
+	dc, err := c.Add(ctx, "foo", "foo:20000", api.NewIdentityTranslator(info))
+	assert.NoError(t, err)
+	d := dc.(*deviceController)
+
+	updates := generateUpdates(info, 8)
+	for _, u := range updates[:3] {
+		u.Entity.GetTableEntry().Action.GetAction().Params[0].Value = []byte{2}
+	}
+	assert.NoError(t, d.Write(ctx, request(updates)))
+
+	// Routes pointing at a next ID must be found using the index rather than by scanning the table
+	action := p4utils.FindAction(info, "FabricIngress.forwarding.set_next_id_routing_v4")
+	entities, err := api.ReadAll(d.store.ReadByActionParam(ctx, action.Preamble.Id, 1, []byte{2}))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 3)
+	stats := d.store.CacheStats()
+	assert.NotNil(t, stats)
+	assert.Greater(t, stats.IndexHits, uint64(0))
+	assert.Equal(t, uint64(0), stats.IndexScans)
+}
+
+func TestControllerAddWithAdoption(t *testing.T) {
+	ctx := context.TODO()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	sb := newFakeSouthbound()
+	for _, e := range parseEntities(t, info,
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0/8 -> set_next_id_routing_v4(next_id=5)",
+		"FabricIngress.forwarding.mpls mpls_label=100 -> pop_mpls_and_next(next_id=3)") {
+		sb.entities[api.EntityKey(e)] = e
+	}
+	c := NewController("test", &p4api.Role{Name: "test"}, store.NewMemoryBackend()).(*devicesController)
+	c.newSouthbound = func(id topo.ID, endpoint string) southbound { return sb }
+
+	dc, err := c.Add(ctx, "foo", "foo:20000", api.NewIdentityTranslator(info), api.WithAdoption())
+	assert.NoError(t, err)
+	intent, err := readIntent(ctx, dc.(*deviceController).store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 2)
+	assert.Equal(t, 0, sb.writes)
+
+	// The added device must be registered and returned by subsequent additions
+	assert.Equal(t, dc, c.Get("foo"))
+	assert.Len(t, c.GetAll(), 1)
+	again, err := c.Add(ctx, "foo", "foo:20000", api.NewIdentityTranslator(info), api.WithAdoption())
+	assert.NoError(t, err)
+	assert.Equal(t, dc, again)
+	c.Remove("foo")
+	assert.True(t, c.Get("foo") == nil)
+	assert.True(t, sb.closed)
+}
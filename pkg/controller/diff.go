@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/proto"
+	"sort"
+)
+
+// change captures a single update along with the update that reverses its effect
+type change struct {
+	update  *p4api.Update
+	inverse *p4api.Update
+}
+
+// entityDiff captures the changes required to transition the device from one set of physical entities to another;
+// makes are the insertions and modifications, breaks are the deletions
+type entityDiff struct {
+	makes  []*change
+	breaks []*change
+}
+
+// Computes the changes required to transition from the current set of entities to the desired one; makes are
+// ordered so that referenced entities are created before entities that refer to them and breaks in reverse
+func diffEntities(current []*p4api.Entity, desired []*p4api.Entity) *entityDiff {
+	existing := make(map[string]*p4api.Entity, len(current))
+	for _, e := range current {
+		existing[api.EntityKey(e)] = e
+	}
+
+	diff := &entityDiff{}
+	for _, e := range desired {
+		key := api.EntityKey(e)
+		old, ok := existing[key]
+		switch {
+		case !ok:
+			diff.makes = append(diff.makes, &change{
+				update:  &p4api.Update{Type: p4api.Update_INSERT, Entity: e},
+				inverse: &p4api.Update{Type: p4api.Update_DELETE, Entity: e},
+			})
+		case !proto.Equal(old, e):
+			diff.makes = append(diff.makes, &change{
+				update:  &p4api.Update{Type: p4api.Update_MODIFY, Entity: e},
+				inverse: &p4api.Update{Type: p4api.Update_MODIFY, Entity: old},
+			})
+		}
+		delete(existing, key)
+	}
+
+	// Whatever is left over in the existing entities is no longer desired
+	for _, e := range current {
+		if _, ok := existing[api.EntityKey(e)]; ok {
+			diff.breaks = append(diff.breaks, &change{
+				update:  &p4api.Update{Type: p4api.Update_DELETE, Entity: e},
+				inverse: &p4api.Update{Type: p4api.Update_INSERT, Entity: e},
+			})
+		}
+	}
+
+	diff.order()
+	return diff
+}
+
+// Orders the makes so that referenced entities are created before entities that refer to them and breaks in reverse
+func (diff *entityDiff) order() {
+	sort.SliceStable(diff.makes, func(i, j int) bool {
+		return entityRank(diff.makes[i].update.Entity) < entityRank(diff.makes[j].update.Entity)
+	})
+	sort.SliceStable(diff.breaks, func(i, j int) bool {
+		return entityRank(diff.breaks[i].update.Entity) > entityRank(diff.breaks[j].update.Entity)
+	})
+}
+
+// Applies the given diff using the southbound, makes first and breaks second; if either of them fails, the changes
+// which have been applied are reverted. Fails with an error of type Unknown if they cannot be reverted.
+func applyDiff(ctx context.Context, sb southbound, diff *entityDiff) error {
+	applied, err := sb.Write(ctx, updatesOf(diff.makes))
+	if err != nil {
+		return rollback(ctx, sb, diff.makes[:applied], err)
+	}
+	applied, err = sb.Write(ctx, updatesOf(diff.breaks))
+	if err != nil {
+		return rollback(ctx, sb, append(append([]*change{}, diff.makes...), diff.breaks[:applied]...), err)
+	}
+	return nil
+}
+
+// Reverts the given changes in the reverse order of their application, after the specified failure, which is returned
+// if the changes have been reverted; otherwise, the state of the device is unknown, which is reported by an error of
+// type Unknown
+func rollback(ctx context.Context, sb southbound, changes []*change, cause error) error {
+	inverses := make([]*p4api.Update, 0, len(changes))
+	for i := len(changes) - 1; i >= 0; i-- {
+		inverses = append(inverses, changes[i].inverse)
+	}
+	if applied, err := sb.Write(ctx, inverses); err != nil {
+		return errors.NewUnknown("Device state is unknown; unable to roll back %d of %d changes after %+v: %+v",
+			len(inverses)-applied, len(inverses), cause, err)
+	}
+	return cause
+}
+
+func updatesOf(changes []*change) []*p4api.Update {
+	updates := make([]*p4api.Update, 0, len(changes))
+	for _, c := range changes {
+		updates = append(updates, c.update)
+	}
+	return updates
+}
+
+// Returns rank of the entity kind, such that entities of lower rank can be referenced by entities of higher rank
+func entityRank(entity *p4api.Entity) int {
+	switch {
+	case entity.GetPacketReplicationEngineEntry() != nil:
+		return 0
+	case entity.GetActionProfileMember() != nil:
+		return 1
+	case entity.GetActionProfileGroup() != nil:
+		return 2
+	case entity.GetCounterEntry() != nil, entity.GetMeterEntry() != nil:
+		return 3
+	case entity.GetTableEntry() != nil:
+		return 4
+	case entity.GetDirectCounterEntry() != nil, entity.GetDirectMeterEntry() != nil:
+		return 5
+	default:
+		return 6
+	}
+}
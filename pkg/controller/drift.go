@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+// Drift reads the device and compares its entities with the physical entities derived from the persisted logical
+// intent, without making any changes. Only programmable entities are compared, i.e. table entries, action profile
+// members and groups and packet replication engine entries; default table entries are compared only if the intent
+// specifies them.
+func (d *deviceController) Drift(ctx context.Context) (*api.DriftReport, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	_, expected, present, err := d.observe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := api.NewDriftReport(d.translator.ToPipeline(), expected, present)
+	log.Infof("Device %s: Drift of %d missing, %d unexpected and %d differing entities", d.id,
+		len(report.Missing), len(report.Unexpected), len(report.Differing))
+	return report, nil
+}
+
+// Returns the persisted logical intent, the programmable physical entities derived from it and those present on
+// the device; default table entries are read from the device only for tables whose defaults the intent specifies
+func (d *deviceController) observe(ctx context.Context) ([]*p4api.Entity, []*p4api.Entity, []*p4api.Entity, error) {
+	intent, err := readIntent(ctx, d.store)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	physical, err := translate(d.translator, intent)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	expected := make([]*p4api.Entity, 0, len(physical))
+	query := driftQuery()
+	defaults := make(map[uint32]bool)
+	for _, e := range physical {
+		if !isProgrammable(e) {
+			continue
+		}
+		expected = append(expected, e)
+		if entry := e.GetTableEntry(); entry != nil && entry.IsDefaultAction && !defaults[entry.TableId] {
+			defaults[entry.TableId] = true
+			query = append(query, &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{TableId: entry.TableId, IsDefaultAction: true}}})
+		}
+	}
+
+	actual, err := d.southbound.Read(ctx, query)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	present := make([]*p4api.Entity, 0, len(actual))
+	for _, e := range actual {
+		// Every table has a default entry on the device; only those specified by the intent are of interest
+		if entry := e.GetTableEntry(); entry != nil && entry.IsDefaultAction && !defaults[entry.TableId] {
+			continue
+		}
+		if isProgrammable(e) {
+			present = append(present, e)
+		}
+	}
+	return intent, expected, present, nil
+}
+
+// Returns a query for all programmable entities of the device
+func driftQuery() []*p4api.Entity {
+	return []*p4api.Entity{
+		{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{}}},
+		{Entity: &p4api.Entity_ActionProfileMember{ActionProfileMember: &p4api.ActionProfileMember{}}},
+		{Entity: &p4api.Entity_ActionProfileGroup{ActionProfileGroup: &p4api.ActionProfileGroup{}}},
+		{Entity: &p4api.Entity_PacketReplicationEngineEntry{PacketReplicationEngineEntry: &p4api.PacketReplicationEngineEntry{
+			Type: &p4api.PacketReplicationEngineEntry_MulticastGroupEntry{MulticastGroupEntry: &p4api.MulticastGroupEntry{}}}}},
+		{Entity: &p4api.Entity_PacketReplicationEngineEntry{PacketReplicationEngineEntry: &p4api.PacketReplicationEngineEntry{
+			Type: &p4api.PacketReplicationEngineEntry_CloneSessionEntry{CloneSessionEntry: &p4api.CloneSessionEntry{}}}}},
+	}
+}
+
+// Returns true if the entity is of a kind which is inserted and deleted, as opposed to counters and meters, which
+// always exist on the device and whose state changes independently of the intent
+func isProgrammable(entity *p4api.Entity) bool {
+	return entity.GetTableEntry() != nil || entity.GetActionProfileMember() != nil ||
+		entity.GetActionProfileGroup() != nil || entity.GetPacketReplicationEngineEntry() != nil
+}
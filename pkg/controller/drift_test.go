@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+func TestDrift(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	updates := generateUpdates(info, 8)
+	seed(ctx, t, d, sb, updates)
+
+	report, err := d.Drift(ctx)
+	assert.NoError(t, err)
+	assert.True(t, report.InSync())
+
+	// Tamper with the device behind the back of the controller
+	delete(sb.entities, api.EntityKey(updates[0].Entity))
+	entry := proto.Clone(updates[1].Entity.GetTableEntry()).(*p4api.TableEntry)
+	entry.Action.GetAction().Params[0].Value = []byte{2}
+	sb.entities[api.EntityKey(updates[1].Entity)] = &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}
+	unexpected := generateUpdates(info, 1)[0].Entity
+	sb.entities[api.EntityKey(unexpected)] = unexpected
+
+	report, err = d.Drift(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sb.writes)
+	assert.Len(t, sb.entities, 8)
+	if assert.Len(t, report.Missing, 1) {
+		assert.Equal(t, api.FormatEntity(info, updates[0].Entity), report.Missing[0].Text)
+	}
+	if assert.Len(t, report.Unexpected, 1) {
+		assert.Equal(t, api.FormatEntity(info, unexpected), report.Unexpected[0].Text)
+	}
+	if assert.Len(t, report.Differing, 1) {
+		assert.Equal(t, []*api.FieldDrift{{Field: "action.next_id", Expected: "1", Actual: "2"}}, report.Differing[0].Fields)
+	}
+}
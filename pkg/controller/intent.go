@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-control/pkg/store"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+// WriteIntent applies a set of updates to the device transactionally, associating the inserted and modified table
+// entries with the given intent
+func (d *deviceController) WriteIntent(ctx context.Context, intent *api.Intent, request *[]p4api.Update) error {
+	if intent == nil || intent.ID == "" {
+		return errors.NewInvalid("Device %s: intent ID must not be empty", d.id)
+	}
+	updates := make([]*p4api.Update, len(*request))
+	opts := make([]store.WriteOption, 0, len(*request))
+	for i := range *request {
+		updates[i] = &(*request)[i]
+		if updates[i].Type != p4api.Update_DELETE && updates[i].Entity.GetTableEntry() != nil {
+			opts = append(opts, store.WithIntent(updates[i], intent))
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.write(ctx, updates, opts...)
+}
+
+// ReadIntent returns an iterator over all control entries belonging to the given intent, as persisted in the store
+func (d *deviceController) ReadIntent(ctx context.Context, id string) api.EntityIterator {
+	return d.store.ReadByIntent(ctx, id)
+}
+
+// DeleteIntent deletes all control entries belonging to the given intent as a single transactional write
+func (d *deviceController) DeleteIntent(ctx context.Context, id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entities, err := api.ReadAll(d.store.ReadByIntent(ctx, id))
+	if err != nil {
+		return err
+	}
+	updates := make([]*p4api.Update, 0, len(entities))
+	for _, e := range entities {
+		updates = append(updates, &p4api.Update{Type: p4api.Update_DELETE, Entity: e})
+	}
+	log.Infof("Device %s: Deleting %d entries of intent %s", d.id, len(updates), id)
+	return d.write(ctx, updates)
+}
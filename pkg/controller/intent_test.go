@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIntents(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	host := &api.Intent{ID: "host", Labels: map[string]string{"mac": "00:00:00:00:00:01"}}
+	assert.NoError(t, d.WriteIntent(ctx, host, request(generateUpdates(info, 4))))
+	assert.NoError(t, d.Write(ctx, request(generateUpdates(info, 2))))
+	assert.True(t, errors.IsInvalid(d.WriteIntent(ctx, &api.Intent{}, request(generateUpdates(info, 1)))))
+	assert.Len(t, sb.entities, 6)
+
+	entities, err := api.ReadAll(d.ReadIntent(ctx, "host"))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 4)
+
+	// A failed deletion must leave all entries of the intent in place
+	sb.failOn = sb.writes + 1
+	assert.Error(t, d.DeleteIntent(ctx, "host"))
+	assert.Len(t, sb.entities, 6)
+	entities, err = api.ReadAll(d.ReadIntent(ctx, "host"))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 4)
+
+	assert.NoError(t, d.DeleteIntent(ctx, "host"))
+	assert.Len(t, sb.entities, 2)
+	entities, err = api.ReadAll(d.ReadIntent(ctx, "host"))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 0)
+	intent, err := readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 2)
+}
@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-control/pkg/store"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"sort"
+	"strconv"
+)
+
+// reconcilePolicies holds the reconciliation policies of the tables, by their names
+type reconcilePolicies struct {
+	tables   map[string]api.ReconcilePolicy
+	fallback api.ReconcilePolicy
+}
+
+func newReconcilePolicies() reconcilePolicies {
+	return reconcilePolicies{tables: make(map[string]api.ReconcilePolicy), fallback: api.ReconcileDeleteUnknown}
+}
+
+// Returns the policy of the named table
+func (p reconcilePolicies) of(table string) api.ReconcilePolicy {
+	if policy, ok := p.tables[table]; ok {
+		return policy
+	}
+	return p.fallback
+}
+
+// Warns about policies of tables unknown to the pipeline of the device, which are of no effect
+func (p reconcilePolicies) check(id topo.ID, info *p4info.P4Info) {
+	for table := range p.tables {
+		if p4utils.FindTable(info, table) == nil {
+			log.Warnf("Device %s: Pipeline has no table %s; its reconciliation policy is ignored", id, table)
+		}
+	}
+}
+
+// Entities of a single table, as derived from the intent and as present on the device
+type tableEntities struct {
+	expected []*p4api.Entity
+	present  []*p4api.Entity
+}
+
+// Reconcile reads the device and brings its entities in line with the physical entities derived from the persisted
+// logical intent, treating the entries of each table according to its reconciliation policy; missing entries are
+// inserted and differing ones modified unless the table is only observed, while unknown entries are either deleted,
+// adopted into the store or left intact; unknown entities other than table entries are never adopted, as the store
+// does not persist them. The adopted entries are persisted before the changes of all tables are applied to the device
+// together, in make-before-break fashion, and removed from the store again if any of the changes fails.
+func (d *deviceController) Reconcile(ctx context.Context) (*api.ReconcileReport, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	intent, expected, present, err := d.observe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info := d.translator.ToPipeline()
+	tables := make(map[string]*tableEntities)
+	group := func(entity *p4api.Entity) *tableEntities {
+		name := tableName(info, entity)
+		if tables[name] == nil {
+			tables[name] = &tableEntities{}
+		}
+		return tables[name]
+	}
+	for _, e := range expected {
+		group(e).expected = append(group(e).expected, e)
+	}
+	for _, e := range present {
+		group(e).present = append(group(e).present, e)
+	}
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	known := make(map[string]bool, len(intent))
+	for _, e := range intent {
+		known[api.EntityKey(e)] = true
+	}
+
+	report := &api.ReconcileReport{}
+	diff := &entityDiff{}
+	adopted := make([]*p4api.Update, 0)
+	for _, name := range names {
+		policy := d.policies.of(name)
+		drift := api.NewDriftReport(info, tables[name].expected, tables[name].present)
+		table := &api.TableReconciliation{Table: name, Policy: policy, Drift: drift}
+		report.Tables = append(report.Tables, table)
+		if policy == api.ReconcileObserveOnly {
+			continue
+		}
+
+		for _, m := range drift.Missing {
+			diff.makes = append(diff.makes, &change{
+				update:  &p4api.Update{Type: p4api.Update_INSERT, Entity: m.Expected},
+				inverse: &p4api.Update{Type: p4api.Update_DELETE, Entity: m.Expected},
+			})
+			table.Inserted++
+		}
+		for _, m := range drift.Differing {
+			diff.makes = append(diff.makes, &change{
+				update:  &p4api.Update{Type: p4api.Update_MODIFY, Entity: m.Expected},
+				inverse: &p4api.Update{Type: p4api.Update_MODIFY, Entity: m.Actual},
+			})
+			table.Modified++
+		}
+
+		for _, m := range drift.Unexpected {
+			switch policy {
+			case api.ReconcileDeleteUnknown:
+				diff.breaks = append(diff.breaks, &change{
+					update:  &p4api.Update{Type: p4api.Update_DELETE, Entity: m.Actual},
+					inverse: &p4api.Update{Type: p4api.Update_INSERT, Entity: m.Actual},
+				})
+				table.Deleted++
+			case api.ReconcileAdoptUnknown:
+				if m.Actual.GetTableEntry() == nil {
+					log.Warnf("Device %s: Unable to adopt %s: only table entries can be adopted", d.id, m.Text)
+					continue
+				}
+				logical, err := d.adopt(ctx, m.Actual)
+				if err == nil && known[api.EntityKey(logical)] {
+					err = errors.NewConflict("logical entity is already part of the intent")
+				}
+				if err != nil {
+					log.Warnf("Device %s: Unable to adopt %s: %+v", d.id, m.Text, err)
+					continue
+				}
+				known[api.EntityKey(logical)] = true
+				adopted = append(adopted, &p4api.Update{Type: p4api.Update_INSERT, Entity: logical})
+				table.Adopted++
+			}
+		}
+	}
+
+	diff.order()
+	// The adopted entries are present on the device already, so persisting them first cannot get ahead of the device
+	if err = d.store.Write(ctx, adopted); err != nil {
+		log.Warnf("Device %s: Unable to adopt %d entities: %+v", d.id, len(adopted), err)
+		return nil, err
+	}
+	if err = applyDiff(ctx, d.southbound, diff); err != nil {
+		log.Warnf("Device %s: Unable to reconcile: %+v", d.id, err)
+		d.restoreIntent(ctx, entitiesOf(adopted), nil)
+		return nil, err
+	}
+	d.recordDerived(adopted)
+	log.Infof("Device %s: Reconciled; %d makes, %d breaks, %d adopted", d.id, len(diff.makes), len(diff.breaks), len(adopted))
+	return report, nil
+}
+
+// Translates the given physical entity into the logical one from which it would have been derived; fails unless the
+// logical entity complies with the pipeline and translates back into the very same physical entity
+func (d *deviceController) adopt(ctx context.Context, entity *p4api.Entity) (*p4api.Entity, error) {
+	translated, err := api.TranslateToLogical(d.translator, &[]p4api.Entity{{Entity: entity.Entity}})
+	if err != nil {
+		return nil, err
+	}
+	if translated == nil || len(*translated) != 1 {
+		return nil, errors.NewNotSupported("entity does not translate into a single logical entity")
+	}
+	logical := &(*translated)[0]
+	if err = store.ValidateEntity(d.translator.FromPipeline(), logical); err != nil {
+		return nil, err
+	}
+	physical, err := d.derive(ctx, logical)
+	if err != nil {
+		return nil, err
+	}
+	if !api.NewDriftReport(d.translator.ToPipeline(), []*p4api.Entity{entity}, physical).InSync() {
+		return nil, errors.NewNotSupported("logical entity does not translate back into the same entity")
+	}
+	return logical, nil
+}
+
+// Returns the physical entities which would be derived from the given logical entity if it was inserted into the
+// intent; the record of derived entities of an update translator is left intact
+func (d *deviceController) derive(ctx context.Context, logical *p4api.Entity) ([]*p4api.Entity, error) {
+	updating, ok := d.translator.(api.UpdateTranslator)
+	if !ok {
+		return translate(d.translator, []*p4api.Entity{logical})
+	}
+	if err := d.deriveIntent(ctx); err != nil {
+		return nil, err
+	}
+	translated, err := updating.TranslateUpdates(asUpdates([]*p4api.Update{{Type: p4api.Update_INSERT, Entity: logical}}))
+	if err != nil {
+		return nil, err
+	}
+	d.revertDerived(nil, []*p4api.Entity{logical})
+
+	physical := make([]*p4api.Entity, 0, len(*translated))
+	for i := range *translated {
+		physical = append(physical, (*translated)[i].Entity)
+	}
+	return physical, nil
+}
+
+// Returns the name of the table of the given entity; empty for entities other than table entries
+func tableName(info *p4info.P4Info, entity *p4api.Entity) string {
+	entry := entity.GetTableEntry()
+	if entry == nil {
+		return ""
+	}
+	for _, table := range info.Tables {
+		if table.Preamble.Id == entry.TableId {
+			return table.Preamble.Name
+		}
+	}
+	return strconv.FormatUint(uint64(entry.TableId), 10)
+}
@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-control/pkg/store"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// Returns the entities parsed from the given texts
+func parseEntities(t *testing.T, info *p4info.P4Info, texts ...string) []*p4api.Entity {
+	entities := make([]*p4api.Entity, 0, len(texts))
+	for _, text := range texts {
+		entity, err := api.ParseEntity(info, text)
+		assert.NoError(t, err, text)
+		entities = append(entities, entity)
+	}
+	return entities
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	d.policies.tables["FabricIngress.forwarding.bridging"] = api.ReconcileEnforce
+	d.policies.tables["FabricIngress.forwarding.mpls"] = api.ReconcileAdoptUnknown
+	d.policies.tables["FabricIngress.stats.flows"] = api.ReconcileObserveOnly
+
+	intent := parseEntities(t, info,
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0/8 -> set_next_id_routing_v4(next_id=5)",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.1.0.0/16 -> set_next_id_routing_v4(next_id=6)",
+		"FabricIngress.forwarding.bridging vlan_id=10 eth_dst=00:00:00:00:00:01&&&ff:ff:ff:ff:ff:ff priority=100 -> set_next_id_bridging(next_id=7)",
+		"FabricIngress.stats.flows ipv4_src=10.0.0.0&&&255.0.0.0 ig_port=260 priority=10 -> FabricIngress.stats.count(flow_id=3)",
+	)
+	updates := make([]*p4api.Update, 0, len(intent))
+	for _, e := range intent {
+		updates = append(updates, &p4api.Update{Type: p4api.Update_INSERT, Entity: e})
+	}
+	assert.NoError(t, d.store.Write(ctx, updates))
+
+	device := parseEntities(t, info,
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.0.0.0/8 -> set_next_id_routing_v4(next_id=5)",
+		"FabricIngress.forwarding.routing_v4 ipv4_dst=10.9.0.0/16 -> set_next_id_routing_v4(next_id=9)",
+		"FabricIngress.forwarding.bridging vlan_id=10 eth_dst=00:00:00:00:00:01&&&ff:ff:ff:ff:ff:ff priority=100 -> set_next_id_bridging(next_id=8)",
+		"FabricIngress.forwarding.bridging vlan_id=20 eth_dst=00:00:00:00:00:02&&&ff:ff:ff:ff:ff:ff priority=100 -> set_next_id_bridging(next_id=8)",
+		"FabricIngress.forwarding.mpls mpls_label=100 -> pop_mpls_and_next(next_id=3)",
+		"FabricIngress.stats.flows ipv4_src=10.1.0.0&&&255.255.0.0 ig_port=260 priority=10 -> FabricIngress.stats.count(flow_id=4)",
+	)
+	for _, e := range device {
+		sb.entities[api.EntityKey(e)] = e
+	}
+
+	// A failed reconciliation must leave both the device and the store intact
+	sb.failOn = sb.writes + 1
+	_, err := d.Reconcile(ctx)
+	assert.Error(t, err)
+	assert.Len(t, sb.entities, 6)
+	persisted, err := readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, persisted, 4)
+
+	report, err := d.Reconcile(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, report.Tables, 4) {
+		bridging, mpls, routing, flows := report.Tables[0], report.Tables[1], report.Tables[2], report.Tables[3]
+
+		assert.Equal(t, "FabricIngress.forwarding.bridging", bridging.Table)
+		assert.Equal(t, api.ReconcileEnforce, bridging.Policy)
+		assert.Len(t, bridging.Drift.Differing, 1)
+		assert.Len(t, bridging.Drift.Unexpected, 1)
+		assert.Equal(t, api.TableReconciliation{Table: bridging.Table, Policy: bridging.Policy, Drift: bridging.Drift, Modified: 1}, *bridging)
+
+		assert.Equal(t, "FabricIngress.forwarding.mpls", mpls.Table)
+		assert.Equal(t, 1, mpls.Adopted)
+
+		assert.Equal(t, "FabricIngress.forwarding.routing_v4", routing.Table)
+		assert.Equal(t, api.ReconcileDeleteUnknown, routing.Policy)
+		assert.Equal(t, 1, routing.Inserted)
+		assert.Equal(t, 1, routing.Deleted)
+
+		assert.Equal(t, "FabricIngress.stats.flows", flows.Table)
+		assert.Equal(t, api.ReconcileObserveOnly, flows.Policy)
+		assert.Len(t, flows.Drift.Missing, 1)
+		assert.Len(t, flows.Drift.Unexpected, 1)
+		assert.Equal(t, 0, flows.Inserted+flows.Modified+flows.Deleted+flows.Adopted)
+	}
+
+	// Entries of the observed table and unknown entries of the enforced one must remain as they were
+	assert.Len(t, sb.entities, 6)
+	assert.Contains(t, sb.entities, api.EntityKey(device[3]))
+	assert.Contains(t, sb.entities, api.EntityKey(device[5]))
+	assert.NotContains(t, sb.entities, api.EntityKey(device[1]))
+	assert.NotContains(t, sb.entities, api.EntityKey(intent[3]))
+	persisted, err = readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, persisted, 5)
+
+	report, err = d.Reconcile(ctx)
+	assert.NoError(t, err)
+	for _, table := range report.Tables {
+		switch table.Table {
+		case "FabricIngress.forwarding.bridging":
+			assert.Len(t, table.Drift.Unexpected, 1)
+		case "FabricIngress.stats.flows":
+			assert.False(t, table.Drift.InSync())
+		default:
+			assert.True(t, table.Drift.InSync(), table.Table)
+		}
+	}
+}
+
+func TestReconcileAdoptTableEntriesOnly(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	d.policies.fallback = api.ReconcileAdoptUnknown
+	route := parseEntities(t, info, "FabricIngress.forwarding.mpls mpls_label=100 -> pop_mpls_and_next(next_id=3)")[0]
+	member := &p4api.Entity{Entity: &p4api.Entity_ActionProfileMember{ActionProfileMember: &p4api.ActionProfileMember{
+		ActionProfileId: 1, MemberId: 1, Action: &p4api.Action{ActionId: 1}}}}
+	for _, e := range []*p4api.Entity{route, member} {
+		sb.entities[api.EntityKey(e)] = e
+	}
+
+	// Unknown entities other than table entries are not persisted by the store and must be left intact
+	report, err := d.Reconcile(ctx)
+	assert.NoError(t, err)
+	adopted := 0
+	for _, table := range report.Tables {
+		adopted += table.Adopted
+	}
+	assert.Equal(t, 1, adopted)
+	assert.Len(t, sb.entities, 2)
+	persisted, err := readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, persisted, 1)
+}
+
+func TestReconcilePolicies(t *testing.T) {
+	c := NewController("test", &p4api.Role{Name: "test"}, store.NewMemoryBackend(),
+		WithTablePolicy("FabricIngress.forwarding.bridging", api.ReconcileEnforce),
+		WithDefaultPolicy(api.ReconcileObserveOnly)).(*devicesController)
+	assert.Equal(t, api.ReconcileEnforce, c.policies.of("FabricIngress.forwarding.bridging"))
+	assert.Equal(t, api.ReconcileObserveOnly, c.policies.of("FabricIngress.forwarding.routing_v4"))
+	assert.Equal(t, api.ReconcileDeleteUnknown, newReconcilePolicies().of(""))
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/proto"
+	"sync"
+)
+
+// Session collecting updates of the logical intent of a device until committed or discarded
+type session struct {
+	api.Session
+	device *deviceController
+
+	mu     sync.Mutex
+	staged []*p4api.Update
+	closed bool
+}
+
+// NewSession starts a new session for staging updates, which are applied to the device only once committed
+func (d *deviceController) NewSession() api.Session {
+	return &session{device: d}
+}
+
+// Stage validates the given updates against the pipeline and the logical intent, as changed by the updates staged
+// so far, and adds copies of them to the session; updates failing the validation are not staged
+func (s *session) Stage(ctx context.Context, request *[]p4api.Update) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.NewInvalid("Device %s: session is closed", s.device.id)
+	}
+
+	staged := make([]*p4api.Update, len(s.staged), len(s.staged)+len(*request))
+	copy(staged, s.staged)
+	for i := range *request {
+		staged = append(staged, proto.Clone(&(*request)[i]).(*p4api.Update))
+	}
+	if _, err := s.pending(ctx, s.device.Pipeline(), staged); err != nil {
+		return err
+	}
+	s.staged = staged
+	return nil
+}
+
+// Diff returns the net effect of the staged updates on the current logical intent
+func (s *session) Diff(ctx context.Context) ([]*p4api.Update, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errors.NewInvalid("Device %s: session is closed", s.device.id)
+	}
+	return s.pending(ctx, s.device.Pipeline(), s.staged)
+}
+
+// Commit applies the pending changes as a single transactional write and closes the session
+func (s *session) Commit(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.NewInvalid("Device %s: session is closed", s.device.id)
+	}
+
+	s.device.mu.Lock()
+	defer s.device.mu.Unlock()
+	updates, err := s.pending(ctx, s.device.translator.FromPipeline(), s.staged)
+	if err != nil {
+		return err
+	}
+	log.Infof("Device %s: Committing %d staged updates as %d changes", s.device.id, len(s.staged), len(updates))
+	if err = s.device.write(ctx, updates); err != nil {
+		return err
+	}
+	s.staged, s.closed = nil, true
+	return nil
+}
+
+// Discard drops all staged updates and closes the session
+func (s *session) Discard() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.staged, s.closed = nil, true
+}
+
+// Returns the updates transitioning the current logical intent to the one resulting from the given staged updates,
+// validated against the given pipeline; deletions precede insertions and modifications. Only the logical entities
+// affected by the staged updates are read from the store.
+func (s *session) pending(ctx context.Context, info *p4info.P4Info, staged []*p4api.Update) ([]*p4api.Update, error) {
+	current, err := readEntities(ctx, s.device.store, entitiesOf(staged))
+	if err != nil {
+		return nil, err
+	}
+	desired, err := applyUpdates(info, current, staged)
+	if err != nil {
+		return nil, err
+	}
+	diff := diffEntities(current, desired)
+	return append(updatesOf(diff.breaks), updatesOf(diff.makes)...), nil
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+func TestSession(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	updates := generateUpdates(info, 4)
+	seed(ctx, t, d, sb, updates)
+	writes := sb.writes
+
+	session := d.NewSession()
+	inserts := generateUpdates(info, 3)
+	staged := request(inserts)
+	assert.NoError(t, session.Stage(ctx, staged))
+
+	// Later changes to the caller's request must not affect the staged updates
+	(*staged)[1].Type = p4api.Update_DELETE
+	(*staged)[1].Entity = nil
+	deletes := []*p4api.Update{
+		{Type: p4api.Update_DELETE, Entity: updates[0].Entity},
+		{Type: p4api.Update_DELETE, Entity: inserts[0].Entity},
+	}
+	assert.NoError(t, session.Stage(ctx, request(deletes)))
+
+	// Updates invalid with respect to the pipeline or the staged intent must be rejected without being staged
+	invalid := proto.Clone(inserts[1].Entity).(*p4api.Entity)
+	invalid.GetTableEntry().Action.GetAction().Params = nil
+	err := session.Stage(ctx, request([]*p4api.Update{{Type: p4api.Update_MODIFY, Entity: invalid}}))
+	assert.True(t, errors.IsInvalid(err))
+	err = session.Stage(ctx, request(deletes[1:]))
+	assert.True(t, errors.IsNotFound(err))
+
+	// Nothing must reach the device or the store until committed
+	diff, err := session.Diff(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, diff, 3)
+	assert.Equal(t, p4api.Update_DELETE, diff[0].Type)
+	assert.Equal(t, p4api.Update_INSERT, diff[1].Type)
+	assert.Equal(t, writes, sb.writes)
+	intent, err := readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 4)
+
+	assert.NoError(t, session.Commit(ctx))
+	assert.Len(t, sb.entities, 5)
+	intent, err = readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 5)
+	assert.True(t, errors.IsInvalid(session.Commit(ctx)))
+
+	// Discarded sessions must have no effect
+	session = d.NewSession()
+	assert.NoError(t, session.Stage(ctx, request(generateUpdates(info, 2))))
+	session.Discard()
+	assert.True(t, errors.IsInvalid(session.Stage(ctx, request(generateUpdates(info, 1)))))
+	assert.Len(t, sb.entities, 5)
+}
+
+func TestSessionFailedCommit(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	seed(ctx, t, d, sb, generateUpdates(info, 4))
+
+	session := d.NewSession()
+	assert.NoError(t, session.Stage(ctx, request(generateUpdates(info, 3))))
+	sb.failOn = sb.writes + 1
+	assert.Error(t, session.Commit(ctx))
+	assert.Len(t, sb.entities, 4)
+	intent, err := readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 4)
+
+	// The session must remain open, allowing the commit to be retried
+	assert.NoError(t, session.Commit(ctx))
+	assert.Len(t, sb.entities, 7)
+}
@@ -0,0 +1,288 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4rtclient"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/grpc/codes"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// southbound is an abstraction of the P4Runtime client through which the physical pipeline entities
+// are applied to and read from the device
+type southbound interface {
+	// Write applies the given physical pipeline updates to the device in order; returns the number of leading
+	// updates which have been applied, i.e. all of them unless an error is returned
+	Write(ctx context.Context, updates []*p4api.Update) (int, error)
+
+	// Read returns all physical pipeline entities matching the given query
+	Read(ctx context.Context, query []*p4api.Entity) ([]*p4api.Entity, error)
+
+	// EmitPacket sends the given physical packet-out to the device
+	EmitPacket(ctx context.Context, packetOut *p4api.PacketOut) error
+
+	// HandlePackets registers the function to be called with each physical packet-in received from the device
+	HandlePackets(handler func(packetIn *p4api.PacketIn))
+
+	// Close closes the stream channel and the connection to the device; the southbound cannot be used afterwards
+	Close() error
+}
+
+// Provides southbound backed by a P4Runtime connection established on first use
+type p4rtSouthbound struct {
+	id       topo.ID
+	endpoint string
+	role     *p4api.Role
+	conns    p4rtclient.ConnManager
+
+	mu         sync.Mutex
+	client     p4rtclient.Client
+	stream     p4api.P4Runtime_StreamChannelClient
+	cancel     context.CancelFunc
+	electionID *p4api.Uint128
+	primary    bool
+	handler    func(packetIn *p4api.PacketIn)
+	closed     bool
+
+	// Set once the device rejects atomic writes; updates are then written one by one so that the applied ones are known
+	sequential bool
+}
+
+func newP4RTSouthbound(id topo.ID, endpoint string, role *p4api.Role, conns p4rtclient.ConnManager) southbound {
+	return &p4rtSouthbound{id: id, endpoint: endpoint, role: role, conns: conns}
+}
+
+// Connects to the P4Runtime endpoint and performs mastership arbitration, if not done already
+func (s *p4rtSouthbound) connect(ctx context.Context) (p4rtclient.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errors.NewUnavailable("Device %s: southbound is closed", s.id)
+	}
+	if s.client != nil {
+		return s.client, nil
+	}
+
+	host, portText, err := net.SplitHostPort(s.endpoint)
+	if err != nil {
+		return nil, errors.NewInvalid("Invalid P4Runtime endpoint %s: %+v", s.endpoint, err)
+	}
+	port, err := strconv.ParseUint(portText, 10, 32)
+	if err != nil {
+		return nil, errors.NewInvalid("Invalid P4Runtime endpoint port %s: %+v", s.endpoint, err)
+	}
+
+	client, err := s.conns.Connect(ctx, &p4rtclient.Destination{
+		Endpoint: &topo.Endpoint{Address: host, Port: uint32(port)},
+		TargetID: s.id,
+		RoleName: s.role.GetName(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Open our own stream channel, which will carry the mastership arbitration and the packet-out/in messages; it
+	// outlives the given context and is closed by Close
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stream, err := client.StreamChannel(streamCtx)
+	if err != nil {
+		cancel()
+		_ = s.conns.Disconnect(ctx, s.id)
+		return nil, err
+	}
+	electionID := p4utils.TimeBasedElectionID()
+	if err = stream.Send(p4utils.CreateMastershipArbitration(electionID, s.role)); err != nil {
+		cancel()
+		_ = s.conns.Disconnect(ctx, s.id)
+		return nil, errors.FromGRPC(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil || resp.GetArbitration() == nil {
+		cancel()
+		_ = s.conns.Disconnect(ctx, s.id)
+		return nil, errors.NewUnavailable("Mastership arbitration failed for %s: %+v", s.id, err)
+	}
+
+	s.client = client
+	s.stream = stream
+	s.cancel = cancel
+	s.electionID = electionID
+	s.arbitrate(resp.GetArbitration())
+	go s.receive(stream)
+	return s.client, nil
+}
+
+// Records the outcome of the given mastership arbitration; the device reports OK status only to the primary
+// controller, whose writes are the only ones it accepts. Must be called with the lock held.
+func (s *p4rtSouthbound) arbitrate(arbitration *p4api.MasterArbitrationUpdate) {
+	primary := arbitration.GetStatus().GetCode() == int32(codes.OK)
+	if primary != s.primary {
+		mastership := "backup"
+		if primary {
+			mastership = "primary"
+		}
+		log.Infof("Device %s: Became %s controller for role %s", s.id, mastership, s.role.GetName())
+	}
+	s.primary = primary
+}
+
+// Receives messages from the stream channel, tracking the mastership arbitration and dispatching any packet-in
+// messages to the registered handler
+func (s *p4rtSouthbound) receive(stream p4api.P4Runtime_StreamChannelClient) {
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			s.mu.Lock()
+			current := s.stream == stream
+			if current {
+				s.client, s.stream, s.primary = nil, nil, false
+				s.cancel()
+			}
+			s.mu.Unlock()
+			if current {
+				log.Warnf("Device %s: Stream channel closed: %+v", s.id, err)
+				_ = s.conns.Disconnect(context.Background(), s.id)
+			}
+			return
+		}
+		if arbitration := msg.GetArbitration(); arbitration != nil {
+			s.mu.Lock()
+			s.arbitrate(arbitration)
+			s.mu.Unlock()
+			continue
+		}
+		if packetIn := msg.GetPacket(); packetIn != nil {
+			s.mu.Lock()
+			handler := s.handler
+			s.mu.Unlock()
+			if handler != nil {
+				handler(packetIn)
+			}
+		}
+	}
+}
+
+// Write applies the given physical pipeline updates to the device in order; returns the number of leading updates
+// which have been applied. The updates are written atomically, or one by one if the device does not support it.
+func (s *p4rtSouthbound) Write(ctx context.Context, updates []*p4api.Update) (int, error) {
+	if len(updates) == 0 {
+		return 0, nil
+	}
+	client, err := s.connect(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	sequential, primary := s.sequential, s.primary
+	s.mu.Unlock()
+	if !primary {
+		return 0, errors.NewUnavailable("Device %s: not the primary controller for role %s", s.id, s.role.GetName())
+	}
+	if !sequential {
+		err = s.write(ctx, client, p4api.WriteRequest_ROLLBACK_ON_ERROR, updates)
+		if err == nil {
+			return len(updates), nil
+		}
+		if !errors.IsNotSupported(err) {
+			return 0, err
+		}
+		log.Infof("Device %s: Atomic writes are not supported; writing updates one by one", s.id)
+		s.mu.Lock()
+		s.sequential = true
+		s.mu.Unlock()
+	}
+
+	for i, u := range updates {
+		if err = s.write(ctx, client, p4api.WriteRequest_CONTINUE_ON_ERROR, []*p4api.Update{u}); err != nil {
+			return i, err
+		}
+	}
+	return len(updates), nil
+}
+
+func (s *p4rtSouthbound) write(ctx context.Context, client p4rtclient.Client, atomicity p4api.WriteRequest_Atomicity, updates []*p4api.Update) error {
+	_, err := client.Write(ctx, &p4api.WriteRequest{
+		Role:       s.role.GetName(),
+		ElectionId: s.electionID,
+		Updates:    updates,
+		Atomicity:  atomicity,
+	})
+	return err
+}
+
+// EmitPacket sends the given physical packet-out to the device
+func (s *p4rtSouthbound) EmitPacket(ctx context.Context, packetOut *p4api.PacketOut) error {
+	if _, err := s.connect(ctx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stream == nil {
+		return errors.NewUnavailable("Device %s: stream channel is not available", s.id)
+	}
+	return errors.FromGRPC(s.stream.Send(&p4api.StreamMessageRequest{Update: &p4api.StreamMessageRequest_Packet{Packet: packetOut}}))
+}
+
+// HandlePackets registers the function to be called with each physical packet-in received from the device and
+// initiates connection to the device, if not connected already
+func (s *p4rtSouthbound) HandlePackets(handler func(packetIn *p4api.PacketIn)) {
+	s.mu.Lock()
+	s.handler = handler
+	s.mu.Unlock()
+	go func() {
+		if _, err := s.connect(context.Background()); err != nil {
+			log.Warnf("Device %s: Unable to connect: %+v", s.id, err)
+		}
+	}()
+}
+
+// Read returns all physical pipeline entities matching the given query
+func (s *p4rtSouthbound) Read(ctx context.Context, query []*p4api.Entity) ([]*p4api.Entity, error) {
+	client, err := s.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := client.Read(ctx, &p4api.ReadRequest{Role: s.role.GetName(), Entities: query})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]*p4api.Entity, 0)
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return entities, nil
+			}
+			return nil, errors.FromGRPC(err)
+		}
+		entities = append(entities, resp.Entities...)
+	}
+}
+
+// Close closes the stream channel and the connection to the device; the southbound cannot be used afterwards
+func (s *p4rtSouthbound) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.client == nil {
+		return nil
+	}
+	s.cancel()
+	s.client, s.stream, s.primary = nil, nil, false
+	return s.conns.Disconnect(context.Background(), s.id)
+}
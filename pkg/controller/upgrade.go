@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-control/pkg/store"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// SetTranslator replaces the pipeline translator used for the device, re-deriving all physical entities from the
+// persisted logical intent and applying the difference to the device in make-before-break fashion
+func (d *deviceController) SetTranslator(ctx context.Context, translator api.PipelineTranslator) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !proto.Equal(translator.FromPipeline(), d.translator.FromPipeline()) {
+		return errors.NewInvalid("Device %s: new translator must accept the same high-level pipeline", d.id)
+	}
+
+	intent, err := readIntent(ctx, d.store)
+	if err != nil {
+		return err
+	}
+
+	current, err := d.derivedFrom(intent)
+	if err != nil {
+		return errors.NewInternal("Device %s: unable to re-derive current physical entities: %+v", d.id, err)
+	}
+	// Translation of the whole intent also establishes the record of derived entities of an update translator
+	derived, err := translate(translator, intent)
+	if err != nil {
+		return errors.NewInvalid("Device %s: unable to translate intent using the new translator: %+v", d.id, err)
+	}
+
+	diff := diffEntities(current, derived)
+	log.Infof("Device %s: Upgrading translator; %d makes, %d breaks", d.id, len(diff.makes), len(diff.breaks))
+	if err = applyDiff(ctx, d.southbound, diff); err != nil {
+		log.Warnf("Device %s: Unable to apply new translation: %+v", d.id, err)
+		return err
+	}
+	d.translator = translator
+	d.derived = true
+	return nil
+}
+
+// Returns the physical entities presently derived from the given persisted logical intent; an update translator
+// provides them from its record of derived entities, which is established first if need be
+func (d *deviceController) derivedFrom(intent []*p4api.Entity) ([]*p4api.Entity, error) {
+	updating, ok := d.translator.(api.UpdateTranslator)
+	if !ok || !d.derived {
+		physical, err := translate(d.translator, intent)
+		if err == nil && ok {
+			d.derived = true
+		}
+		return physical, err
+	}
+
+	return derivedOf(updating, intent), nil
+}
+
+// Returns the physical entities recorded by the update translator as derived from any of the given logical entities
+func derivedOf(updating api.UpdateTranslator, entities []*p4api.Entity) []*p4api.Entity {
+	physical := make([]*p4api.Entity, 0, len(entities))
+	seen := make(map[string]bool, len(entities))
+	for _, e := range entities {
+		for _, p := range updating.Derived(e) {
+			if key := api.EntityKey(p); !seen[key] {
+				seen[key] = true
+				physical = append(physical, p)
+			}
+		}
+	}
+	return physical
+}
+
+// Reads all logical entities persisted in the given store
+func readIntent(ctx context.Context, entityStore store.EntityStore) ([]*p4api.Entity, error) {
+	return api.ReadAll(entityStore.Read(ctx, store.AllEntitiesQuery()))
+}
+
+// Reads the logical entities persisted in the given store with the same keys as any of the given entities
+func readEntities(ctx context.Context, entityStore store.EntityStore, entities []*p4api.Entity) ([]*p4api.Entity, error) {
+	keys := make(map[string]bool, len(entities))
+	query := make([]*p4api.Entity, 0, len(entities))
+	for _, e := range entities {
+		key := api.EntityKey(e)
+		if keys[key] {
+			continue
+		}
+		keys[key] = true
+		if entry := e.GetTableEntry(); entry != nil {
+			e = &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{
+				TableId: entry.TableId, Match: entry.Match, IsDefaultAction: entry.IsDefaultAction}}}
+		}
+		query = append(query, e)
+	}
+
+	found, err := api.ReadAll(entityStore.Read(ctx, query))
+	if err != nil {
+		return nil, err
+	}
+	// Queries of table entries without any field matches yield all entries of the table
+	persisted := make([]*p4api.Entity, 0, len(query))
+	for _, e := range found {
+		if key := api.EntityKey(e); keys[key] {
+			delete(keys, key)
+			persisted = append(persisted, e)
+		}
+	}
+	return persisted, nil
+}
+
+// Translates the given logical entities into physical ones using the specified translator
+func translate(translator api.PipelineTranslator, entities []*p4api.Entity) ([]*p4api.Entity, error) {
+	logical := make([]p4api.Entity, len(entities))
+	for i, e := range entities {
+		logical[i].Entity = e.Entity
+	}
+
+	physical, err := translator.Translate(&logical)
+	if err != nil {
+		return nil, err
+	}
+	if physical == nil {
+		return nil, nil
+	}
+
+	derived := make([]*p4api.Entity, 0, len(*physical))
+	for i := range *physical {
+		derived = append(derived, &(*physical)[i])
+	}
+	return derived, nil
+}
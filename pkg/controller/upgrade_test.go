@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/atomix/go-sdk/pkg/test"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-control/pkg/store"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	testutils "github.com/onosproject/onos-net-lib/pkg/test"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// Simulates a device holding physical entities; fails the write whose sequence number matches failOn, if set, and
+// stops at the first insertion of an existing entity or deletion of a missing one, as a non-atomic device would
+type fakeSouthbound struct {
+	entities   map[string]*p4api.Entity
+	writes     int
+	failOn     int
+	packetOuts []*p4api.PacketOut
+	handler    func(packetIn *p4api.PacketIn)
+	closed     bool
+}
+
+func newFakeSouthbound() *fakeSouthbound {
+	return &fakeSouthbound{entities: make(map[string]*p4api.Entity)}
+}
+
+func (s *fakeSouthbound) Write(ctx context.Context, updates []*p4api.Update) (int, error) {
+	if len(updates) == 0 {
+		return 0, nil
+	}
+	s.writes++
+	if s.writes == s.failOn {
+		return 0, errors.NewUnavailable("device unavailable")
+	}
+	for i, u := range updates {
+		key := api.EntityKey(u.Entity)
+		_, exists := s.entities[key]
+		switch {
+		case u.Type == p4api.Update_INSERT && exists:
+			return i, errors.NewAlreadyExists("entity already exists")
+		case u.Type == p4api.Update_DELETE && !exists:
+			return i, errors.NewNotFound("entity not found")
+		case u.Type == p4api.Update_DELETE:
+			delete(s.entities, key)
+		default:
+			s.entities[key] = u.Entity
+		}
+	}
+	return len(updates), nil
+}
+
+func (s *fakeSouthbound) Read(ctx context.Context, query []*p4api.Entity) ([]*p4api.Entity, error) {
+	entities := make([]*p4api.Entity, 0, len(s.entities))
+	for _, e := range s.entities {
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+func (s *fakeSouthbound) EmitPacket(ctx context.Context, packetOut *p4api.PacketOut) error {
+	s.packetOuts = append(s.packetOuts, packetOut)
+	return nil
+}
+
+func (s *fakeSouthbound) HandlePackets(handler func(packetIn *p4api.PacketIn)) {
+	s.handler = handler
+}
+
+func (s *fakeSouthbound) Close() error {
+	s.closed = true
+	return nil
+}
+
+// Translator that bumps the priority of every table entry; fails if so configured
+type priorityTranslator struct {
+	api.PipelineTranslator
+	bump int32
+	fail bool
+}
+
+func (t *priorityTranslator) Translate(entities *[]p4api.Entity) (*[]p4api.Entity, error) {
+	if t.fail {
+		return nil, errors.NewInvalid("translation failed")
+	}
+	translated := make([]p4api.Entity, len(*entities))
+	for i := range *entities {
+		entry := (*entities)[i].GetTableEntry()
+		translated[i].Entity = &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{
+			TableId: entry.TableId, Match: entry.Match, Action: entry.Action, Priority: entry.Priority + t.bump,
+		}}
+	}
+	return &translated, nil
+}
+
+func newTestController(ctx context.Context, t *testing.T) (*deviceController, *fakeSouthbound, *p4info.P4Info) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	entityStore, err := store.NewEntityStore(ctx, store.NewAtomixBackend(test.NewClient()), "foo", info)
+	assert.NoError(t, err)
+
+	sb := newFakeSouthbound()
+	return newDeviceController("foo", "foo:20000", entityStore, api.NewIdentityTranslator(info), sb), sb, info
+}
+
+func generateUpdates(info *p4info.P4Info, count int) []*p4api.Update {
+	tableInfo := p4utils.FindTable(info, "FabricIngress.forwarding.routing_v4")
+	actionInfo := p4utils.FindAction(info, "FabricIngress.forwarding.set_next_id_routing_v4")
+	updates := make([]*p4api.Update, 0, count)
+	for i := 0; i < count; i++ {
+		action := &p4api.TableAction{Type: &p4api.TableAction_Action{Action: &p4api.Action{ActionId: actionInfo.Preamble.Id,
+			Params: []*p4api.Action_Param{{ParamId: 1, Value: []byte{1}}}}}}
+		entry := testutils.GenerateTableEntry(tableInfo, 0, action)
+		updates = append(updates, &p4api.Update{Type: p4api.Update_INSERT, Entity: &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}})
+	}
+	return updates
+}
+
+// Seeds the store with the updates and the fake device with their translation by the current translator
+func seed(ctx context.Context, t *testing.T, d *deviceController, sb *fakeSouthbound, updates []*p4api.Update) {
+	assert.NoError(t, d.store.Write(ctx, updates))
+	intent, err := readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	physical, err := translate(d.translator, intent)
+	assert.NoError(t, err)
+	for _, e := range physical {
+		sb.entities[api.EntityKey(e)] = e
+	}
+}
+
+func TestSetTranslator(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	seed(ctx, t, d, sb, generateUpdates(info, 16))
+
+	translator := &priorityTranslator{PipelineTranslator: api.NewIdentityTranslator(info), bump: 10}
+	assert.NoError(t, d.SetTranslator(ctx, translator))
+	assert.Same(t, translator, d.translator)
+	assert.Equal(t, 2, sb.writes)
+	assert.Len(t, sb.entities, 16)
+	for _, e := range sb.entities {
+		assert.Equal(t, int32(10), e.GetTableEntry().Priority)
+	}
+}
+
+func TestSetTranslatorFailedTranslation(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	seed(ctx, t, d, sb, generateUpdates(info, 8))
+	original := d.translator
+
+	translator := &priorityTranslator{PipelineTranslator: api.NewIdentityTranslator(info), fail: true}
+	err := d.SetTranslator(ctx, translator)
+	assert.True(t, errors.IsInvalid(err))
+	assert.Same(t, original, d.translator)
+	assert.Equal(t, 0, sb.writes)
+}
+
+func TestSetTranslatorRollback(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	seed(ctx, t, d, sb, generateUpdates(info, 8))
+	original := d.translator
+
+	// Allow the makes to go through, but fail the breaks
+	sb.failOn = 2
+	translator := &priorityTranslator{PipelineTranslator: api.NewIdentityTranslator(info), bump: 10}
+	assert.Error(t, d.SetTranslator(ctx, translator))
+	assert.Same(t, original, d.translator)
+
+	// Validate that the device has been rolled back to hold the original entries
+	assert.Len(t, sb.entities, 8)
+	for _, e := range sb.entities {
+		assert.Equal(t, int32(0), e.GetTableEntry().Priority)
+	}
+}
+
+func TestSetTranslatorPipelineMismatch(t *testing.T) {
+	ctx := context.TODO()
+	d, _, _ := newTestController(ctx, t)
+	err := d.SetTranslator(ctx, api.NewIdentityTranslator(&p4info.P4Info{}))
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestSetUpdateTranslator(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	routes := p4utils.FindTable(info, "FabricIngress.forwarding.routing_v4").Preamble.Id
+	projections := []*api.TableProjection{{LogicalTableID: routes, PhysicalTableID: routes}}
+	mapping, err := api.NewTableMappingTranslator(info, info, projections)
+	assert.NoError(t, err)
+	translator := &countingUpdateTranslator{countingTranslator: countingTranslator{PipelineTranslator: mapping}, updating: mapping}
+	d.translator = translator
+	updates := generateUpdates(info, 9)
+	assert.NoError(t, d.Write(ctx, request(updates[:8])))
+
+	// The current physical entities must be taken from the record of the update translator
+	assert.NoError(t, d.SetTranslator(ctx, &priorityTranslator{PipelineTranslator: api.NewIdentityTranslator(info), bump: 10}))
+	assert.Equal(t, 0, translator.translated)
+	assert.Len(t, sb.entities, 8)
+
+	// The new update translator must have its record established by the upgrade
+	mapping, err = api.NewTableMappingTranslator(info, info, projections)
+	assert.NoError(t, err)
+	assert.NoError(t, d.SetTranslator(ctx, mapping))
+	assert.Len(t, mapping.Derived(updates[0].Entity), 1)
+	assert.NoError(t, d.Write(ctx, request(updates[8:])))
+	assert.Len(t, mapping.Derived(updates[8].Entity), 1)
+	assert.Len(t, sb.entities, 9)
+	for _, e := range sb.entities {
+		assert.Equal(t, int32(0), e.GetTableEntry().Priority)
+	}
+}
@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-control/pkg/store"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+// Write applies a set of updates to the device transactionally. The updates are applied to the persisted logical
+// intent, the resulting change of the physical entities is applied to the device and finally the updates are
+// persisted in the store. If any of the steps fails, the changes applied by the preceding ones are reverted. Only table
+// entries are supported, as the store does not persist other entities.
+func (d *deviceController) Write(ctx context.Context, request *[]p4api.Update) error {
+	updates := make([]*p4api.Update, len(*request))
+	for i := range *request {
+		updates[i] = &(*request)[i]
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.write(ctx, updates)
+}
+
+// Transactionally applies the given logical updates to the device and persists them in the store using the
+// specified store write options. Only the logical entities affected by the updates are read from the store and
+// translated, and only the physical entities derived from them are compared, so that the cost of the write is
+// proportional to the number of updates rather than to the size of the logical intent.
+func (d *deviceController) write(ctx context.Context, updates []*p4api.Update, opts ...store.WriteOption) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	affected := entitiesOf(updates)
+	previous, err := readEntities(ctx, d.store, affected)
+	if err != nil {
+		return err
+	}
+	desired, err := applyUpdates(d.translator.FromPipeline(), previous, updates)
+	if err != nil {
+		return err
+	}
+
+	diff, err := d.translateChange(ctx, previous, desired, updates)
+	if err != nil {
+		return err
+	}
+	if err = applyDiff(ctx, d.southbound, diff); err != nil {
+		log.Warnf("Device %s: Unable to apply %d updates: %+v", d.id, len(updates), err)
+		d.revertDerived(previous, desired)
+		return err
+	}
+
+	if err = d.store.Write(ctx, updates, opts...); err != nil {
+		log.Warnf("Device %s: Unable to persist %d updates; rolling back: %+v", d.id, len(updates), err)
+		d.restoreIntent(ctx, affected, previous)
+		d.revertDerived(previous, desired)
+		return rollback(ctx, d.southbound, append(append([]*change{}, diff.makes...), diff.breaks...), err)
+	}
+	return nil
+}
+
+// Computes the change of the physical entities resulting from the given logical updates, which transition the
+// affected logical entities from the previous ones to the desired ones. Update translators translate the updates
+// incrementally against their record of derived entities, while other translators translate both versions of the
+// affected logical entities.
+func (d *deviceController) translateChange(ctx context.Context, previous []*p4api.Entity, desired []*p4api.Entity, updates []*p4api.Update) (*entityDiff, error) {
+	updating, ok := d.translator.(api.UpdateTranslator)
+	if !ok {
+		before, err := translate(d.translator, previous)
+		if err != nil {
+			return nil, err
+		}
+		after, err := translate(d.translator, desired)
+		if err != nil {
+			return nil, err
+		}
+		return diffEntities(before, after), nil
+	}
+
+	if err := d.deriveIntent(ctx); err != nil {
+		return nil, err
+	}
+	before := derivedOf(updating, previous)
+	translated, err := updating.TranslateUpdates(asUpdates(updates))
+	if err != nil {
+		return nil, err
+	}
+	return diffEntities(before, applyDerived(before, translated)), nil
+}
+
+// Returns the physical entities resulting from application of the given translated updates to the specified ones
+func applyDerived(entities []*p4api.Entity, updates *[]p4api.Update) []*p4api.Entity {
+	result := make(map[string]*p4api.Entity, len(entities))
+	order := make([]string, 0, len(entities))
+	for _, e := range entities {
+		key := api.EntityKey(e)
+		result[key] = e
+		order = append(order, key)
+	}
+	for i := range *updates {
+		u := &(*updates)[i]
+		key := api.EntityKey(u.Entity)
+		if _, ok := result[key]; !ok {
+			order = append(order, key)
+		}
+		if u.Type == p4api.Update_DELETE {
+			result[key] = nil
+		} else {
+			result[key] = u.Entity
+		}
+	}
+
+	applied := make([]*p4api.Entity, 0, len(result))
+	for _, key := range order {
+		if e := result[key]; e != nil {
+			applied = append(applied, e)
+			delete(result, key)
+		}
+	}
+	return applied
+}
+
+// Establishes the record of derived entities of the update translator from the persisted logical intent, unless the
+// record reflects it already
+func (d *deviceController) deriveIntent(ctx context.Context) error {
+	if d.derived {
+		return nil
+	}
+	intent, err := readIntent(ctx, d.store)
+	if err != nil {
+		return err
+	}
+	if _, err = translate(d.translator, intent); err != nil {
+		return err
+	}
+	d.derived = true
+	return nil
+}
+
+// Reverts the record of derived entities of the update translator after the change of the affected logical entities
+// from the previous ones to the desired ones failed to take effect
+func (d *deviceController) revertDerived(previous []*p4api.Entity, desired []*p4api.Entity) {
+	diff := diffEntities(desired, previous)
+	d.recordDerived(append(updatesOf(diff.breaks), updatesOf(diff.makes)...))
+}
+
+// Applies the given logical updates, which took effect other than via a write, to the record of derived entities of
+// the update translator; if the record cannot be updated, it is established afresh by the next write
+func (d *deviceController) recordDerived(updates []*p4api.Update) {
+	updating, ok := d.translator.(api.UpdateTranslator)
+	if !ok || !d.derived || len(updates) == 0 {
+		return
+	}
+	if _, err := updating.TranslateUpdates(asUpdates(updates)); err != nil {
+		log.Warnf("Device %s: Unable to update the record of derived entities: %+v", d.id, err)
+		d.derived = false
+	}
+}
+
+// Restores the given persisted logical entities to their previous versions on a best-effort basis, after a partially
+// failed write; entities without previous versions are deleted
+func (d *deviceController) restoreIntent(ctx context.Context, affected []*p4api.Entity, previous []*p4api.Entity) {
+	persisted, err := readEntities(ctx, d.store, affected)
+	if err != nil {
+		log.Warnf("Device %s: Unable to restore logical intent: %+v", d.id, err)
+		return
+	}
+	diff := diffEntities(persisted, previous)
+	if err = d.store.Write(ctx, append(updatesOf(diff.breaks), updatesOf(diff.makes)...)); err != nil {
+		log.Warnf("Device %s: Unable to restore logical intent: %+v", d.id, err)
+	}
+}
+
+// Returns the entities of the given updates
+func entitiesOf(updates []*p4api.Update) []*p4api.Entity {
+	entities := make([]*p4api.Entity, 0, len(updates))
+	for _, u := range updates {
+		if u.Entity != nil {
+			entities = append(entities, u.Entity)
+		}
+	}
+	return entities
+}
+
+// Produces a slice of updates, as accepted by the translators, from the given updates
+func asUpdates(updates []*p4api.Update) *[]p4api.Update {
+	result := make([]p4api.Update, len(updates))
+	for i, u := range updates {
+		result[i].Type = u.Type
+		result[i].Entity = u.Entity
+	}
+	return &result
+}
+
+// Returns the entities resulting from application of the given updates to the specified entities; fails if an
+// inserted or modified entity does not comply with the P4Info, if an inserted entity exists already or if a modified
+// or deleted one does not exist. Only table entries are supported, as the store does not persist other entities.
+func applyUpdates(info *p4info.P4Info, entities []*p4api.Entity, updates []*p4api.Update) ([]*p4api.Entity, error) {
+	result := make(map[string]*p4api.Entity, len(entities))
+	order := make([]string, 0, len(entities))
+	for _, e := range entities {
+		key := api.EntityKey(e)
+		result[key] = e
+		order = append(order, key)
+	}
+
+	for _, u := range updates {
+		if u.Entity == nil {
+			return nil, errors.NewInvalid("Update must specify an entity")
+		}
+		if u.Entity.GetTableEntry() == nil {
+			return nil, errors.NewNotSupported("Only table entries can be written: %s", api.FormatEntity(info, u.Entity))
+		}
+		if u.Type != p4api.Update_DELETE {
+			if err := store.ValidateEntity(info, u.Entity); err != nil {
+				return nil, err
+			}
+		}
+		key := api.EntityKey(u.Entity)
+		_, exists := result[key]
+		switch u.Type {
+		case p4api.Update_INSERT:
+			if exists {
+				return nil, errors.NewAlreadyExists("Entity already exists: %s", api.FormatEntity(info, u.Entity))
+			}
+			order = append(order, key)
+			result[key] = u.Entity
+		case p4api.Update_MODIFY:
+			if !exists {
+				return nil, errors.NewNotFound("Entity not found: %s", api.FormatEntity(info, u.Entity))
+			}
+			result[key] = u.Entity
+		case p4api.Update_DELETE:
+			if !exists {
+				return nil, errors.NewNotFound("Entity not found: %s", api.FormatEntity(info, u.Entity))
+			}
+			delete(result, key)
+		default:
+			return nil, errors.NewInvalid("Unsupported update type %s", u.Type)
+		}
+	}
+
+	applied := make([]*p4api.Entity, 0, len(result))
+	for _, key := range order {
+		if e, ok := result[key]; ok {
+			applied = append(applied, e)
+			delete(result, key)
+		}
+	}
+	return applied, nil
+}
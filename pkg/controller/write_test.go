@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// Returns the given updates as a write request
+func request(updates []*p4api.Update) *[]p4api.Update {
+	req := make([]p4api.Update, len(updates))
+	for i, u := range updates {
+		req[i].Type = u.Type
+		req[i].Entity = u.Entity
+	}
+	return &req
+}
+
+func TestWrite(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	updates := generateUpdates(info, 8)
+	assert.NoError(t, d.Write(ctx, request(updates)))
+	assert.Len(t, sb.entities, 8)
+	intent, err := readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 8)
+
+	// Invalid updates must not reach the device
+	writes := sb.writes
+	err = d.Write(ctx, request(updates[:1]))
+	assert.True(t, errors.IsAlreadyExists(err))
+	assert.Equal(t, writes, sb.writes)
+
+	// Failed device writes must not be persisted
+	sb.failOn = sb.writes + 1
+	deletes := []*p4api.Update{{Type: p4api.Update_DELETE, Entity: updates[0].Entity}}
+	assert.Error(t, d.Write(ctx, request(deletes)))
+	assert.Len(t, sb.entities, 8)
+	intent, err = readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 8)
+
+	// Entities other than table entries are not persisted by the store and must not reach the device either
+	writes = sb.writes
+	member := &p4api.Entity{Entity: &p4api.Entity_ActionProfileMember{ActionProfileMember: &p4api.ActionProfileMember{
+		ActionProfileId: 1, MemberId: 1, Action: &p4api.Action{ActionId: 1}}}}
+	err = d.Write(ctx, request([]*p4api.Update{{Type: p4api.Update_INSERT, Entity: member}}))
+	assert.True(t, errors.IsNotSupported(err))
+	assert.Equal(t, writes, sb.writes)
+}
+
+func TestWriteRollback(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	updates := generateUpdates(info, 3)
+	existing := updates[1].Entity
+	sb.entities[api.EntityKey(existing)] = existing
+
+	// Only the updates applied before the failing one must be reverted, leaving the pre-existing entity intact
+	err := d.Write(ctx, request(updates))
+	assert.True(t, errors.IsAlreadyExists(err))
+	assert.Len(t, sb.entities, 1)
+	assert.Same(t, existing, sb.entities[api.EntityKey(existing)])
+	intent, err := readIntent(ctx, d.store)
+	assert.NoError(t, err)
+	assert.Len(t, intent, 0)
+
+	// Failure to roll back must be reported as the device state being unknown
+	sb.failOn = sb.writes + 2
+	err = d.Write(ctx, request(updates))
+	assert.True(t, errors.IsUnknown(err))
+	assert.Len(t, sb.entities, 2)
+}
+
+// Translator counting the entities it is given for translation
+type countingTranslator struct {
+	api.PipelineTranslator
+	translated int
+}
+
+func (t *countingTranslator) Translate(entities *[]p4api.Entity) (*[]p4api.Entity, error) {
+	t.translated += len(*entities)
+	return t.PipelineTranslator.Translate(entities)
+}
+
+// Update translator counting the entities it is given for translation in full
+type countingUpdateTranslator struct {
+	countingTranslator
+	updating api.UpdateTranslator
+}
+
+func (t *countingUpdateTranslator) TranslateUpdates(updates *[]p4api.Update) (*[]p4api.Update, error) {
+	return t.updating.TranslateUpdates(updates)
+}
+
+func (t *countingUpdateTranslator) Derived(entity *p4api.Entity) []*p4api.Entity {
+	return t.updating.Derived(entity)
+}
+
+func TestIncrementalWrite(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	translator := &countingTranslator{PipelineTranslator: api.NewIdentityTranslator(info)}
+	d.translator = translator
+	updates := generateUpdates(info, 64)
+	seed(ctx, t, d, sb, updates[1:])
+
+	// Only the affected entities must be translated
+	translator.translated = 0
+	changes := []*p4api.Update{updates[0], {Type: p4api.Update_DELETE, Entity: updates[1].Entity}}
+	assert.NoError(t, d.Write(ctx, request(changes)))
+	assert.Equal(t, 2, translator.translated)
+	assert.Len(t, sb.entities, 63)
+}
+
+func TestIncrementalUpdateWrite(t *testing.T) {
+	ctx := context.TODO()
+	d, sb, info := newTestController(ctx, t)
+	routes := p4utils.FindTable(info, "FabricIngress.forwarding.routing_v4").Preamble.Id
+	mapping, err := api.NewTableMappingTranslator(info, info, []*api.TableProjection{{LogicalTableID: routes, PhysicalTableID: routes}})
+	assert.NoError(t, err)
+	translator := &countingUpdateTranslator{countingTranslator: countingTranslator{PipelineTranslator: mapping}, updating: mapping}
+	d.translator = translator
+	updates := generateUpdates(info, 32)
+	seed(ctx, t, d, sb, updates[2:])
+
+	// The record of derived entities is established from the intent once, and maintained incrementally afterwards
+	translator.translated = 0
+	assert.NoError(t, d.Write(ctx, request(updates[:1])))
+	assert.Equal(t, 30, translator.translated)
+	assert.NoError(t, d.Write(ctx, request([]*p4api.Update{{Type: p4api.Update_DELETE, Entity: updates[2].Entity}})))
+	assert.Equal(t, 30, translator.translated)
+	assert.Len(t, sb.entities, 30)
+	assert.Len(t, mapping.Derived(updates[2].Entity), 0)
+
+	// Failed device writes must revert the record of derived entities
+	sb.failOn = sb.writes + 1
+	assert.Error(t, d.Write(ctx, request(updates[1:2])))
+	assert.Len(t, mapping.Derived(updates[1].Entity), 0)
+	assert.NoError(t, d.Write(ctx, request(updates[1:2])))
+	assert.Len(t, mapping.Derived(updates[1].Entity), 1)
+	assert.Len(t, sb.entities, 31)
+	assert.Equal(t, 30, translator.translated)
+}
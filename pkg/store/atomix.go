@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/atomix/go-sdk/pkg/primitive"
+	_map "github.com/atomix/go-sdk/pkg/primitive/map"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"io"
+)
+
+type atomixBackend struct {
+	client primitive.Client
+}
+
+// NewAtomixBackend creates a backend storing the entity store maps in an Atomix cluster using the given client
+func NewAtomixBackend(client primitive.Client) Backend {
+	return &atomixBackend{client: client}
+}
+
+// Map returns the Atomix map with the given name, creating it if it does not exist yet
+func (b *atomixBackend) Map(ctx context.Context, name string) (Map, error) {
+	m, err := _map.NewBuilder[string, []byte](b.client, name).
+		Tag("onos-control", "p4rt-entities").
+		Codec(bytesCodec{}).
+		Get(ctx)
+	if err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	return &atomixMap{name: name, entries: m}, nil
+}
+
+// Close does nothing; the client remains owned by the creator of the backend
+func (b *atomixBackend) Close() error {
+	return nil
+}
+
+// bytesCodec passes the values through as they are, since the store encodes the entries itself
+type bytesCodec struct{}
+
+func (c bytesCodec) Encode(value []byte) ([]byte, error) {
+	return value, nil
+}
+
+func (c bytesCodec) Decode(value []byte) ([]byte, error) {
+	return value, nil
+}
+
+type atomixMap struct {
+	name    string
+	entries _map.Map[string, []byte]
+}
+
+func (m *atomixMap) Name() string {
+	return m.name
+}
+
+func (m *atomixMap) Insert(ctx context.Context, key string, value []byte) (*MapEntry, error) {
+	entry, err := m.entries.Insert(ctx, key, value)
+	if err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	return newMapEntry(entry), nil
+}
+
+func (m *atomixMap) Update(ctx context.Context, key string, value []byte, revision api.Revision) (*MapEntry, error) {
+	var opts []_map.UpdateOption
+	if revision != 0 {
+		opts = append(opts, _map.IfVersion(primitive.Version(revision)))
+	}
+	entry, err := m.entries.Update(ctx, key, value, opts...)
+	if err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	return newMapEntry(entry), nil
+}
+
+func (m *atomixMap) Remove(ctx context.Context, key string, revision api.Revision) (*MapEntry, error) {
+	var opts []_map.RemoveOption
+	if revision != 0 {
+		opts = append(opts, _map.IfVersion(primitive.Version(revision)))
+	}
+	entry, err := m.entries.Remove(ctx, key, opts...)
+	if err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	return newMapEntry(entry), nil
+}
+
+func (m *atomixMap) Get(ctx context.Context, key string) (*MapEntry, error) {
+	entry, err := m.entries.Get(ctx, key)
+	if err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	return newMapEntry(entry), nil
+}
+
+func (m *atomixMap) List(ctx context.Context, emit func(entry *MapEntry) error) error {
+	stream, err := m.entries.List(ctx)
+	if err != nil {
+		return errors.FromAtomix(err)
+	}
+	for {
+		entry, err := stream.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.FromAtomix(err)
+		}
+		if err = emit(newMapEntry(entry)); err != nil {
+			return err
+		}
+	}
+}
+
+func (m *atomixMap) Watch(ctx context.Context, ch chan<- *MapEvent) error {
+	stream, err := m.entries.Events(ctx)
+	if err != nil {
+		return errors.FromAtomix(err)
+	}
+	go func() {
+		defer close(ch)
+		for {
+			e, err := stream.Next()
+			if err != nil {
+				return
+			}
+			var event *MapEvent
+			switch me := e.(type) {
+			case *_map.Inserted[string, []byte]:
+				event = &MapEvent{Type: EntityInserted, Entry: newMapEntry(me.Entry)}
+			case *_map.Updated[string, []byte]:
+				event = &MapEvent{Type: EntityUpdated, Entry: newMapEntry(me.NewEntry)}
+			case *_map.Removed[string, []byte]:
+				event = &MapEvent{Type: EntityRemoved, Entry: newMapEntry(me.Entry)}
+			default:
+				continue
+			}
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (m *atomixMap) Close(ctx context.Context) error {
+	return errors.FromAtomix(m.entries.Close(ctx))
+}
+
+func newMapEntry(entry *_map.Entry[string, []byte]) *MapEntry {
+	return &MapEntry{Key: entry.Key, Value: entry.Value, Revision: api.Revision(entry.Version)}
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+)
+
+// Backend is an abstraction of a storage system capable of maintaining named maps of revisioned entries, on top
+// of which the entity stores are built
+type Backend interface {
+	// Map returns the map with the given name, creating it if it does not exist yet
+	Map(ctx context.Context, name string) (Map, error)
+
+	// Close releases the resources held by the backend
+	Close() error
+}
+
+// Map is a named collection of revisioned entries keyed by string. The revision of an entry changes with each
+// update of the entry and is never zero for a persisted entry. Errors are reported using the onos-lib-go error
+// types; AlreadyExists, NotFound and Conflict in particular.
+type Map interface {
+	// Name returns the name of the map
+	Name() string
+
+	// Insert creates a new entry; returns AlreadyExists error if the entry already exists
+	Insert(ctx context.Context, key string, value []byte) (*MapEntry, error)
+
+	// Update replaces the value of an existing entry; returns NotFound error if the entry does not exist. If a
+	// non-zero revision is given, the update is applied only if the entry is at that revision; otherwise
+	// Conflict error is returned.
+	Update(ctx context.Context, key string, value []byte, revision api.Revision) (*MapEntry, error)
+
+	// Remove removes an existing entry and returns it as it was prior to the removal; returns NotFound error if
+	// the entry does not exist. If a non-zero revision is given, the entry is removed only if it is at that
+	// revision; otherwise Conflict error is returned.
+	Remove(ctx context.Context, key string, revision api.Revision) (*MapEntry, error)
+
+	// Get returns the entry with the given key; returns NotFound error if the entry does not exist
+	Get(ctx context.Context, key string) (*MapEntry, error)
+
+	// List emits all entries of the map via the supplied function, stopping at the first error returned by it
+	List(ctx context.Context, emit func(entry *MapEntry) error) error
+
+	// Watch registers for events describing changes of the map entries made by any client of the backend and
+	// returns once the registration is complete; events are sent to the given channel until the context is
+	// cancelled or the events can no longer be delivered, after which the channel is closed
+	Watch(ctx context.Context, ch chan<- *MapEvent) error
+
+	// Close releases any resources held on behalf of the map; the entries remain persisted
+	Close(ctx context.Context) error
+}
+
+// MapEntry is a single revisioned map entry
+type MapEntry struct {
+	Key      string
+	Value    []byte
+	Revision api.Revision
+}
+
+// MapEvent describes a change of a map entry. For removals, the entry is the entry just prior to its removal.
+type MapEvent struct {
+	Type  EventType
+	Entry *MapEntry
+}
+
+// BatchMap is a map capable of applying a batch of operations atomically. The entity store applies the table entry
+// updates of a write to such maps one batch per table, so that either all or none of them take effect.
+type BatchMap interface {
+	Map
+
+	// Apply applies the given operations in order, as a single atomic change of the map; either all of them take
+	// effect, or none. Returns the resulting entries, or for removals the entries prior to their removal, in the
+	// order of the operations. If any operation fails, returns its error along with its index.
+	Apply(ctx context.Context, ops []*MapOp) ([]*MapEntry, int, error)
+}
+
+// MapOpType is the type of an operation of a batch applied to a map
+type MapOpType int
+
+const (
+	// MapInsert creates a new entry; fails with AlreadyExists error if the entry already exists
+	MapInsert MapOpType = iota
+	// MapUpdate replaces the value of an existing entry; fails with NotFound error if the entry does not exist
+	MapUpdate
+	// MapRemove removes an existing entry; fails with NotFound error if the entry does not exist
+	MapRemove
+)
+
+// MapOp is a single operation of a batch applied to a map
+type MapOp struct {
+	Type MapOpType
+	Key  string
+	// Value is the value of the inserted or updated entry
+	Value []byte
+	// Merge, if given, produces the value of the updated entry from its present value, in place of Value
+	Merge func(present []byte) ([]byte, error)
+	// Revision, if non-zero, makes an update or removal conditional on the entry being at that revision; otherwise
+	// the operation fails with Conflict error
+	Revision api.Revision
+}
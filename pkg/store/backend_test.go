@@ -0,0 +1,219 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/atomix/go-sdk/pkg/test"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Runs the given test against a fresh instance of each of the backends
+func forEachBackend(t *testing.T, test func(t *testing.T, backend Backend)) {
+	backends := []struct {
+		name    string
+		backend func(t *testing.T) Backend
+	}{
+		{"atomix", newAtomixTestBackend},
+		{"memory", func(t *testing.T) Backend { return NewMemoryBackend() }},
+		{"file", func(t *testing.T) Backend {
+			backend, err := NewFileBackend(filepath.Join(t.TempDir(), "entities.db"))
+			assert.NoError(t, err)
+			return backend
+		}},
+	}
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			backend := b.backend(t)
+			defer backend.Close()
+			test(t, backend)
+		})
+	}
+}
+
+func newAtomixTestBackend(t *testing.T) Backend {
+	return NewAtomixBackend(test.NewClient())
+}
+
+func listKeys(ctx context.Context, t *testing.T, m Map) map[string]string {
+	entries := make(map[string]string)
+	assert.NoError(t, m.List(ctx, func(entry *MapEntry) error {
+		entries[entry.Key] = string(entry.Value)
+		return nil
+	}))
+	return entries
+}
+
+func TestBackendConformance(t *testing.T) {
+	forEachBackend(t, testBackendConformance)
+}
+
+func testBackendConformance(t *testing.T, backend Backend) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m, err := backend.Map(ctx, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "test", m.Name())
+
+	events := make(chan *MapEvent, 16)
+	assert.NoError(t, m.Watch(ctx, events))
+
+	e1, err := m.Insert(ctx, "a", []byte("1"))
+	assert.NoError(t, err)
+	assert.NotZero(t, e1.Revision)
+	_, err = m.Insert(ctx, "a", []byte("2"))
+	assert.True(t, errors.IsAlreadyExists(err))
+
+	e2, err := m.Update(ctx, "a", []byte("2"), e1.Revision)
+	assert.NoError(t, err)
+	assert.Greater(t, e2.Revision, e1.Revision)
+	_, err = m.Update(ctx, "a", []byte("3"), e1.Revision)
+	assert.True(t, errors.IsConflict(err))
+	_, err = m.Update(ctx, "b", []byte("3"), 0)
+	assert.True(t, errors.IsNotFound(err))
+
+	got, err := m.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", string(got.Value))
+	assert.Equal(t, e2.Revision, got.Revision)
+
+	_, err = m.Insert(ctx, "b", []byte("3"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "2", "b": "3"}, listKeys(ctx, t, m))
+
+	_, err = m.Remove(ctx, "a", e1.Revision)
+	assert.True(t, errors.IsConflict(err))
+	removed, err := m.Remove(ctx, "a", e2.Revision)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", string(removed.Value))
+	_, err = m.Remove(ctx, "a", 0)
+	assert.True(t, errors.IsNotFound(err))
+	_, err = m.Get(ctx, "a")
+	assert.True(t, errors.IsNotFound(err))
+	assert.Equal(t, map[string]string{"b": "3"}, listKeys(ctx, t, m))
+
+	// Maps of the same name are shared; maps of different names are not
+	same, err := backend.Map(ctx, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"b": "3"}, listKeys(ctx, t, same))
+	other, err := backend.Map(ctx, "other")
+	assert.NoError(t, err)
+	assert.Empty(t, listKeys(ctx, t, other))
+
+	expected := []EventType{EntityInserted, EntityUpdated, EntityInserted, EntityRemoved}
+	for _, eventType := range expected {
+		select {
+		case event := <-events:
+			assert.Equal(t, eventType, event.Type)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+	}
+
+	// Cancelling the watch must close the channel
+	cancel()
+	for range events {
+	}
+	assert.NoError(t, m.Close(context.Background()))
+}
+
+func TestFileBackendDurability(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "entities.db")
+	backend, err := NewFileBackend(path)
+	assert.NoError(t, err)
+	m, err := backend.Map(ctx, "test")
+	assert.NoError(t, err)
+	_, err = m.Insert(ctx, "a", []byte("1"))
+	assert.NoError(t, err)
+	_, err = m.Insert(ctx, "b", []byte("2"))
+	assert.NoError(t, err)
+	removed, err := m.Remove(ctx, "b", 0)
+	assert.NoError(t, err)
+	assert.NoError(t, backend.Close())
+
+	// Entries and revisions must survive reopening, including the compaction
+	for i := 0; i < 2; i++ {
+		backend, err = NewFileBackend(path)
+		assert.NoError(t, err)
+		m, err = backend.Map(ctx, "test")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "1"}, listKeys(ctx, t, m))
+		assert.NoError(t, backend.Close())
+	}
+
+	backend, err = NewFileBackend(path)
+	assert.NoError(t, err)
+	m, err = backend.Map(ctx, "test")
+	assert.NoError(t, err)
+	inserted, err := m.Insert(ctx, "b", []byte("3"))
+	assert.NoError(t, err)
+	assert.Greater(t, inserted.Revision, removed.Revision)
+	assert.NoError(t, backend.Close())
+	_, err = m.Insert(ctx, "c", []byte("4"))
+	assert.True(t, errors.IsUnavailable(err))
+}
+
+func TestBatchMap(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend Backend) {
+		ctx := context.Background()
+		m, err := backend.Map(ctx, "test")
+		assert.NoError(t, err)
+		batch, ok := m.(BatchMap)
+		if !ok {
+			t.Skip("backend does not support batches")
+		}
+		a, err := m.Insert(ctx, "a", []byte("1"))
+		assert.NoError(t, err)
+
+		// A failed operation must leave the map intact
+		_, failed, err := batch.Apply(ctx, []*MapOp{
+			{Type: MapInsert, Key: "b", Value: []byte("2")},
+			{Type: MapUpdate, Key: "a", Value: []byte("3"), Revision: a.Revision + 1},
+		})
+		assert.True(t, errors.IsConflict(err))
+		assert.Equal(t, 1, failed)
+		assert.Equal(t, map[string]string{"a": "1"}, listKeys(ctx, t, m))
+
+		results, _, err := batch.Apply(ctx, []*MapOp{
+			{Type: MapInsert, Key: "b", Value: []byte("2")},
+			{Type: MapUpdate, Key: "a", Merge: func(present []byte) ([]byte, error) { return append(present, '3'), nil }},
+			{Type: MapRemove, Key: "b"},
+			{Type: MapInsert, Key: "c", Value: []byte("4")},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, results, 4)
+		assert.Equal(t, "2", string(results[2].Value))
+		assert.Greater(t, results[3].Revision, results[1].Revision)
+		assert.Equal(t, map[string]string{"a": "13", "c": "4"}, listKeys(ctx, t, m))
+	})
+}
+
+func TestFileBackendBatchDurability(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "entities.db")
+	backend, err := NewFileBackend(path)
+	assert.NoError(t, err)
+	m, err := backend.Map(ctx, "test")
+	assert.NoError(t, err)
+	_, _, err = m.(BatchMap).Apply(ctx, []*MapOp{
+		{Type: MapInsert, Key: "a", Value: []byte("1")},
+		{Type: MapInsert, Key: "b", Value: []byte("2")},
+		{Type: MapRemove, Key: "a"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, backend.Close())
+
+	backend, err = NewFileBackend(path)
+	assert.NoError(t, err)
+	m, err = backend.Map(ctx, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"b": "2"}, listKeys(ctx, t, m))
+	assert.NoError(t, backend.Close())
+}
@@ -0,0 +1,354 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"sync"
+)
+
+// DefaultWriteConcurrency is the default maximum number of concurrent operations issued against the backing
+// storage system by a single write
+const DefaultWriteConcurrency = 64
+
+// Option is an entity store configuration option
+type Option func(s *entityStore)
+
+// WithWriteConcurrency sets the maximum number of concurrent operations issued against the backing storage
+// system by a single write
+func WithWriteConcurrency(concurrency int) Option {
+	return func(s *entityStore) {
+		if concurrency > 0 {
+			s.writeConcurrency = concurrency
+		}
+	}
+}
+
+// WriteOption is an option of a single write
+type WriteOption func(w *writeOptions)
+
+type writeOptions struct {
+	revisions map[*p4api.Update]api.Revision
+	intents   map[*p4api.Update]*api.Intent
+}
+
+// IfRevision makes the given update of the write conditional on the persisted entity being at the specified
+// revision, as previously reported by Read or Watch; applicable only to modifications and deletions of table entries
+func IfRevision(update *p4api.Update, revision api.Revision) WriteOption {
+	return func(w *writeOptions) {
+		w.revisions[update] = revision
+	}
+}
+
+// tableOp is a single table entry update along with its position in the write request and its resolved target
+type tableOp struct {
+	index       int
+	update      *p4api.Update
+	table       *table
+	key         string
+	conditional bool
+	revision    api.Revision
+	hasIntent   bool
+	intent      *api.Intent
+}
+
+// opGroup is a sequence of updates of the same table entry, which must be applied in order
+type opGroup []*tableOp
+
+// tableBatch is the sequence of updates of the entries of a single table, in the order of the write request
+type tableBatch struct {
+	table *table
+	ops   []*tableOp
+}
+
+// modifyRetries is the maximum number of attempts to modify a table entry retaining its intent, while the entry keeps
+// being changed concurrently
+const modifyRetries = 8
+
+// Resolves the tables and keys of the given table entry updates, grouping the updates per table in the order of
+// their first appearance. Returns error if any of the updates refers to an unknown table or has invalid matches.
+func (s *entityStore) groupTableUpdates(updates []*p4api.Update, options *writeOptions) ([]*tableBatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	batches := make([]*tableBatch, 0)
+	index := make(map[uint32]*tableBatch)
+	for i, update := range updates {
+		entry := update.Entity.GetTableEntry()
+		if entry == nil {
+			continue
+		}
+		t, key, err := s.findTableAndKey(entry)
+		if err != nil {
+			return nil, err
+		}
+		op := &tableOp{index: i, update: update, table: t, key: key}
+		if op.revision, op.conditional = options.revisions[update]; op.conditional && update.Type == p4api.Update_INSERT {
+			return nil, errors.NewInvalid("Insert of entry in table %s cannot be conditional", t.info.Preamble.Name)
+		}
+		if op.intent, op.hasIntent = options.intents[update]; op.hasIntent && op.intent != nil && op.intent.ID == "" {
+			return nil, errors.NewInvalid("Intent ID of entry in table %s must not be empty", t.info.Preamble.Name)
+		}
+		b, ok := index[entry.TableId]
+		if !ok {
+			b = &tableBatch{table: t}
+			index[entry.TableId] = b
+			batches = append(batches, b)
+		}
+		b.ops = append(b.ops, op)
+	}
+	return batches, nil
+}
+
+// Groups the updates of the batch per table entry in the order of their first appearance
+func (b *tableBatch) entryGroups() []opGroup {
+	groups := make([]opGroup, 0, len(b.ops))
+	index := make(map[string]int)
+	for _, op := range b.ops {
+		if g, ok := index[op.key]; ok {
+			groups[g] = append(groups[g], op)
+		} else {
+			index[op.key] = len(groups)
+			groups = append(groups, opGroup{op})
+		}
+	}
+	return groups
+}
+
+// Applies the given batches of table entry updates. The batches of tables kept in maps capable of applying batches
+// atomically are applied each as a single operation; the updates of other tables are grouped per entry. The batches
+// and groups are applied concurrently, up to the write concurrency of the store, with the updates within each group
+// applied in order. Once any update fails, no further updates are issued and the error of the earliest failed update,
+// by its position in the write request, is returned.
+func (s *entityStore) applyTableUpdates(ctx context.Context, batches []*tableBatch) error {
+	units := make([]func(ctx context.Context) (int, error), 0, len(batches))
+	for _, b := range batches {
+		b := b
+		if entries, ok := b.table.entries.(BatchMap); ok {
+			units = append(units, func(ctx context.Context) (int, error) {
+				return s.applyTableBatch(ctx, entries, b)
+			})
+			continue
+		}
+		for _, group := range b.entryGroups() {
+			group := group
+			units = append(units, func(ctx context.Context) (int, error) {
+				for _, op := range group {
+					if ctx.Err() != nil {
+						return op.index, errors.NewCanceled("Write cancelled: %+v", ctx.Err())
+					}
+					if err := s.applyTableOp(ctx, op); err != nil {
+						return op.index, err
+					}
+				}
+				return 0, nil
+			})
+		}
+	}
+	if len(units) == 0 {
+		return nil
+	}
+
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	failedIndex := -1
+	var failure error
+
+	work := make(chan func(ctx context.Context) (int, error))
+	wg := &sync.WaitGroup{}
+	workers := s.writeConcurrency
+	if workers > len(units) {
+		workers = len(units)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for unit := range work {
+				if batchCtx.Err() != nil {
+					continue
+				}
+				index, err := unit(batchCtx)
+				// Ignore failures induced by cancellation due to an earlier failure
+				if err == nil || (batchCtx.Err() != nil && ctx.Err() == nil) {
+					continue
+				}
+				mu.Lock()
+				if failedIndex < 0 || index < failedIndex {
+					failedIndex, failure = index, err
+				}
+				mu.Unlock()
+				cancel()
+			}
+		}()
+	}
+
+	for _, unit := range units {
+		if batchCtx.Err() != nil {
+			break
+		}
+		work <- unit
+	}
+	close(work)
+	wg.Wait()
+
+	if failure == nil && ctx.Err() != nil {
+		return errors.NewCanceled("Write cancelled: %+v", ctx.Err())
+	}
+	return failure
+}
+
+// Applies the updates of a single table as one atomic batch of map operations; modifications retain the intent of
+// the entries, unless they specify one, which is resolved by the map as part of the batch. Returns the index of the
+// failed update, by its position in the write request, along with its error.
+func (s *entityStore) applyTableBatch(ctx context.Context, entries BatchMap, b *tableBatch) (int, error) {
+	ops := make([]*MapOp, len(b.ops))
+	intents := make([]*api.Intent, len(b.ops))
+	for i, op := range b.ops {
+		i, op := i, op
+		entry := op.update.Entity.GetTableEntry()
+		intents[i] = op.intent
+		switch op.update.Type {
+		case p4api.Update_INSERT:
+			value, err := encodeTableEntry(entry, op.intent)
+			if err != nil {
+				return op.index, err
+			}
+			ops[i] = &MapOp{Type: MapInsert, Key: op.key, Value: value}
+		case p4api.Update_MODIFY:
+			ops[i] = &MapOp{Type: MapUpdate, Key: op.key, Revision: op.revision}
+			if op.hasIntent {
+				value, err := encodeTableEntry(entry, op.intent)
+				if err != nil {
+					return op.index, err
+				}
+				ops[i].Value = value
+			} else {
+				ops[i].Merge = func(present []byte) ([]byte, error) {
+					intent, err := decodeIntent(&MapEntry{Key: op.key, Value: present})
+					if err != nil {
+						return nil, err
+					}
+					intents[i] = intent
+					return encodeTableEntry(entry, intent)
+				}
+			}
+		case p4api.Update_DELETE:
+			ops[i] = &MapOp{Type: MapRemove, Key: op.key, Revision: op.revision}
+		default:
+			return op.index, errors.NewInvalid("Unsupported update type %s", op.update.Type)
+		}
+	}
+
+	results, failed, err := entries.Apply(ctx, ops)
+	if err != nil {
+		log.Warnf("Device %s: Unable to apply %d updates of table %s: %+v", s.id, len(ops), b.table.info.Preamble.Name, err)
+		return b.ops[failed].index, err
+	}
+
+	// Reflect our own writes in the cache right away, rather than waiting for the corresponding events
+	if b.table.cache != nil {
+		for i, op := range b.ops {
+			if op.update.Type == p4api.Update_DELETE {
+				b.table.cache.remove(op.key, results[i].Revision)
+			} else {
+				b.table.cache.put(op.key, op.update.Entity.GetTableEntry(), intents[i], results[i].Revision)
+			}
+		}
+	}
+	return 0, nil
+}
+
+// Applies a single table entry update
+func (s *entityStore) applyTableOp(ctx context.Context, op *tableOp) error {
+	entry := op.update.Entity.GetTableEntry()
+	intent := op.intent
+	var result *MapEntry
+	var err error
+	switch op.update.Type {
+	case p4api.Update_INSERT:
+		var value []byte
+		if value, err = encodeTableEntry(entry, intent); err != nil {
+			return err
+		}
+		if result, err = op.table.entries.Insert(ctx, op.key, value); err != nil {
+			log.Warnf("Device %s: Unable to insert entry: %+v", s.id, err)
+		}
+	case p4api.Update_MODIFY:
+		if result, intent, err = s.modifyTableEntry(ctx, op); err != nil {
+			log.Warnf("Device %s: Unable to update entry: %+v", s.id, err)
+		}
+	case p4api.Update_DELETE:
+		result, err = op.table.entries.Remove(ctx, op.key, op.revision)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Reflect our own writes in the cache right away, rather than waiting for the corresponding events
+	if op.table.cache != nil {
+		if op.update.Type == p4api.Update_DELETE {
+			op.table.cache.remove(op.key, result.Revision)
+		} else {
+			op.table.cache.put(op.key, entry, intent, result.Revision)
+		}
+	}
+	return nil
+}
+
+// Modifies a single table entry; unless the update specifies the intent of the entry, the entry retains its intent.
+// Returns the resulting map entry along with the intent of the modified entry.
+func (s *entityStore) modifyTableEntry(ctx context.Context, op *tableOp) (*MapEntry, *api.Intent, error) {
+	entry := op.update.Entity.GetTableEntry()
+	for attempt := 1; ; attempt++ {
+		intent, revision := op.intent, op.revision
+		if !op.hasIntent {
+			present, presentRevision, err := s.presentIntent(ctx, op, attempt == 1)
+			if err != nil {
+				return nil, nil, err
+			}
+			intent = present
+			if !op.conditional {
+				revision = presentRevision
+			}
+		}
+		value, err := encodeTableEntry(entry, intent)
+		if err != nil {
+			return nil, nil, err
+		}
+		result, err := op.table.entries.Update(ctx, op.key, value, revision)
+		if err != nil && errors.IsConflict(err) && !op.hasIntent && !op.conditional && attempt < modifyRetries {
+			// The entry has been changed concurrently; retry with its latest intent
+			continue
+		}
+		return result, intent, err
+	}
+}
+
+// Returns the intent and revision of the persisted entry targeted by the given update, served from the cache on the
+// first attempt, if available, rather than read from the backing storage system
+func (s *entityStore) presentIntent(ctx context.Context, op *tableOp, first bool) (*api.Intent, api.Revision, error) {
+	if first && op.table.cache != nil {
+		if ce, ok := op.table.cache.get(op.key); ok && ce != nil {
+			return ce.intent, ce.revision, nil
+		}
+	}
+	existing, err := op.table.entries.Get(ctx, op.key)
+	if err != nil {
+		return nil, 0, err
+	}
+	intent, err := decodeIntent(existing)
+	if err != nil {
+		return nil, 0, err
+	}
+	return intent, existing.Revision, nil
+}
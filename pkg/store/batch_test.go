@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"github.com/atomix/go-sdk/pkg/test"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"testing"
+	"time"
+)
+
+// Generates the given number of distinct IPv4 route updates of the specified type
+func generateRoutes(info *p4info.P4Info, count int, updateType p4api.Update_Type) []*p4api.Update {
+	table := p4utils.FindTable(info, "FabricIngress.forwarding.routing_v4")
+	action := p4utils.FindAction(info, "FabricIngress.forwarding.set_next_id_routing_v4")
+	updates := make([]*p4api.Update, count)
+	for i := range updates {
+		dst := make([]byte, 4)
+		binary.BigEndian.PutUint32(dst, uint32(i))
+		updates[i] = &p4api.Update{Type: updateType, Entity: &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{
+			TableId: table.Preamble.Id,
+			Match:   []*p4api.FieldMatch{{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Lpm{Lpm: &p4api.FieldMatch_LPM{Value: dst, PrefixLen: 32}}}},
+			Action: &p4api.TableAction{Type: &p4api.TableAction_Action{Action: &p4api.Action{ActionId: action.Preamble.Id,
+				Params: []*p4api.Action_Param{{ParamId: 1, Value: []byte{byte(i)}}}}}},
+		}}}}
+	}
+	return updates
+}
+
+func TestBatchedWrite(t *testing.T) {
+	forEachBackend(t, testBatchedWrite)
+}
+
+func testBatchedWrite(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	store, err := NewEntityStore(ctx, backend, "foo", info, WithWriteConcurrency(8))
+	assert.NoError(t, err)
+	query := []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{}}}}
+
+	// Updates of the same entry must be applied in order
+	inserts := generateRoutes(info, 100, p4api.Update_INSERT)
+	deletes := generateRoutes(info, 50, p4api.Update_DELETE)
+	assert.NoError(t, store.Write(ctx, append(inserts, deletes...)))
+	entities, err := api.ReadAll(store.Read(ctx, query))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 50)
+
+	// Invalid updates must be rejected before anything is applied
+	invalid := &p4api.Update{Type: p4api.Update_INSERT, Entity: &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{TableId: 1}}}}
+	err = store.Write(ctx, append(generateRoutes(info, 60, p4api.Update_MODIFY), invalid))
+	assert.True(t, errors.IsInvalid(err))
+
+	// The earliest failed update must be reported
+	err = store.Write(ctx, generateRoutes(info, 100, p4api.Update_MODIFY))
+	assert.True(t, errors.IsNotFound(err))
+	entities, err = api.ReadAll(store.Read(ctx, query))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 50)
+
+	// The updates of a table must take effect atomically where the backend supports it
+	if _, ok := store.(*entityStore).tables[inserts[0].Entity.GetTableEntry().TableId].entries.(BatchMap); ok {
+		err = store.Write(ctx, append(generateRoutes(info, 110, p4api.Update_INSERT)[100:], generateRoutes(info, 1, p4api.Update_MODIFY)...))
+		assert.True(t, errors.IsNotFound(err))
+		entities, err = api.ReadAll(store.Read(ctx, query))
+		assert.NoError(t, err)
+		assert.Len(t, entities, 50)
+	}
+
+	// Queries with field matches must yield only the entry with the same key
+	entities, err = api.ReadAll(store.Read(ctx, []*p4api.Entity{inserts[10].Entity, inserts[60].Entity}))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 1)
+	assert.True(t, proto.Equal(inserts[60].Entity, entities[0]))
+}
+
+// BenchmarkWrite measures throughput of bulk route programming, e.g. go test -bench Write/entries=10k ./pkg/store
+func BenchmarkWrite(b *testing.B) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	sizes := []struct {
+		name  string
+		count int
+	}{{"10k", 10_000}, {"100k", 100_000}, {"1M", 1_000_000}}
+	for _, size := range sizes {
+		count := size.count
+		for _, concurrency := range []int{1, DefaultWriteConcurrency} {
+			b.Run(fmt.Sprintf("entries=%s/concurrency=%d", size.name, concurrency), func(b *testing.B) {
+				ctx := context.Background()
+				updates := generateRoutes(info, count, p4api.Update_INSERT)
+				var elapsed time.Duration
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					store, err := NewEntityStore(ctx, NewAtomixBackend(test.NewClient()), "bench", info, WithWriteConcurrency(concurrency))
+					if err != nil {
+						b.Fatal(err)
+					}
+					b.StartTimer()
+					start := time.Now()
+					if err = store.Write(ctx, updates); err != nil {
+						b.Fatal(err)
+					}
+					elapsed += time.Since(start)
+				}
+				b.ReportMetric(float64(count*b.N)/elapsed.Seconds(), "entries/s")
+			})
+		}
+	}
+}
+
+// Map whose entries are changed concurrently with every update
+type conflictingMap struct {
+	Map
+	updates int
+}
+
+func (m *conflictingMap) Update(ctx context.Context, key string, value []byte, revision api.Revision) (*MapEntry, error) {
+	m.updates++
+	return nil, errors.NewConflict("Entry %s changed concurrently", key)
+}
+
+func TestModifyRetries(t *testing.T) {
+	ctx := context.Background()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	store, err := NewEntityStore(ctx, NewMemoryBackend(), "foo", info)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Write(ctx, generateRoutes(info, 1, p4api.Update_INSERT)))
+
+	// Modifications retaining the intent must give up once the retries are exhausted
+	tbl := store.(*entityStore).tables[generateRoutes(info, 1, p4api.Update_INSERT)[0].Entity.GetTableEntry().TableId]
+	conflicting := &conflictingMap{Map: tbl.entries}
+	tbl.entries = conflicting
+	err = store.Write(ctx, generateRoutes(info, 1, p4api.Update_MODIFY))
+	assert.True(t, errors.IsConflict(err))
+	assert.Equal(t, modifyRetries, conflicting.updates)
+}
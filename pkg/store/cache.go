@@ -0,0 +1,311 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// cacheResyncInterval is the delay before a table cache attempts to re-synchronize after losing its event stream
+const cacheResyncInterval = time.Second
+
+// WithCache enables an in-memory read-through cache of the table entries, kept coherent with the backing
+// storage system using its change events. Reads are served from the cache while it is synchronized and from
+// the backing storage system otherwise.
+func WithCache() Option {
+	return func(s *entityStore) {
+		s.cached = true
+	}
+}
+
+// CacheStats reports effectiveness and coherence of the entity store cache
+type CacheStats struct {
+	// Hits is the number of table reads served from the cache
+	Hits uint64
+	// Misses is the number of table reads served from the backing storage system because the cache was not synchronized
+	Misses uint64
+	// Unsynchronized is the number of table caches presently not synchronized with the backing storage system
+	Unsynchronized int
+	// Staleness is the longest time any of the table caches has been out of synchronization
+	Staleness time.Duration
+	// Resyncs is the number of times the table caches had to be re-synchronized after losing their event stream
+	Resyncs uint64
+	// IndexHits is the number of table lookups by attribute value served using a secondary index
+	IndexHits uint64
+	// IndexScans is the number of table lookups by attribute value which required scanning the table
+	IndexScans uint64
+}
+
+type cachedEntry struct {
+	entry    *p4api.TableEntry
+	intent   *api.Intent
+	revision api.Revision
+}
+
+// tableCache maintains a replica of the entries of a single table
+type tableCache struct {
+	mu           sync.RWMutex
+	entries      map[string]*cachedEntry
+	removed      map[string]api.Revision
+	indexes      []*tableIndex
+	synchronized bool
+	since        time.Time
+	resyncs      uint64
+}
+
+func newTableCache(defs []indexDef) *tableCache {
+	c := &tableCache{entries: make(map[string]*cachedEntry), removed: make(map[string]api.Revision), since: time.Now()}
+	for _, def := range defs {
+		c.indexes = append(c.indexes, newTableIndex(def))
+	}
+	return c
+}
+
+// Drops all cached entries along with their index entries and tombstones
+func (c *tableCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*cachedEntry)
+	c.removed = make(map[string]api.Revision)
+	for i, index := range c.indexes {
+		c.indexes[i] = newTableIndex(index.def)
+	}
+}
+
+// Records the given entry unless a newer version of it, or its removal at the same or a later revision, is already
+// known
+func (c *tableCache) put(key string, entry *p4api.TableEntry, intent *api.Intent, revision api.Revision) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	current, ok := c.entries[key]
+	if ok && current.revision > revision {
+		return
+	}
+	if removed, ok := c.removed[key]; ok {
+		if removed >= revision {
+			return
+		}
+		delete(c.removed, key)
+	}
+	ce := &cachedEntry{entry: entry, intent: intent, revision: revision}
+	for _, index := range c.indexes {
+		if ok {
+			index.remove(key, current)
+		}
+		index.add(key, ce)
+	}
+	c.entries[key] = ce
+}
+
+// Removes the given entry, which has been removed at the given revision by a write of the store, unless a newer
+// version of it is already known. A tombstone of the removal is kept, so that the entry is not brought back by a put
+// of the same or an older version arriving late from the map events; the tombstone is dropped once the map events
+// report the removal.
+func (c *tableCache) remove(key string, revision api.Revision) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.drop(key, revision) {
+		if removed, ok := c.removed[key]; !ok || removed < revision {
+			c.removed[key] = revision
+		}
+	}
+}
+
+// Removes the given entry, whose removal at the given revision has been reported by the map events, unless a newer
+// version of it is already known. The events report the changes of each entry in order, so no older version of the
+// entry can arrive from them afterwards and any tombstone of the removal is dropped.
+func (c *tableCache) removeReported(key string, revision api.Revision) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.drop(key, revision)
+	if removed, ok := c.removed[key]; ok && removed <= revision {
+		delete(c.removed, key)
+	}
+}
+
+// Drops the given entry along with its index entries unless a newer version of it is known; returns false if so.
+// Must be called with the cache lock held.
+func (c *tableCache) drop(key string, revision api.Revision) bool {
+	current, ok := c.entries[key]
+	if ok && current.revision > revision {
+		return false
+	}
+	if ok {
+		for _, index := range c.indexes {
+			index.remove(key, current)
+		}
+		delete(c.entries, key)
+	}
+	return true
+}
+
+// Returns the cached entries whose attribute described by the given index definition has the specified value;
+// returns false if the cache is not synchronized or does not maintain such index
+func (c *tableCache) lookup(def indexDef, value string) ([]*cachedEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.synchronized {
+		return nil, false
+	}
+	for _, index := range c.indexes {
+		if index.def == def {
+			entries := make([]*cachedEntry, 0, len(index.keys[value]))
+			for key := range index.keys[value] {
+				entries = append(entries, c.entries[key])
+			}
+			return entries, true
+		}
+	}
+	return nil, false
+}
+
+// Returns the cached entry with the given key, or nil if there is no such entry; returns false if the cache is not
+// synchronized
+func (c *tableCache) get(key string) (*cachedEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.synchronized {
+		return nil, false
+	}
+	return c.entries[key], true
+}
+
+// Returns a snapshot of the cached entries, or false if the cache is not synchronized
+func (c *tableCache) snapshot() ([]*cachedEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.synchronized {
+		return nil, false
+	}
+	entries := make([]*cachedEntry, 0, len(c.entries))
+	for _, ce := range c.entries {
+		entries = append(entries, ce)
+	}
+	return entries, true
+}
+
+func (c *tableCache) setSynchronized(synchronized bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.synchronized != synchronized {
+		c.synchronized = synchronized
+		c.since = time.Now()
+	}
+}
+
+// Starts maintaining caches of all tables; returns once all caches have been initially synchronized
+func (s *entityStore) startCaches(ctx context.Context) error {
+	s.cacheCtx, s.cacheCancel = context.WithCancel(context.Background())
+	for _, t := range s.tables {
+		t.cache = newTableCache(s.tableIndexDefs(t.info.Preamble.Id))
+		if err := s.syncCache(ctx, t); err != nil {
+			s.cacheCancel()
+			return err
+		}
+	}
+	return nil
+}
+
+// Synchronizes the table cache with the backing map and keeps it coherent using the map events
+func (s *entityStore) syncCache(ctx context.Context, t *table) error {
+	// Register for events first, so that no changes made while listing the entries are missed
+	eventsCtx, cancel := context.WithCancel(s.cacheCtx)
+	events := make(chan *MapEvent)
+	if err := t.entries.Watch(eventsCtx, events); err != nil {
+		cancel()
+		return err
+	}
+	t.cache.reset()
+	err := t.entries.List(ctx, func(entry *MapEntry) error {
+		te, intent, err := decodeStoredEntry(entry)
+		if err != nil {
+			return err
+		}
+		t.cache.put(entry.Key, te, intent, entry.Revision)
+		return nil
+	})
+	if err != nil {
+		cancel()
+		return err
+	}
+	t.cache.setSynchronized(true)
+	go s.maintainCache(t, events, cancel)
+	return nil
+}
+
+func (s *entityStore) maintainCache(t *table, events <-chan *MapEvent, cancel context.CancelFunc) {
+	defer cancel()
+	for event := range events {
+		if event.Type == EntityRemoved {
+			t.cache.removeReported(event.Entry.Key, event.Entry.Revision)
+			continue
+		}
+		te, intent, err := decodeStoredEntry(event.Entry)
+		if err != nil {
+			log.Warnf("Device %s: %+v", s.id, err)
+			break
+		}
+		t.cache.put(event.Entry.Key, te, intent, event.Entry.Revision)
+	}
+
+	// The event stream ended; unless the store is being shut down, re-synchronize the cache
+	cancel()
+	for range events {
+	}
+	t.cache.setSynchronized(false)
+	for s.cacheCtx.Err() == nil {
+		log.Warnf("Device %s: Lost events of table %s; re-synchronizing cache", s.id, t.info.Preamble.Name)
+		atomic.AddUint64(&t.cache.resyncs, 1)
+		if err := s.syncCache(s.cacheCtx, t); err == nil {
+			return
+		}
+		select {
+		case <-time.After(cacheResyncInterval):
+		case <-s.cacheCtx.Done():
+		}
+	}
+}
+
+// Stops maintaining the table caches
+func (s *entityStore) stopCaches() {
+	if s.cacheCancel != nil {
+		s.cacheCancel()
+	}
+}
+
+// CacheStats returns the statistics of the entity store cache, or nil if the cache is not enabled.
+func (s *entityStore) CacheStats() *CacheStats {
+	if !s.cached {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stats := &CacheStats{
+		Hits:       atomic.LoadUint64(&s.cacheHits),
+		Misses:     atomic.LoadUint64(&s.cacheMisses),
+		IndexHits:  atomic.LoadUint64(&s.indexHits),
+		IndexScans: atomic.LoadUint64(&s.indexScans),
+	}
+	for _, t := range s.tables {
+		if t.cache == nil {
+			continue
+		}
+		stats.Resyncs += atomic.LoadUint64(&t.cache.resyncs)
+		t.cache.mu.RLock()
+		if !t.cache.synchronized {
+			stats.Unsynchronized++
+			if staleness := time.Since(t.cache.since); staleness > stats.Staleness {
+				stats.Staleness = staleness
+			}
+		}
+		t.cache.mu.RUnlock()
+	}
+	return stats
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	forEachBackend(t, testCache)
+}
+
+func testCache(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	routing := p4utils.FindTable(info, "FabricIngress.forwarding.routing_v4")
+	query := []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{TableId: routing.Preamble.Id}}}}
+
+	// Entries written prior to the cache being started must be loaded
+	other, err := NewEntityStore(ctx, backend, "foo", info)
+	assert.NoError(t, err)
+	assert.Nil(t, other.CacheStats())
+	assert.NoError(t, other.Write(ctx, generateRoutes(info, 10, p4api.Update_INSERT)))
+
+	store, err := NewEntityStore(ctx, backend, "foo", info, WithCache())
+	assert.NoError(t, err)
+	readEntries(ctx, t, store, query, 10)
+	stats := store.CacheStats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(0), stats.Misses)
+	assert.Equal(t, 0, stats.Unsynchronized)
+
+	// Our own writes must be visible immediately
+	assert.NoError(t, store.Write(ctx, generateRoutes(info, 5, p4api.Update_DELETE)))
+	readEntries(ctx, t, store, query, 5)
+
+	// Writes of other clients must be picked up via events
+	assert.NoError(t, other.Write(ctx, generateRoutes(info, 20, p4api.Update_INSERT)[10:]))
+	assert.Eventually(t, func() bool {
+		entities, err := api.ReadAll(store.Read(ctx, query))
+		return err == nil && len(entities) == 15
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Greater(t, store.CacheStats().Hits, uint64(2))
+
+	assert.NoError(t, store.(*entityStore).Purge(ctx))
+}
+
+func TestCacheTombstones(t *testing.T) {
+	c := newTableCache(nil)
+	c.setSynchronized(true)
+	entry := &p4api.TableEntry{TableId: 1}
+
+	// A put arriving after the removal of the same version must not bring the entry back
+	c.put("a", entry, nil, 3)
+	c.remove("a", 3)
+	c.put("a", entry, nil, 3)
+	ce, _ := c.get("a")
+	assert.Nil(t, ce)
+
+	// Removal known before the put of an older version must prevail as well
+	c.remove("b", 5)
+	c.put("b", entry, nil, 4)
+	ce, _ = c.get("b")
+	assert.Nil(t, ce)
+
+	// Newer versions must be recorded, replacing the tombstone
+	c.put("a", entry, nil, 7)
+	ce, _ = c.get("a")
+	if assert.NotNil(t, ce) {
+		assert.Equal(t, api.Revision(7), ce.revision)
+	}
+	c.remove("a", 6)
+	ce, _ = c.get("a")
+	assert.NotNil(t, ce)
+
+	// Tombstones must be dropped once the events report the removals, and removals reported by events alone must
+	// leave none behind
+	c.remove("a", 7)
+	c.removeReported("a", 7)
+	c.removeReported("b", 5)
+	c.put("c", entry, nil, 8)
+	c.removeReported("c", 8)
+	assert.Empty(t, c.removed)
+	ce, _ = c.get("c")
+	assert.Nil(t, ce)
+}
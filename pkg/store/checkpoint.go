@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"sort"
+	"time"
+)
+
+// DefaultCheckpointLimit is the default limit of the total size of the checkpoints of a single entity store in bytes
+const DefaultCheckpointLimit = 64 * 1024 * 1024
+
+// WithCheckpointLimit sets the limit of the total size of the checkpoints of the entity store in bytes
+func WithCheckpointLimit(limit int) Option {
+	return func(s *entityStore) {
+		if limit > 0 {
+			s.checkpointLimit = limit
+		}
+	}
+}
+
+// Persisted form of a checkpoint; the captured entities are kept in the snapshot format
+type checkpointRecord struct {
+	api.Checkpoint
+	Snapshot []byte `json:"snapshot"`
+}
+
+// Checkpoint captures all persisted entities under the given name, which must not be in use already. Fails if the
+// checkpoint would exceed the limit of the total size of checkpoints of the store, or if entities other than table
+// entries are persisted, as checkpoints are limited to table entries for now.
+func (s *entityStore) Checkpoint(ctx context.Context, name string) (*api.Checkpoint, error) {
+	if name == "" {
+		return nil, errors.NewInvalid("Checkpoint name must not be empty")
+	}
+	entities, intents, err := s.readSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &bytes.Buffer{}
+	if err = s.encodeSnapshot(snapshot, entities, intents); err != nil {
+		return nil, err
+	}
+
+	s.checkpointMu.Lock()
+	defer s.checkpointMu.Unlock()
+	checkpoints, err := s.Checkpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	size := snapshot.Len()
+	for _, checkpoint := range checkpoints {
+		size += checkpoint.Size
+	}
+	if size > s.checkpointLimit {
+		return nil, errors.NewInvalid("Checkpoint %s of %d bytes would exceed the %d byte limit of checkpoints of device %s",
+			name, snapshot.Len(), s.checkpointLimit, s.id)
+	}
+
+	record := &checkpointRecord{
+		Checkpoint: api.Checkpoint{Name: name, Created: time.Now(), Entities: len(entities), Size: snapshot.Len()},
+		Snapshot:   snapshot.Bytes(),
+	}
+	value, err := json.Marshal(record)
+	if err != nil {
+		return nil, errors.NewInternal("Unable to encode checkpoint %s: %+v", name, err)
+	}
+	if _, err = s.checkpoints.Insert(ctx, name, value); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return nil, errors.NewAlreadyExists("Checkpoint %s of device %s already exists", name, s.id)
+		}
+		return nil, err
+	}
+	log.Infof("Device %s: Created checkpoint %s of %d entities", s.id, name, len(entities))
+	return &record.Checkpoint, nil
+}
+
+// Checkpoints returns descriptors of all checkpoints of the store, ordered by their creation time
+func (s *entityStore) Checkpoints(ctx context.Context) ([]*api.Checkpoint, error) {
+	checkpoints := make([]*api.Checkpoint, 0)
+	if err := s.checkpoints.List(ctx, func(entry *MapEntry) error {
+		record, err := decodeCheckpoint(entry)
+		if err != nil {
+			return err
+		}
+		checkpoints = append(checkpoints, &record.Checkpoint)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(checkpoints, func(i, j int) bool { return checkpoints[i].Created.Before(checkpoints[j].Created) })
+	return checkpoints, nil
+}
+
+// CheckpointEntities returns the entities captured by the given checkpoint along with the intents to which they
+// belong; the intents are nil if the checkpoint predates recording of intents. Fails if the checkpoint does not
+// comply with the P4Info of the store.
+func (s *entityStore) CheckpointEntities(ctx context.Context, name string) ([]*p4api.Entity, []*api.Intent, error) {
+	entry, err := s.checkpoints.Get(ctx, name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil, errors.NewNotFound("Checkpoint %s of device %s not found", name, s.id)
+		}
+		return nil, nil, err
+	}
+	record, err := decodeCheckpoint(entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, entities, intents, err := s.decodeSnapshot(bytes.NewReader(record.Snapshot))
+	return entities, intents, err
+}
+
+// DeleteCheckpoint deletes the given checkpoint
+func (s *entityStore) DeleteCheckpoint(ctx context.Context, name string) error {
+	if _, err := s.checkpoints.Remove(ctx, name, 0); err != nil {
+		if errors.IsNotFound(err) {
+			return errors.NewNotFound("Checkpoint %s of device %s not found", name, s.id)
+		}
+		return err
+	}
+	log.Infof("Device %s: Deleted checkpoint %s", s.id, name)
+	return nil
+}
+
+func decodeCheckpoint(entry *MapEntry) (*checkpointRecord, error) {
+	record := &checkpointRecord{}
+	if err := json.Unmarshal(entry.Value, record); err != nil {
+		return nil, errors.NewInternal("Unable to decode checkpoint %s: %+v", entry.Key, err)
+	}
+	return record, nil
+}
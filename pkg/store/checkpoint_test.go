@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCheckpoints(t *testing.T) {
+	ctx := context.TODO()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+
+	store, err := NewEntityStore(ctx, NewMemoryBackend(), "foo", info, WithCheckpointLimit(16*1024))
+	assert.NoError(t, err)
+	assert.NoError(t, store.Write(ctx, generateRoutes(info, 10, p4api.Update_INSERT)))
+
+	_, err = store.Checkpoint(ctx, "")
+	assert.True(t, errors.IsInvalid(err))
+
+	first, err := store.Checkpoint(ctx, "first")
+	assert.NoError(t, err)
+	assert.Equal(t, "first", first.Name)
+	assert.Equal(t, 10, first.Entities)
+	assert.Greater(t, first.Size, 0)
+
+	_, err = store.Checkpoint(ctx, "first")
+	assert.True(t, errors.IsAlreadyExists(err))
+
+	// Checkpoints must be independent of subsequent changes
+	assert.NoError(t, store.Write(ctx, generateRoutes(info, 5, p4api.Update_DELETE)))
+	second, err := store.Checkpoint(ctx, "second")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, second.Entities)
+
+	checkpoints, err := store.Checkpoints(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, checkpoints, 2)
+	assert.Equal(t, "first", checkpoints[0].Name)
+	assert.Equal(t, "second", checkpoints[1].Name)
+
+	entities, intents, err := store.CheckpointEntities(ctx, "first")
+	assert.NoError(t, err)
+	assert.Len(t, entities, 10)
+	assert.Len(t, intents, 10)
+	_, _, err = store.CheckpointEntities(ctx, "third")
+	assert.True(t, errors.IsNotFound(err))
+
+	// Checkpoints must not exceed the limit of their total size
+	assert.NoError(t, store.Write(ctx, generateRoutes(info, 200, p4api.Update_INSERT)[10:]))
+	_, err = store.Checkpoint(ctx, "third")
+	assert.True(t, errors.IsInvalid(err))
+
+	assert.NoError(t, store.DeleteCheckpoint(ctx, "first"))
+	assert.True(t, errors.IsNotFound(store.DeleteCheckpoint(ctx, "first")))
+	checkpoints, err = store.Checkpoints(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, checkpoints, 1)
+}
@@ -7,13 +7,14 @@ package store
 import (
 	"context"
 	"crypto/sha1"
-	_map "github.com/atomix/go-sdk/pkg/primitive/map"
+	"github.com/onosproject/onos-control/pkg/api"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
 	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/proto"
 	"hash"
-	"io"
 	"sort"
+	"sync/atomic"
 )
 
 /*
@@ -41,62 +42,102 @@ Read requests for direct meters and counters should be done straight from the de
 
 type table struct {
 	info    *p4info.Table
-	entries _map.Map[string, *p4api.TableEntry]
+	entries Map
+	cache   *tableCache
 }
 
-func (s *entityStore) modifyTableEntry(ctx context.Context, entry *p4api.TableEntry, insert bool) error {
-	t, key, err := s.findTableAndKey(entry)
-	if err != nil {
-		return err
-	}
-
-	if insert {
-		_, err = t.entries.Insert(ctx, key, entry)
-	} else {
-		_, err = t.entries.Update(ctx, key, entry)
-	}
-	if err != nil {
-		return errors.FromAtomix(err)
+// Decodes the table entry persisted in the given map entry, ignoring its intent
+func decodeTableEntry(entry *MapEntry) (*p4api.TableEntry, error) {
+	te := &p4api.TableEntry{}
+	if err := (proto.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(entry.Value, te); err != nil {
+		return nil, errors.NewInternal("Unable to decode entry %x of map: %+v", entry.Key, err)
 	}
-
-	// TODO: Implement updates of direct resources, if/as necessary
-	return nil
+	return te, nil
 }
 
-func (s *entityStore) readTableEntries(ctx context.Context, query *p4api.TableEntry, ch chan<- *p4api.Entity) error {
+func (s *entityStore) readTableEntries(ctx context.Context, query *p4api.TableEntry, emit func(entity *p4api.Entity, revision api.Revision) error) error {
 	if query.TableId != 0 {
 		t, ok := s.tables[query.TableId]
 		if !ok {
 			return errors.NewInvalid("No such table %d", query.TableId)
 		}
-		return s.readSpecificTableEntries(ctx, query.TableId, t, query, ch)
+		return s.readSpecificTableEntries(ctx, query.TableId, t, query, emit)
 	}
 	for id, t := range s.tables {
-		if err := s.readSpecificTableEntries(ctx, id, t, query, ch); err != nil {
+		if err := s.readSpecificTableEntries(ctx, id, t, query, emit); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *entityStore) readSpecificTableEntries(ctx context.Context, id uint32, t *table, query *p4api.TableEntry, ch chan<- *p4api.Entity) error {
-	stream, err := t.entries.List(ctx)
-	if err != nil {
-		return errors.FromAtomix(err)
+func (s *entityStore) readSpecificTableEntries(ctx context.Context, id uint32, t *table, query *p4api.TableEntry, emit func(entity *p4api.Entity, revision api.Revision) error) error {
+	if len(query.Match) > 0 || query.IsDefaultAction {
+		return s.readTableEntry(ctx, t, query, emit)
+	}
+	if t.cache != nil {
+		if entries, ok := t.cache.snapshot(); ok {
+			atomic.AddUint64(&s.cacheHits, 1)
+			for _, ce := range entries {
+				if s.matchesQuery(ce.entry, query) {
+					if err := emit(&p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: ce.entry}}, ce.revision); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+		atomic.AddUint64(&s.cacheMisses, 1)
 	}
 
-	for {
-		v, err := stream.Next()
+	return t.entries.List(ctx, func(entry *MapEntry) error {
+		te, err := decodeTableEntry(entry)
 		if err != nil {
-			if err == io.EOF {
-				return nil
-			}
 			return err
 		}
-		if s.matchesQuery(v.Value, query) {
-			ch <- &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: v.Value}}
+		if s.matchesQuery(te, query) {
+			return emit(&p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: te}}, entry.Revision)
+		}
+		return nil
+	})
+}
+
+// Reads the single entry of the table with the key given by the field matches of the query, or its default entry
+func (s *entityStore) readTableEntry(ctx context.Context, t *table, query *p4api.TableEntry, emit func(entity *p4api.Entity, revision api.Revision) error) error {
+	// Order a copy of the field matches, leaving the query intact
+	matches := append([]*p4api.FieldMatch{}, query.Match...)
+	sortFieldMatches(matches)
+	key, err := t.entryKey(&p4api.TableEntry{TableId: query.TableId, Match: matches, IsDefaultAction: query.IsDefaultAction})
+	if err != nil {
+		return err
+	}
+
+	if t.cache != nil {
+		if ce, ok := t.cache.get(key); ok {
+			atomic.AddUint64(&s.cacheHits, 1)
+			if ce != nil && s.matchesQuery(ce.entry, query) {
+				return emit(&p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: ce.entry}}, ce.revision)
+			}
+			return nil
+		}
+		atomic.AddUint64(&s.cacheMisses, 1)
+	}
+
+	entry, err := t.entries.Get(ctx, key)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
 		}
+		return err
+	}
+	te, err := decodeTableEntry(entry)
+	if err != nil {
+		return err
+	}
+	if s.matchesQuery(te, query) {
+		return emit(&p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: te}}, entry.Revision)
 	}
+	return nil
 }
 
 // Returns true if the specified entry matches the query
@@ -199,19 +240,6 @@ func sortFieldMatches(matches []*p4api.FieldMatch) {
 	}
 }
 
-func (s *entityStore) removeTableEntry(ctx context.Context, entry *p4api.TableEntry) error {
-	t, key, err := s.findTableAndKey(entry)
-	if err != nil {
-		return err
-	}
-
-	_, err = t.entries.Remove(ctx, key)
-	if err != nil {
-		return errors.FromAtomix(err)
-	}
-	return nil
-}
-
 // TODO: Implement the variants below
 
 func (s *entityStore) modifyCounterEntry(ctx context.Context, entry *p4api.CounterEntry, insert bool) error {
@@ -262,34 +290,34 @@ func (s *entityStore) deleteCloneSessionEntry(ctx context.Context, entry *p4api.
 	return nil
 }
 
-func (s *entityStore) readCounterEntries(ctx context.Context, entry *p4api.CounterEntry, ch chan<- *p4api.Entity) error {
+func (s *entityStore) readCounterEntries(ctx context.Context, entry *p4api.CounterEntry, emit func(entity *p4api.Entity, revision api.Revision) error) error {
 	return nil
 }
 
-func (s *entityStore) readDirectCounterEntries(ctx context.Context, entry *p4api.DirectCounterEntry, ch chan<- *p4api.Entity) error {
+func (s *entityStore) readDirectCounterEntries(ctx context.Context, entry *p4api.DirectCounterEntry, emit func(entity *p4api.Entity, revision api.Revision) error) error {
 	return nil
 }
 
-func (s *entityStore) readMeterEntries(ctx context.Context, entry *p4api.MeterEntry, ch chan<- *p4api.Entity) error {
+func (s *entityStore) readMeterEntries(ctx context.Context, entry *p4api.MeterEntry, emit func(entity *p4api.Entity, revision api.Revision) error) error {
 	return nil
 }
 
-func (s *entityStore) readDirectMeterEntries(ctx context.Context, entry *p4api.DirectMeterEntry, ch chan<- *p4api.Entity) error {
+func (s *entityStore) readDirectMeterEntries(ctx context.Context, entry *p4api.DirectMeterEntry, emit func(entity *p4api.Entity, revision api.Revision) error) error {
 	return nil
 }
 
-func (s *entityStore) readActionProfileGroups(ctx context.Context, group *p4api.ActionProfileGroup, ch chan<- *p4api.Entity) error {
+func (s *entityStore) readActionProfileGroups(ctx context.Context, group *p4api.ActionProfileGroup, emit func(entity *p4api.Entity, revision api.Revision) error) error {
 	return nil
 }
 
-func (s *entityStore) readActionProfileMembers(ctx context.Context, member *p4api.ActionProfileMember, ch chan<- *p4api.Entity) error {
+func (s *entityStore) readActionProfileMembers(ctx context.Context, member *p4api.ActionProfileMember, emit func(entity *p4api.Entity, revision api.Revision) error) error {
 	return nil
 }
 
-func (s *entityStore) readMulticastGroupEntries(ctx context.Context, entry *p4api.MulticastGroupEntry, ch chan<- *p4api.Entity) error {
+func (s *entityStore) readMulticastGroupEntries(ctx context.Context, entry *p4api.MulticastGroupEntry, emit func(entity *p4api.Entity, revision api.Revision) error) error {
 	return nil
 }
 
-func (s *entityStore) readCloneSessionEntries(ctx context.Context, entry *p4api.CloneSessionEntry, ch chan<- *p4api.Entity) error {
+func (s *entityStore) readCloneSessionEntries(ctx context.Context, entry *p4api.CloneSessionEntry, emit func(entity *p4api.Entity, revision api.Revision) error) error {
 	return nil
 }
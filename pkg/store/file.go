@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+const (
+	journalPut      = "put"
+	journalRemove   = "remove"
+	journalRevision = "revision"
+	journalBatch    = "batch"
+)
+
+// journalRecord is a single change of a map entry, as recorded in the file of the file backend
+type journalRecord struct {
+	Map      string       `json:"map"`
+	Op       string       `json:"op"`
+	Key      string       `json:"key"`
+	Value    []byte       `json:"value,omitempty"`
+	Revision api.Revision `json:"revision"`
+	// Batch holds the changes applied atomically as a single batch
+	Batch []*journalRecord `json:"batch,omitempty"`
+}
+
+type fileBackend struct {
+	*memoryBackend
+	path   string
+	fileMu sync.Mutex
+	file   *os.File
+}
+
+// NewFileBackend creates a backend durably storing the entity store maps in a single file at the given path,
+// which is created if it does not exist yet. Entries are served from memory; each change is appended to the file
+// and synced to disk before it is acknowledged, and the file is compacted whenever it is opened. The file must
+// not be used by more than one backend at a time.
+func NewFileBackend(path string) (Backend, error) {
+	b := &fileBackend{memoryBackend: newMemoryBackend(), path: path}
+	if err := b.load(); err != nil {
+		return nil, err
+	}
+	if err := b.compact(); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return nil, errors.NewInternal("Unable to open %s: %+v", path, err)
+	}
+	b.file = file
+	b.persist = b.append
+	return b, nil
+}
+
+// Replays the changes recorded in the file
+func (b *fileBackend) load() error {
+	file, err := os.Open(b.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.NewInternal("Unable to open %s: %+v", b.path, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// Ignore any trailing partial record left behind by an interrupted write
+			return nil
+		} else if err != nil {
+			return errors.NewInternal("Unable to read %s: %+v", b.path, err)
+		}
+		rec := &journalRecord{}
+		if err := json.Unmarshal(line, rec); err != nil {
+			return errors.NewInternal("Corrupted record in %s: %+v", b.path, err)
+		}
+		b.replay(rec)
+	}
+}
+
+// Applies the change described by the given record to the maps
+func (b *fileBackend) replay(rec *journalRecord) {
+	if rec.Op == journalBatch {
+		for _, r := range rec.Batch {
+			b.replay(r)
+		}
+		return
+	}
+	m := b.getMap(rec.Map)
+	switch rec.Op {
+	case journalPut:
+		m.entries[rec.Key] = &MapEntry{Key: rec.Key, Value: rec.Value, Revision: rec.Revision}
+	case journalRemove:
+		delete(m.entries, rec.Key)
+	}
+	if rec.Revision > m.revision {
+		m.revision = rec.Revision
+	}
+}
+
+// Rewrites the file to contain only the present entries, retaining the latest revision of each map
+func (b *fileBackend) compact() error {
+	tmpPath := b.path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return errors.NewInternal("Unable to create %s: %+v", tmpPath, err)
+	}
+	writer := bufio.NewWriter(file)
+	err = b.writeSnapshot(json.NewEncoder(writer))
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, b.path)
+	}
+	if err != nil {
+		return errors.NewInternal("Unable to compact %s: %+v", b.path, err)
+	}
+	return nil
+}
+
+func (b *fileBackend) writeSnapshot(encoder *json.Encoder) error {
+	for _, m := range b.maps {
+		// Record the revision of the map first, so that revisions are not reused after removals
+		if err := encoder.Encode(&journalRecord{Map: m.name, Op: journalRevision, Revision: m.revision}); err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(m.entries))
+		for key := range m.entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			entry := m.entries[key]
+			if err := encoder.Encode(&journalRecord{Map: m.name, Op: journalPut, Key: key, Value: entry.Value, Revision: entry.Revision}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Appends the given record to the file and syncs it to disk
+func (b *fileBackend) append(rec *journalRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return errors.NewInternal("Unable to encode record: %+v", err)
+	}
+	b.fileMu.Lock()
+	defer b.fileMu.Unlock()
+	if b.file == nil {
+		return errors.NewUnavailable("Backend %s is closed", b.path)
+	}
+	if _, err = b.file.Write(append(line, '\n')); err == nil {
+		err = b.file.Sync()
+	}
+	if err != nil {
+		return errors.NewInternal("Unable to write %s: %+v", b.path, err)
+	}
+	return nil
+}
+
+// Close closes the file; subsequent changes of the maps fail
+func (b *fileBackend) Close() error {
+	b.fileMu.Lock()
+	defer b.fileMu.Unlock()
+	if b.file == nil {
+		return nil
+	}
+	err := b.file.Close()
+	b.file = nil
+	if err != nil {
+		return errors.NewInternal("Unable to close %s: %+v", b.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"time"
+)
+
+// DeviceChecker determines whether devices exist
+type DeviceChecker interface {
+	// Exists returns true if the given device exists
+	Exists(ctx context.Context, id topo.ID) (bool, error)
+}
+
+// DeviceCheckerFunc is an adapter allowing use of ordinary functions as device checkers
+type DeviceCheckerFunc func(ctx context.Context, id topo.ID) (bool, error)
+
+// Exists returns true if the given device exists
+func (f DeviceCheckerFunc) Exists(ctx context.Context, id topo.ID) (bool, error) {
+	return f(ctx, id)
+}
+
+// NewTopoDeviceChecker returns a device checker considering devices to exist if onos-topo has an object for them
+func NewTopoDeviceChecker(client topo.TopoClient) DeviceChecker {
+	return DeviceCheckerFunc(func(ctx context.Context, id topo.ID) (bool, error) {
+		_, err := client.Get(ctx, &topo.GetRequest{ID: id})
+		if err == nil {
+			return true, nil
+		}
+		if err = errors.FromGRPC(err); errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	})
+}
+
+// CollectGarbage purges the entity stores of devices which have been continuously found not to exist for at least
+// the grace period. Devices found not to exist are recorded in the registry, so the grace period spans restarts;
+// devices found to exist again are no longer considered missing. Returns the IDs of the devices whose stores were
+// purged. Only the stores in the namespace of the stores manager are considered.
+func (sm *storeManager) CollectGarbage(ctx context.Context, checker DeviceChecker, grace time.Duration) ([]topo.ID, error) {
+	records, err := listStores(ctx, sm.backend, sm.namespace)
+	if err != nil {
+		return nil, err
+	}
+	purged := make([]topo.ID, 0)
+	now := time.Now()
+	for _, record := range records {
+		exists, err := checker.Exists(ctx, record.ID)
+		if err != nil {
+			// Err on the side of caution; the device may well exist
+			log.Warnf("Unable to determine whether device %s exists: %+v", record.ID, err)
+			continue
+		}
+		switch {
+		case exists && !record.MissingSince.IsZero():
+			log.Infof("Device %s exists again", record.ID)
+			err = updateStoreRecord(ctx, sm.backend, sm.namespace, record.ID, func(record *StoreRecord) {
+				record.MissingSince = time.Time{}
+			})
+		case !exists && record.MissingSince.IsZero():
+			log.Infof("Device %s no longer exists; its store will be purged after %s", record.ID, grace)
+			err = updateStoreRecord(ctx, sm.backend, sm.namespace, record.ID, func(record *StoreRecord) {
+				record.MissingSince = now
+			})
+		case !exists && now.Sub(record.MissingSince) >= grace:
+			log.Infof("Device %s has not existed since %s; purging its store", record.ID, record.MissingSince)
+			if err = sm.Purge(ctx, record.ID); err == nil {
+				purged = append(purged, record.ID)
+			}
+		}
+		if err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
+// RunGarbageCollector periodically collects garbage of the given stores, at the specified interval, until the
+// context is cancelled
+func RunGarbageCollector(ctx context.Context, stores Stores, checker DeviceChecker, grace time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := stores.CollectGarbage(ctx, checker, grace); err != nil {
+			log.Warnf("Unable to collect garbage of stores in namespace %s: %+v", stores.Namespace(), err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
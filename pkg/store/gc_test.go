@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestStoresRegistryAndGC(t *testing.T) {
+	ctx := context.TODO()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	backend := NewMemoryBackend()
+	query := []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{}}}}
+
+	stores := NewStoreManager(backend, DefaultNamespace)
+	for _, id := range []topo.ID{"foo", "bar"} {
+		store, err := stores.Get(ctx, id, info)
+		assert.NoError(t, err)
+		assert.NoError(t, store.Write(ctx, generateRoutes(info, 3, p4api.Update_INSERT)))
+	}
+
+	// Stores from earlier runs must be listed and accessible
+	stores = NewStoreManager(backend, DefaultNamespace)
+	assert.Empty(t, stores.GetAll())
+	records, err := stores.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, topo.ID("bar"), records[0].ID)
+	assert.Equal(t, Fingerprint(info), records[0].Fingerprint)
+	assert.False(t, records[0].Created.IsZero())
+	store, err := stores.Get(ctx, "bar", nil)
+	assert.NoError(t, err)
+	readEntries(ctx, t, store, query, 3)
+	_, err = stores.Get(ctx, "baz", nil)
+	assert.True(t, errors.IsNotFound(err))
+
+	// Stores in other namespaces must not be considered
+	others := NewStoreManager(backend, Namespace{App: "other", Role: "default"})
+	_, err = others.Get(ctx, "bar", info)
+	assert.NoError(t, err)
+
+	exists := map[topo.ID]bool{"foo": true}
+	checker := DeviceCheckerFunc(func(ctx context.Context, id topo.ID) (bool, error) {
+		return exists[id], nil
+	})
+
+	// Missing devices must be purged only after the grace period
+	purged, err := stores.CollectGarbage(ctx, checker, time.Hour)
+	assert.NoError(t, err)
+	assert.Empty(t, purged)
+	records, err = stores.List(ctx)
+	assert.NoError(t, err)
+	assert.False(t, records[0].MissingSince.IsZero())
+	assert.True(t, records[1].MissingSince.IsZero())
+
+	// Devices which re-appear must no longer be considered missing
+	exists["bar"] = true
+	_, err = stores.CollectGarbage(ctx, checker, 0)
+	assert.NoError(t, err)
+	records, err = stores.List(ctx)
+	assert.NoError(t, err)
+	assert.True(t, records[0].MissingSince.IsZero())
+
+	delete(exists, "bar")
+	_, err = stores.CollectGarbage(ctx, checker, 0)
+	assert.NoError(t, err)
+	purged, err = stores.CollectGarbage(ctx, checker, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []topo.ID{"bar"}, purged)
+
+	records, err = stores.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, topo.ID("foo"), records[0].ID)
+	_, err = stores.Get(ctx, "bar", nil)
+	assert.True(t, errors.IsNotFound(err))
+	store, err = stores.Get(ctx, "bar", info)
+	assert.NoError(t, err)
+	readEntries(ctx, t, store, query, 0)
+
+	records, err = others.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+}
@@ -0,0 +1,313 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"strconv"
+	"sync/atomic"
+)
+
+type indexKind int
+
+const (
+	actionIndex indexKind = iota
+	actionParamIndex
+	matchFieldIndex
+	intentLabelIndex
+)
+
+// Index describes a secondary index of the persisted table entries, identifying the indexed attribute by name
+type Index struct {
+	kind   indexKind
+	table  string
+	field  string
+	action string
+	param  string
+	label  string
+}
+
+// ActionIndex indexes table entries by the IDs of their actions, including those of their action profile action sets
+func ActionIndex() Index {
+	return Index{kind: actionIndex}
+}
+
+// ActionParamIndex indexes table entries by the value of the given parameter of the specified action, e.g. to find
+// all routes pointing at a particular next hop
+func ActionParamIndex(action string, param string) Index {
+	return Index{kind: actionParamIndex, action: action, param: param}
+}
+
+// MatchFieldIndex indexes entries of the given table by the value of the specified match field
+func MatchFieldIndex(table string, field string) Index {
+	return Index{kind: matchFieldIndex, table: table, field: field}
+}
+
+// IntentLabelIndex indexes table entries by the value of the given label of the intent they belong to
+func IntentLabelIndex(label string) Index {
+	return Index{kind: intentLabelIndex, label: label}
+}
+
+// WithIndexes maintains the given secondary indexes of the table entries in memory, allowing lookups by the indexed
+// attributes without scanning the tables. The indexes are maintained along with the cache, which they imply.
+func WithIndexes(indexes ...Index) Option {
+	return func(s *entityStore) {
+		s.cached = true
+		s.indexes = append(s.indexes, indexes...)
+	}
+}
+
+// indexDef is an index with its attributes resolved to their IDs; also used to describe lookups of unindexed
+// attributes, which are served by scanning the tables
+type indexDef struct {
+	kind     indexKind
+	tableID  uint32
+	fieldID  uint32
+	actionID uint32
+	paramID  uint32
+	label    string
+}
+
+// Resolves the attributes of the configured indexes using the P4Info of the store
+func (s *entityStore) resolveIndexes() error {
+	for _, index := range s.indexes {
+		def := indexDef{kind: index.kind, label: index.label}
+		switch index.kind {
+		case actionParamIndex:
+			action := p4utils.FindAction(s.info, index.action)
+			if action == nil {
+				return errors.NewInvalid("Unable to index unknown action %s", index.action)
+			}
+			param := p4utils.FindActionParam(action, index.param)
+			if param == nil {
+				return errors.NewInvalid("Unable to index unknown parameter %s of action %s", index.param, index.action)
+			}
+			def.actionID, def.paramID = action.Preamble.Id, param.Id
+		case matchFieldIndex:
+			table := p4utils.FindTable(s.info, index.table)
+			if table == nil {
+				return errors.NewInvalid("Unable to index unknown table %s", index.table)
+			}
+			field := p4utils.FindTableMatchField(table, index.field)
+			if field == nil {
+				return errors.NewInvalid("Unable to index unknown match field %s of table %s", index.field, index.table)
+			}
+			def.tableID, def.fieldID = table.Preamble.Id, field.Id
+		case intentLabelIndex:
+			if index.label == "" {
+				return errors.NewInvalid("Unable to index empty intent label")
+			}
+		}
+		s.indexDefs = append(s.indexDefs, def)
+	}
+	return nil
+}
+
+// Returns the definitions of the indexes applicable to the given table
+func (s *entityStore) tableIndexDefs(tableID uint32) []indexDef {
+	defs := make([]indexDef, 0, len(s.indexDefs))
+	for _, def := range s.indexDefs {
+		if def.appliesTo(tableID) {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
+
+// Returns true if entries of the given table may be indexed by the attribute
+func (d indexDef) appliesTo(tableID uint32) bool {
+	return d.kind != matchFieldIndex || d.tableID == tableID
+}
+
+// Returns the values of the attribute of the given entry, belonging to the specified intent
+func (d indexDef) values(entry *p4api.TableEntry, intent *api.Intent) []string {
+	values := make([]string, 0, 1)
+	switch d.kind {
+	case actionIndex:
+		for _, action := range entryActions(entry) {
+			values = append(values, strconv.FormatUint(uint64(action.ActionId), 10))
+		}
+	case actionParamIndex:
+		for _, action := range entryActions(entry) {
+			if action.ActionId != d.actionID {
+				continue
+			}
+			for _, p := range action.Params {
+				if p.ParamId == d.paramID {
+					values = append(values, canonicalValue(p.Value))
+				}
+			}
+		}
+	case matchFieldIndex:
+		if entry.TableId != d.tableID {
+			break
+		}
+		for _, m := range entry.Match {
+			if m.FieldId == d.fieldID {
+				values = append(values, canonicalValue(matchValue(m)))
+			}
+		}
+	case intentLabelIndex:
+		if intent == nil {
+			break
+		}
+		if value, ok := intent.Labels[d.label]; ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Returns true if the attribute of the given entry, belonging to the specified intent, has the given value
+func (d indexDef) matches(entry *p4api.TableEntry, intent *api.Intent, value string) bool {
+	for _, v := range d.values(entry, intent) {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the actions of the given table entry, including those of its action profile action set
+func entryActions(entry *p4api.TableEntry) []*p4api.Action {
+	switch {
+	case entry.Action.GetAction() != nil:
+		return []*p4api.Action{entry.Action.GetAction()}
+	case entry.Action.GetActionProfileActionSet() != nil:
+		actions := make([]*p4api.Action, 0, len(entry.Action.GetActionProfileActionSet().ActionProfileActions))
+		for _, pa := range entry.Action.GetActionProfileActionSet().ActionProfileActions {
+			actions = append(actions, pa.Action)
+		}
+		return actions
+	}
+	return nil
+}
+
+// Returns the given bytestring value in canonical form, i.e. without leading zeros
+func canonicalValue(value []byte) string {
+	i := 0
+	for i < len(value) && value[i] == 0 {
+		i++
+	}
+	return string(value[i:])
+}
+
+// tableIndex maps the values of an attribute to the keys of the cached table entries having them
+type tableIndex struct {
+	def  indexDef
+	keys map[string]map[string]bool
+}
+
+func newTableIndex(def indexDef) *tableIndex {
+	return &tableIndex{def: def, keys: make(map[string]map[string]bool)}
+}
+
+func (i *tableIndex) add(key string, ce *cachedEntry) {
+	for _, value := range i.def.values(ce.entry, ce.intent) {
+		if i.keys[value] == nil {
+			i.keys[value] = make(map[string]bool)
+		}
+		i.keys[value][key] = true
+	}
+}
+
+func (i *tableIndex) remove(key string, ce *cachedEntry) {
+	for _, value := range i.def.values(ce.entry, ce.intent) {
+		delete(i.keys[value], key)
+		if len(i.keys[value]) == 0 {
+			delete(i.keys, value)
+		}
+	}
+}
+
+// ReadByAction returns an iterator over all table entries using the given action, directly or as part of their
+// action profile action set
+func (s *entityStore) ReadByAction(ctx context.Context, actionID uint32) api.EntityIterator {
+	return s.readByIndex(ctx, indexDef{kind: actionIndex}, strconv.FormatUint(uint64(actionID), 10))
+}
+
+// ReadByActionParam returns an iterator over all table entries using the given action with the specified parameter
+// set to the given value
+func (s *entityStore) ReadByActionParam(ctx context.Context, actionID uint32, paramID uint32, value []byte) api.EntityIterator {
+	return s.readByIndex(ctx, indexDef{kind: actionParamIndex, actionID: actionID, paramID: paramID}, canonicalValue(value))
+}
+
+// ReadByMatchField returns an iterator over all entries of the given table whose specified match field has the given
+// value
+func (s *entityStore) ReadByMatchField(ctx context.Context, tableID uint32, fieldID uint32, value []byte) api.EntityIterator {
+	return s.readByIndex(ctx, indexDef{kind: matchFieldIndex, tableID: tableID, fieldID: fieldID}, canonicalValue(value))
+}
+
+// ReadByLabel returns an iterator over all table entries belonging to intents having the given label with the
+// specified value
+func (s *entityStore) ReadByLabel(ctx context.Context, label string, value string) api.EntityIterator {
+	return s.readByIndex(ctx, indexDef{kind: intentLabelIndex, label: label}, value)
+}
+
+// Returns an iterator over all table entries whose attribute has the given value; the entries are looked up using
+// the corresponding index if it is maintained and synchronized, and found by scanning the tables otherwise
+func (s *entityStore) readByIndex(ctx context.Context, def indexDef, value string) api.EntityIterator {
+	return api.NewEntityIterator(ctx, 1, func(ctx context.Context, i int, emit func(entity *p4api.Entity, revision api.Revision) error) error {
+		if def.kind == matchFieldIndex {
+			if _, ok := s.tables[def.tableID]; !ok {
+				return errors.NewInvalid("No such table %d", def.tableID)
+			}
+		}
+		for id, t := range s.tables {
+			if !def.appliesTo(id) {
+				continue
+			}
+			if err := s.readTableByIndex(ctx, t, def, value, emit); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *entityStore) readTableByIndex(ctx context.Context, t *table, def indexDef, value string, emit func(entity *p4api.Entity, revision api.Revision) error) error {
+	if t.cache != nil {
+		if entries, ok := t.cache.lookup(def, value); ok {
+			atomic.AddUint64(&s.indexHits, 1)
+			return emitCached(entries, emit)
+		}
+	}
+
+	atomic.AddUint64(&s.indexScans, 1)
+	if t.cache != nil {
+		if entries, ok := t.cache.snapshot(); ok {
+			matching := make([]*cachedEntry, 0)
+			for _, ce := range entries {
+				if def.matches(ce.entry, ce.intent, value) {
+					matching = append(matching, ce)
+				}
+			}
+			return emitCached(matching, emit)
+		}
+	}
+	return t.entries.List(ctx, func(entry *MapEntry) error {
+		te, intent, err := decodeStoredEntry(entry)
+		if err != nil {
+			return err
+		}
+		if def.matches(te, intent, value) {
+			return emit(&p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: te}}, entry.Revision)
+		}
+		return nil
+	})
+}
+
+func emitCached(entries []*cachedEntry, emit func(entity *p4api.Entity, revision api.Revision) error) error {
+	for _, ce := range entries {
+		if err := emit(&p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: ce.entry}}, ce.revision); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	testutils "github.com/onosproject/onos-net-lib/pkg/test"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var testIndexes = []Index{
+	ActionIndex(),
+	ActionParamIndex("FabricIngress.forwarding.set_next_id_routing_v4", "next_id"),
+	MatchFieldIndex("FabricIngress.forwarding.mpls", "mpls_label"),
+	IntentLabelIndex("tenant"),
+}
+
+func TestIndexes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend Backend) {
+		ctx := context.TODO()
+		info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+		assert.NoError(t, err)
+
+		indexed, err := NewEntityStore(ctx, backend, "foo", info, WithIndexes(testIndexes...))
+		assert.NoError(t, err)
+		defer indexed.(*entityStore).stopCaches()
+		testIndexLookups(ctx, t, info, indexed, indexed)
+		assert.Equal(t, uint64(0), indexed.CacheStats().IndexScans)
+		assert.Greater(t, indexed.CacheStats().IndexHits, uint64(0))
+
+		// Lookups without indexes must yield the same results by scanning the tables
+		unindexed, err := NewEntityStore(ctx, backend, "bar", info)
+		assert.NoError(t, err)
+		testIndexLookups(ctx, t, info, unindexed, unindexed)
+
+		// Changes made by other clients of the backend must be reflected in the indexes
+		other, err := NewEntityStore(ctx, backend, "foo", info)
+		assert.NoError(t, err)
+		action := p4utils.FindAction(info, "FabricIngress.forwarding.set_next_id_routing_v4")
+		modifies := generateRoutes(info, 20, p4api.Update_MODIFY)[15:]
+		for _, u := range modifies {
+			u.Entity.GetTableEntry().Action.GetAction().Params[0].Value = []byte{42}
+		}
+		assert.NoError(t, other.Write(ctx, modifies))
+		assert.Eventually(t, func() bool {
+			entities, err := api.ReadAll(indexed.ReadByActionParam(ctx, action.Preamble.Id, 1, []byte{42}))
+			return err == nil && len(entities) == 5
+		}, 5*time.Second, 10*time.Millisecond)
+		readEntities(ctx, t, indexed.ReadByActionParam(ctx, action.Preamble.Id, 1, []byte{17}), 0)
+		assert.Equal(t, uint64(0), indexed.CacheStats().IndexScans)
+	})
+}
+
+// Populates the writer store with routes, MPLS entries and intents and validates lookups using the reader store
+func testIndexLookups(ctx context.Context, t *testing.T, info *p4info.P4Info, writer EntityStore, reader EntityStore) {
+	routes := generateRoutes(info, 20, p4api.Update_INSERT)
+	mpls := p4utils.FindTable(info, "FabricIngress.forwarding.mpls")
+	updates := append([]*p4api.Update{}, routes...)
+	for i := 1; i <= 3; i++ {
+		entry := testutils.GenerateTableEntry(mpls, 0, nil)
+		entry.Match[0].GetExact().Value = []byte{0, 0, byte(i)}
+		updates = append(updates, &p4api.Update{Type: p4api.Update_INSERT, Entity: &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}})
+	}
+	opts := []WriteOption{
+		WithIntent(routes[1], &api.Intent{ID: "h1", Labels: map[string]string{"tenant": "blue"}}),
+		WithIntent(routes[2], &api.Intent{ID: "h2", Labels: map[string]string{"tenant": "blue"}}),
+		WithIntent(routes[3], &api.Intent{ID: "h3", Labels: map[string]string{"tenant": "red"}}),
+	}
+	assert.NoError(t, writer.Write(ctx, updates, opts...))
+
+	action := p4utils.FindAction(info, "FabricIngress.forwarding.set_next_id_routing_v4")
+	entities := readEntities(ctx, t, reader.ReadByActionParam(ctx, action.Preamble.Id, 1, []byte{17}), 1)
+	assert.Equal(t, []byte{17}, entities[0].GetTableEntry().Action.GetAction().Params[0].Value)
+	readEntities(ctx, t, reader.ReadByActionParam(ctx, action.Preamble.Id, 1, []byte{0, 0, 0, 17}), 1)
+	readEntities(ctx, t, reader.ReadByActionParam(ctx, action.Preamble.Id, 1, []byte{99}), 0)
+	readEntities(ctx, t, reader.ReadByAction(ctx, action.Preamble.Id), 20)
+	readEntities(ctx, t, reader.ReadByMatchField(ctx, mpls.Preamble.Id, 1, []byte{2}), 1)
+	readEntities(ctx, t, reader.ReadByLabel(ctx, "tenant", "blue"), 2)
+	readEntities(ctx, t, reader.ReadByLabel(ctx, "tenant", "green"), 0)
+
+	// Modified and deleted entries must no longer be found by their former values
+	modify := generateRoutes(info, 18, p4api.Update_MODIFY)[17]
+	modify.Entity.GetTableEntry().Action.GetAction().Params[0].Value = []byte{3}
+	remove := &p4api.Update{Type: p4api.Update_DELETE, Entity: updates[21].Entity}
+	assert.NoError(t, writer.Write(ctx, []*p4api.Update{modify, remove}))
+	readEntities(ctx, t, reader.ReadByActionParam(ctx, action.Preamble.Id, 1, []byte{17}), 0)
+	readEntities(ctx, t, reader.ReadByActionParam(ctx, action.Preamble.Id, 1, []byte{3}), 2)
+	readEntities(ctx, t, reader.ReadByMatchField(ctx, mpls.Preamble.Id, 1, []byte{2}), 0)
+}
+
+func readEntities(ctx context.Context, t *testing.T, iterator api.EntityIterator, count int) []*p4api.Entity {
+	entities, err := api.ReadAll(iterator)
+	assert.NoError(t, err)
+	assert.Len(t, entities, count)
+	return entities
+}
+
+func TestInvalidIndexes(t *testing.T) {
+	ctx := context.TODO()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	for _, index := range []Index{
+		ActionParamIndex("FabricIngress.forwarding.set_next_id_routing_v4", "nope"),
+		ActionParamIndex("nope", "next_id"),
+		MatchFieldIndex("FabricIngress.forwarding.mpls", "nope"),
+		MatchFieldIndex("nope", "mpls_label"),
+		IntentLabelIndex(""),
+	} {
+		_, err = NewEntityStore(ctx, NewMemoryBackend(), "foo", info, WithIndexes(index))
+		assert.True(t, errors.IsInvalid(err))
+	}
+}
@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"sync/atomic"
+)
+
+// The intent of a table entry is persisted alongside the entry, as extra fields appended to its protobuf encoding.
+// The field numbers are well beyond those of the table entry message, so the entry itself decodes unaffected and
+// entries persisted without intent remain valid.
+const (
+	intentIDField    protowire.Number = 1 << 20
+	intentLabelField protowire.Number = 1<<20 + 1
+	labelKeyField    protowire.Number = 1
+	labelValueField  protowire.Number = 2
+)
+
+// WithIntent associates the given update of a table entry with the specified intent. Inserted entries without
+// intent belong to no intent, while modified entries without intent retain the intent they belong to.
+func WithIntent(update *p4api.Update, intent *api.Intent) WriteOption {
+	return func(w *writeOptions) {
+		w.intents[update] = intent
+	}
+}
+
+// Encodes the given table entry along with its intent, if any
+func encodeTableEntry(entry *p4api.TableEntry, intent *api.Intent) ([]byte, error) {
+	value, err := proto.Marshal(entry)
+	if err != nil {
+		return nil, errors.NewInvalid("Unable to encode entry: %+v", err)
+	}
+	if intent == nil {
+		return value, nil
+	}
+	value = protowire.AppendTag(value, intentIDField, protowire.BytesType)
+	value = protowire.AppendString(value, intent.ID)
+	for k, v := range intent.Labels {
+		var label []byte
+		label = protowire.AppendTag(label, labelKeyField, protowire.BytesType)
+		label = protowire.AppendString(label, k)
+		label = protowire.AppendTag(label, labelValueField, protowire.BytesType)
+		label = protowire.AppendString(label, v)
+		value = protowire.AppendTag(value, intentLabelField, protowire.BytesType)
+		value = protowire.AppendBytes(value, label)
+	}
+	return value, nil
+}
+
+// Decodes the table entry persisted in the given map entry along with its intent; the intent is nil if the entry
+// belongs to no intent
+func decodeStoredEntry(entry *MapEntry) (*p4api.TableEntry, *api.Intent, error) {
+	te, err := decodeTableEntry(entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	intent, err := decodeIntent(entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	return te, intent, nil
+}
+
+// Decodes the intent of the table entry persisted in the given map entry; returns nil if the entry belongs to no
+// intent
+func decodeIntent(entry *MapEntry) (*api.Intent, error) {
+	var intent *api.Intent
+	b := entry.Value
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, errors.NewInternal("Unable to decode entry %x of map: %+v", entry.Key, protowire.ParseError(n))
+		}
+		b = b[n:]
+		if (num != intentIDField && num != intentLabelField) || typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+				return nil, errors.NewInternal("Unable to decode entry %x of map: %+v", entry.Key, protowire.ParseError(n))
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return nil, errors.NewInternal("Unable to decode intent of entry %x of map: %+v", entry.Key, protowire.ParseError(n))
+		}
+		b = b[n:]
+		if intent == nil {
+			intent = &api.Intent{}
+		}
+		if num == intentIDField {
+			intent.ID = string(v)
+			continue
+		}
+		key, value, err := decodeLabel(v)
+		if err != nil {
+			return nil, errors.NewInternal("Unable to decode intent of entry %x of map: %+v", entry.Key, err)
+		}
+		if intent.Labels == nil {
+			intent.Labels = make(map[string]string)
+		}
+		intent.Labels[key] = value
+	}
+	return intent, nil
+}
+
+func decodeLabel(b []byte) (string, string, error) {
+	var key, value string
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return "", "", protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			return "", "", errors.NewInvalid("unexpected label field %d", num)
+		}
+		v, n := protowire.ConsumeString(b)
+		if n < 0 {
+			return "", "", protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch num {
+		case labelKeyField:
+			key = v
+		case labelValueField:
+			value = v
+		}
+	}
+	return key, value, nil
+}
+
+// ReadByIntent returns an iterator over all persisted entities belonging to the given intent
+func (s *entityStore) ReadByIntent(ctx context.Context, id string) api.EntityIterator {
+	return api.NewEntityIterator(ctx, 1, func(ctx context.Context, i int, emit func(entity *p4api.Entity, revision api.Revision) error) error {
+		if id == "" {
+			return errors.NewInvalid("Intent ID must not be empty")
+		}
+		for _, t := range s.tables {
+			if err := s.readTableIntent(ctx, t, id, emit); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *entityStore) readTableIntent(ctx context.Context, t *table, id string, emit func(entity *p4api.Entity, revision api.Revision) error) error {
+	if t.cache != nil {
+		if entries, ok := t.cache.snapshot(); ok {
+			atomic.AddUint64(&s.cacheHits, 1)
+			for _, ce := range entries {
+				if ce.intent != nil && ce.intent.ID == id {
+					if err := emit(&p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: ce.entry}}, ce.revision); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+		atomic.AddUint64(&s.cacheMisses, 1)
+	}
+
+	return t.entries.List(ctx, func(entry *MapEntry) error {
+		intent, err := decodeIntent(entry)
+		if err != nil {
+			return err
+		}
+		if intent == nil || intent.ID != id {
+			return nil
+		}
+		te, err := decodeTableEntry(entry)
+		if err != nil {
+			return err
+		}
+		return emit(&p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: te}}, entry.Revision)
+	})
+}
+
+// Lists all persisted entries of the given table along with the intents to which they belong
+func (s *entityStore) listTableIntents(ctx context.Context, t *table, emit func(entry *p4api.TableEntry, intent *api.Intent, revision api.Revision) error) error {
+	if t.cache != nil {
+		if entries, ok := t.cache.snapshot(); ok {
+			atomic.AddUint64(&s.cacheHits, 1)
+			for _, ce := range entries {
+				if err := emit(ce.entry, ce.intent, ce.revision); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		atomic.AddUint64(&s.cacheMisses, 1)
+	}
+
+	return t.entries.List(ctx, func(entry *MapEntry) error {
+		te, intent, err := decodeStoredEntry(entry)
+		if err != nil {
+			return err
+		}
+		return emit(te, intent, entry.Revision)
+	})
+}
+
+// IntentOf returns the intent to which the given persisted entity belongs; nil if it belongs to no intent
+func (s *entityStore) IntentOf(ctx context.Context, entity *p4api.Entity) (*api.Intent, error) {
+	if entity.GetTableEntry() == nil {
+		return nil, errors.NewInvalid("Intents are supported only for table entries")
+	}
+	t, key, err := s.findTableAndKey(entity.GetTableEntry())
+	if err != nil {
+		return nil, err
+	}
+	entry, err := t.entries.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return decodeIntent(entry)
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIntents(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend Backend) {
+		testIntents(t, backend)
+		testIntents(t, backend, WithCache(), WithNamespace(Namespace{App: "cached", Role: "default"}))
+	})
+}
+
+func testIntents(t *testing.T, backend Backend, opts ...Option) {
+	ctx := context.TODO()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	store, err := NewEntityStore(ctx, backend, "foo", info, opts...)
+	assert.NoError(t, err)
+
+	host := &api.Intent{ID: "host", Labels: map[string]string{"tenant": "blue", "host": "h1"}}
+	tenant := &api.Intent{ID: "tenant"}
+	updates := generateRoutes(info, 10, p4api.Update_INSERT)
+	writeOpts := make([]WriteOption, 0)
+	for i, update := range updates {
+		switch {
+		case i < 5:
+			writeOpts = append(writeOpts, WithIntent(update, host))
+		case i < 8:
+			writeOpts = append(writeOpts, WithIntent(update, tenant))
+		}
+	}
+	assert.NoError(t, store.Write(ctx, updates, writeOpts...))
+
+	entities, err := api.ReadAll(store.ReadByIntent(ctx, "host"))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 5)
+	entities, err = api.ReadAll(store.ReadByIntent(ctx, "tenant"))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 3)
+	_, err = api.ReadAll(store.ReadByIntent(ctx, ""))
+	assert.True(t, errors.IsInvalid(err))
+
+	intent, err := store.IntentOf(ctx, updates[0].Entity)
+	assert.NoError(t, err)
+	assert.Equal(t, host, intent)
+	intent, err = store.IntentOf(ctx, updates[9].Entity)
+	assert.NoError(t, err)
+	assert.Nil(t, intent)
+
+	// Modified entries must retain their intent, unless given a new one
+	modifies := generateRoutes(info, 2, p4api.Update_MODIFY)
+	assert.NoError(t, store.Write(ctx, modifies, WithIntent(modifies[1], tenant)))
+	intent, err = store.IntentOf(ctx, modifies[0].Entity)
+	assert.NoError(t, err)
+	assert.Equal(t, host, intent)
+	entities, err = api.ReadAll(store.ReadByIntent(ctx, "host"))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 4)
+	entities, err = api.ReadAll(store.ReadByIntent(ctx, "tenant"))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 4)
+
+	// Intents are not applicable to entities other than table entries
+	counter := &p4api.Update{Type: p4api.Update_MODIFY, Entity: &p4api.Entity{Entity: &p4api.Entity_CounterEntry{CounterEntry: &p4api.CounterEntry{}}}}
+	err = store.Write(ctx, []*p4api.Update{counter}, WithIntent(counter, host))
+	assert.True(t, errors.IsInvalid(err))
+	err = store.Write(ctx, modifies[:1], WithIntent(modifies[0], &api.Intent{}))
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestIntentMigration(t *testing.T) {
+	ctx := context.TODO()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	backend := NewMemoryBackend()
+
+	store, err := NewStoreManager(backend, DefaultNamespace).Get(ctx, "foo", info)
+	assert.NoError(t, err)
+	updates := generateRoutes(info, 4, p4api.Update_INSERT)
+	assert.NoError(t, store.Write(ctx, updates, WithIntent(updates[0], &api.Intent{ID: "host"})))
+
+	store, err = NewStoreManager(backend, DefaultNamespace).Get(ctx, "foo", nextP4Info(info))
+	assert.NoError(t, err)
+	entities, err := api.ReadAll(store.ReadByIntent(ctx, "host"))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 1)
+	assert.Equal(t, uint32(12345), entities[0].GetTableEntry().TableId)
+}
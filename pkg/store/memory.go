@@ -0,0 +1,301 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"sort"
+	"sync"
+)
+
+// memoryWatchBuffer is the number of events buffered for each watcher of an in-memory map; watchers falling
+// further behind lose their event stream
+const memoryWatchBuffer = 4096
+
+type memoryBackend struct {
+	mu      sync.Mutex
+	maps    map[string]*memoryMap
+	persist func(rec *journalRecord) error
+}
+
+// NewMemoryBackend creates a backend keeping the entity store maps in memory of the process; the entries are lost
+// once the process terminates. Maps of the same name obtained from the same backend are shared.
+func NewMemoryBackend() Backend {
+	return newMemoryBackend()
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{maps: make(map[string]*memoryMap)}
+}
+
+// Map returns the map with the given name, creating it if it does not exist yet
+func (b *memoryBackend) Map(ctx context.Context, name string) (Map, error) {
+	return b.getMap(name), nil
+}
+
+func (b *memoryBackend) getMap(name string) *memoryMap {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m, ok := b.maps[name]
+	if !ok {
+		m = &memoryMap{backend: b, name: name, entries: make(map[string]*MapEntry)}
+		b.maps[name] = m
+	}
+	return m
+}
+
+// Close releases the resources held by the backend
+func (b *memoryBackend) Close() error {
+	return nil
+}
+
+type memoryWatcher struct {
+	events chan *MapEvent
+}
+
+type memoryMap struct {
+	backend  *memoryBackend
+	name     string
+	mu       sync.RWMutex
+	entries  map[string]*MapEntry
+	revision api.Revision
+	watchers []*memoryWatcher
+}
+
+func (m *memoryMap) Name() string {
+	return m.name
+}
+
+func (m *memoryMap) Insert(ctx context.Context, key string, value []byte) (*MapEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.entries[key]; ok {
+		return nil, errors.NewAlreadyExists("Entry %s already exists in map %s", key, m.name)
+	}
+	return m.put(key, value, EntityInserted)
+}
+
+func (m *memoryMap) Update(ctx context.Context, key string, value []byte, revision api.Revision) (*MapEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.check(key, revision); err != nil {
+		return nil, err
+	}
+	return m.put(key, value, EntityUpdated)
+}
+
+func (m *memoryMap) Remove(ctx context.Context, key string, revision api.Revision) (*MapEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, err := m.check(key, revision)
+	if err != nil {
+		return nil, err
+	}
+	if m.backend.persist != nil {
+		if err := m.backend.persist(&journalRecord{Map: m.name, Op: journalRemove, Key: key, Revision: m.revision + 1}); err != nil {
+			return nil, err
+		}
+	}
+	m.revision++
+	delete(m.entries, key)
+	m.notify(&MapEvent{Type: EntityRemoved, Entry: entry})
+	return entry, nil
+}
+
+// Apply applies the given operations as a single atomic change; the changes are staged and take effect, along with
+// their events, only once all operations have succeeded
+func (m *memoryMap) Apply(ctx context.Context, ops []*MapOp) ([]*MapEntry, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Staged entries by their keys; nil for removed entries
+	staged := make(map[string]*MapEntry, len(ops))
+	present := func(key string) *MapEntry {
+		if entry, ok := staged[key]; ok {
+			return entry
+		}
+		return m.entries[key]
+	}
+
+	revision := m.revision
+	results := make([]*MapEntry, len(ops))
+	records := make([]*journalRecord, 0, len(ops))
+	events := make([]*MapEvent, 0, len(ops))
+	for i, op := range ops {
+		existing := present(op.Key)
+		if op.Type == MapInsert {
+			if existing != nil {
+				return nil, i, errors.NewAlreadyExists("Entry %s already exists in map %s", op.Key, m.name)
+			}
+		} else if existing == nil {
+			return nil, i, errors.NewNotFound("Entry %s not found in map %s", op.Key, m.name)
+		} else if op.Revision != 0 && existing.Revision != op.Revision {
+			return nil, i, errors.NewConflict("Entry %s in map %s is at revision %d; expected %d", op.Key, m.name, existing.Revision, op.Revision)
+		}
+
+		revision++
+		switch op.Type {
+		case MapInsert, MapUpdate:
+			value := op.Value
+			if op.Type == MapUpdate && op.Merge != nil {
+				var err error
+				if value, err = op.Merge(existing.Value); err != nil {
+					return nil, i, err
+				}
+			}
+			entry := &MapEntry{Key: op.Key, Value: value, Revision: revision}
+			staged[op.Key], results[i] = entry, entry
+			records = append(records, &journalRecord{Map: m.name, Op: journalPut, Key: op.Key, Value: value, Revision: revision})
+			eventType := EntityInserted
+			if op.Type == MapUpdate {
+				eventType = EntityUpdated
+			}
+			events = append(events, &MapEvent{Type: eventType, Entry: entry})
+		case MapRemove:
+			staged[op.Key], results[i] = nil, existing
+			records = append(records, &journalRecord{Map: m.name, Op: journalRemove, Key: op.Key, Revision: revision})
+			events = append(events, &MapEvent{Type: EntityRemoved, Entry: existing})
+		default:
+			return nil, i, errors.NewInvalid("Unsupported operation %d", op.Type)
+		}
+	}
+
+	if m.backend.persist != nil && len(records) > 0 {
+		if err := m.backend.persist(&journalRecord{Map: m.name, Op: journalBatch, Batch: records}); err != nil {
+			return nil, 0, err
+		}
+	}
+	m.revision = revision
+	for key, entry := range staged {
+		if entry == nil {
+			delete(m.entries, key)
+		} else {
+			m.entries[key] = entry
+		}
+	}
+	for _, event := range events {
+		m.notify(event)
+	}
+	return results, 0, nil
+}
+
+// Returns the existing entry with the given key, checking that it is at the given revision, unless zero
+func (m *memoryMap) check(key string, revision api.Revision) (*MapEntry, error) {
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, errors.NewNotFound("Entry %s not found in map %s", key, m.name)
+	}
+	if revision != 0 && entry.Revision != revision {
+		return nil, errors.NewConflict("Entry %s in map %s is at revision %d; expected %d", key, m.name, entry.Revision, revision)
+	}
+	return entry, nil
+}
+
+// Records the given value under the next revision; must be called with the map lock held
+func (m *memoryMap) put(key string, value []byte, eventType EventType) (*MapEntry, error) {
+	entry := &MapEntry{Key: key, Value: value, Revision: m.revision + 1}
+	if m.backend.persist != nil {
+		if err := m.backend.persist(&journalRecord{Map: m.name, Op: journalPut, Key: key, Value: value, Revision: entry.Revision}); err != nil {
+			return nil, err
+		}
+	}
+	m.revision = entry.Revision
+	m.entries[key] = entry
+	m.notify(&MapEvent{Type: eventType, Entry: entry})
+	return entry, nil
+}
+
+func (m *memoryMap) Get(ctx context.Context, key string) (*MapEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, errors.NewNotFound("Entry %s not found in map %s", key, m.name)
+	}
+	return entry, nil
+}
+
+// List emits the entries in the order of their keys, from a snapshot taken at the start of the listing
+func (m *memoryMap) List(ctx context.Context, emit func(entry *MapEntry) error) error {
+	m.mu.RLock()
+	entries := make([]*MapEntry, 0, len(m.entries))
+	for _, entry := range m.entries {
+		entries = append(entries, entry)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return errors.NewCanceled("List cancelled: %+v", err)
+		}
+		if err := emit(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryMap) Watch(ctx context.Context, ch chan<- *MapEvent) error {
+	w := &memoryWatcher{events: make(chan *MapEvent, memoryWatchBuffer)}
+	m.mu.Lock()
+	m.watchers = append(m.watchers, w)
+	m.mu.Unlock()
+
+	go func() {
+		defer close(ch)
+		defer m.unwatch(w)
+		for {
+			select {
+			case event, ok := <-w.events:
+				if !ok {
+					return
+				}
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Delivers the event to all watchers, dropping any watchers unable to keep up; must be called with the map
+// lock held
+func (m *memoryMap) notify(event *MapEvent) {
+	watchers := m.watchers[:0]
+	for _, w := range m.watchers {
+		select {
+		case w.events <- event:
+			watchers = append(watchers, w)
+		default:
+			log.Warnf("Watcher of map %s fell behind; dropping it", m.name)
+			close(w.events)
+		}
+	}
+	m.watchers = watchers
+}
+
+func (m *memoryMap) unwatch(w *memoryWatcher) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, ww := range m.watchers {
+		if ww == w {
+			m.watchers = append(m.watchers[:i], m.watchers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Close does nothing; the map is shared by all users of the backend
+func (m *memoryMap) Close(ctx context.Context) error {
+	return nil
+}
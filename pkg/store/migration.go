@@ -0,0 +1,287 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// MigrationHook is invoked for each persisted table entry being migrated to a new P4Info, after its table, match
+// fields, actions and action parameters have been carried over by name; anything not found by name in the new
+// P4Info is dropped, and the table ID of the migrated entry is zero if the table itself was not found. The hook
+// receives the original entry, structured according to the previous P4Info, along with the carried over entry,
+// which it may adjust, e.g. to populate newly added fields or to re-encode values of fields whose width changed.
+// It returns the entry to be persisted, or error if the entry cannot be migrated.
+type MigrationHook func(from *p4info.P4Info, to *p4info.P4Info, original *p4api.TableEntry, migrated *p4api.TableEntry) (*p4api.TableEntry, error)
+
+// MigrationFailure describes a persisted table entry which could not be migrated to a new P4Info
+type MigrationFailure struct {
+	// Table is the name of the table of the entry according to the previous P4Info
+	Table string
+	// Entry is the entry as it was persisted according to the previous P4Info
+	Entry *p4api.TableEntry
+	// Err describes the reason the entry could not be migrated
+	Err error
+}
+
+// MigrationReport describes the outcome of migration of an entity store to a new P4Info
+type MigrationReport struct {
+	// From is the fingerprint of the previous P4Info
+	From string
+	// To is the fingerprint of the new P4Info
+	To string
+	// Migrated is the number of table entries carried over to the new P4Info
+	Migrated int
+	// Failures lists the table entries which could not be migrated and have been dropped, because they were rejected
+	// by the migration hook, did not comply with the new P4Info or collided with other migrated entries
+	Failures []*MigrationFailure
+}
+
+// Returns the name of the map used to stash the entries being migrated, allowing an interrupted migration to resume
+func migrationMapName(ns Namespace, id topo.ID) string {
+	return ns.mapName(id, "migration")
+}
+
+// Key of the stash entry marking the stash as complete; the keys of stashed entries always contain the table ID
+const stashCompleteKey = "complete"
+
+// Brings the entities persisted in the given store in line with its P4Info, if the P4Info differs from the one
+// with which they have been persisted; returns nil report if no migration was necessary
+func migrate(ctx context.Context, backend Backend, s *entityStore, hook MigrationHook) (*MigrationReport, error) {
+	from, err := loadSchema(ctx, backend, s.namespace, s.id)
+	if err != nil {
+		return nil, err
+	}
+	stash, err := backend.Map(ctx, migrationMapName(s.namespace, s.id))
+	if err != nil {
+		return nil, err
+	}
+	if from == nil {
+		return nil, saveSchema(ctx, backend, s.namespace, s.id, s.info)
+	}
+	to := s.info
+	if Fingerprint(from) == Fingerprint(to) {
+		// Finish clean-up of any migration interrupted after the new schema has been saved
+		return nil, clearMap(ctx, stash)
+	}
+
+	report := &MigrationReport{From: Fingerprint(from), To: Fingerprint(to)}
+	log.Infof("Migrating store %s from schema %s to %s", s.id, report.From, report.To)
+
+	// Stash the entries to migrate, unless resuming an interrupted migration whose stash has been completed, then
+	// clear the tables; a stash lacking the completion marker may be partial and is therefore started over
+	if _, err = stash.Get(ctx, stashCompleteKey); errors.IsNotFound(err) {
+		if err = clearMap(ctx, stash); err != nil {
+			return nil, err
+		}
+		if err = stashEntries(ctx, backend, s.namespace, s.id, from, stash); err != nil {
+			return nil, err
+		}
+		if _, err = stash.Insert(ctx, stashCompleteKey, []byte{}); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	tables := make([]*p4info.Table, 0, len(from.Tables)+len(to.Tables))
+	for _, t := range append(append(tables, from.Tables...), to.Tables...) {
+		m, err := backend.Map(ctx, tableMapName(s.namespace, s.id, t.Preamble.Id))
+		if err != nil {
+			return nil, err
+		}
+		if err = clearMap(ctx, m); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = stash.List(ctx, func(entry *MapEntry) error {
+		if entry.Key == stashCompleteKey {
+			return nil
+		}
+		original, intent, err := decodeStoredEntry(entry)
+		if err != nil {
+			return err
+		}
+		migrated, err := migrateEntry(from, to, original, hook)
+		if err == nil {
+			update := &p4api.Update{Type: p4api.Update_INSERT, Entity: &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: migrated}}}
+			err = s.Write(ctx, []*p4api.Update{update}, WithIntent(update, intent))
+			if err != nil && !errors.IsAlreadyExists(err) && !errors.IsInvalid(err) {
+				// Failures of the backend are not attributable to the entry; the migration is aborted, keeping the
+				// stash and the previous schema, so that it is resumed the next time the store is opened
+				return err
+			}
+		}
+		if err != nil {
+			report.Failures = append(report.Failures, &MigrationFailure{Table: tableName(from, original.TableId), Entry: original, Err: err})
+			return nil
+		}
+		report.Migrated++
+		return nil
+	}); err != nil {
+		log.Warnf("Store %s: Migration aborted; it will be resumed: %+v", s.id, err)
+		return nil, err
+	}
+
+	if err = saveSchema(ctx, backend, s.namespace, s.id, to); err != nil {
+		return nil, err
+	}
+	for _, failure := range report.Failures {
+		log.Warnf("Store %s: Unable to migrate entry of table %s: %+v", s.id, failure.Table, failure.Err)
+	}
+	log.Infof("Migrated store %s; %d entries migrated, %d dropped", s.id, report.Migrated, len(report.Failures))
+	return report, clearMap(ctx, stash)
+}
+
+// Copies the entries of all tables of the given schema into the stash
+func stashEntries(ctx context.Context, backend Backend, ns Namespace, id topo.ID, from *p4info.P4Info, stash Map) error {
+	for _, t := range from.Tables {
+		m, err := backend.Map(ctx, tableMapName(ns, id, t.Preamble.Id))
+		if err != nil {
+			return err
+		}
+		if err = m.List(ctx, func(entry *MapEntry) error {
+			_, err := stash.Insert(ctx, fmt.Sprintf("%d/%s", t.Preamble.Id, entry.Key), entry.Value)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Removes all entries of the given map
+func clearMap(ctx context.Context, m Map) error {
+	keys := make([]string, 0)
+	if err := m.List(ctx, func(entry *MapEntry) error {
+		keys = append(keys, entry.Key)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if _, err := m.Remove(ctx, key, 0); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Carries the given entry over to the new P4Info and lets the hook adjust it; returns error if the hook fails or if
+// the resulting entry does not comply with the new P4Info
+func migrateEntry(from *p4info.P4Info, to *p4info.P4Info, original *p4api.TableEntry, hook MigrationHook) (*p4api.TableEntry, error) {
+	migrated := carryOver(from, to, original)
+	if hook != nil {
+		var err error
+		if migrated, err = hook(from, to, original, migrated); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateMigrated(from, to, original, migrated); err != nil {
+		return nil, err
+	}
+	return migrated, nil
+}
+
+// Returns a copy of the given entry with its table, match fields, actions and action parameters renumbered by
+// name according to the new P4Info, dropping those not found
+func carryOver(from *p4info.P4Info, to *p4info.P4Info, original *p4api.TableEntry) *p4api.TableEntry {
+	migrated := proto.Clone(original).(*p4api.TableEntry)
+	fromTable := api.FindTableByID(from, original.TableId)
+	var toTable *p4info.Table
+	if fromTable != nil {
+		toTable = p4utils.FindTable(to, fromTable.Preamble.Name)
+	}
+	if toTable == nil {
+		migrated.TableId = 0
+		return migrated
+	}
+	migrated.TableId = toTable.Preamble.Id
+
+	matches := migrated.Match[:0]
+	for _, m := range migrated.Match {
+		if f := api.FindMatchFieldByID(fromTable, m.FieldId); f != nil {
+			if nf := p4utils.FindTableMatchField(toTable, f.Name); nf != nil {
+				m.FieldId = nf.Id
+				matches = append(matches, m)
+			}
+		}
+	}
+	migrated.Match = matches
+
+	switch {
+	case migrated.Action.GetAction() != nil:
+		carryOverAction(from, to, migrated.Action.GetAction())
+	case migrated.Action.GetActionProfileActionSet() != nil:
+		for _, pa := range migrated.Action.GetActionProfileActionSet().ActionProfileActions {
+			carryOverAction(from, to, pa.Action)
+		}
+	}
+	return migrated
+}
+
+func carryOverAction(from *p4info.P4Info, to *p4info.P4Info, action *p4api.Action) {
+	fromAction := api.FindActionByID(from, action.ActionId)
+	var toAction *p4info.Action
+	if fromAction != nil {
+		toAction = p4utils.FindAction(to, fromAction.Preamble.Name)
+	}
+	if toAction == nil {
+		action.ActionId = 0
+		action.Params = nil
+		return
+	}
+	action.ActionId = toAction.Preamble.Id
+	params := action.Params[:0]
+	for _, p := range action.Params {
+		if fp := api.FindParamByID(fromAction, p.ParamId); fp != nil {
+			if np := p4utils.FindActionParam(toAction, fp.Name); np != nil {
+				p.ParamId = np.Id
+				params = append(params, p)
+			}
+		}
+	}
+	action.Params = params
+}
+
+// Validates that the migrated entry complies with the new P4Info. Match fields and action parameters absent from
+// the original entry are required only if they have been newly introduced by the new P4Info.
+func validateMigrated(from *p4info.P4Info, to *p4info.P4Info, original *p4api.TableEntry, migrated *p4api.TableEntry) error {
+	toTable := api.FindTableByID(to, migrated.TableId)
+	if toTable == nil {
+		return errors.NewNotFound("Table %s no longer exists", tableName(from, original.TableId))
+	}
+	for _, action := range entryActions(migrated) {
+		if api.FindActionByID(to, action.ActionId) == nil {
+			return errors.NewNotFound("Action of entry in table %s no longer exists", toTable.Preamble.Name)
+		}
+	}
+
+	fromTable := api.FindTableByID(from, original.TableId)
+	return validateTableEntry(to, migrated, &requirements{
+		matchField: func(table *p4info.Table, field *p4info.MatchField) bool {
+			return fromTable == nil || p4utils.FindTableMatchField(fromTable, field.Name) == nil
+		},
+		actionParam: func(action *p4info.Action, param *p4info.Action_Param) bool {
+			fromAction := p4utils.FindAction(from, action.Preamble.Name)
+			return fromAction == nil || p4utils.FindActionParam(fromAction, param.Name) == nil
+		},
+	})
+}
+
+func tableName(info *p4info.P4Info, id uint32) string {
+	if t := api.FindTableByID(info, id); t != nil {
+		return t.Preamble.Name
+	}
+	return fmt.Sprintf("%d", id)
+}
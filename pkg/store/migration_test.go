@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	testutils "github.com/onosproject/onos-net-lib/pkg/test"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+// Derives a new version of the given P4Info, which renumbers the routing table, its action and the action parameter,
+// removes the bridging table and adds a new exact match field to the MPLS table
+func nextP4Info(info *p4info.P4Info) *p4info.P4Info {
+	next := proto.Clone(info).(*p4info.P4Info)
+	routing := p4utils.FindTable(next, "FabricIngress.forwarding.routing_v4")
+	action := p4utils.FindAction(next, "FabricIngress.forwarding.set_next_id_routing_v4")
+	routing.Preamble.Id = 12345
+	for _, ref := range routing.ActionRefs {
+		if ref.Id == action.Preamble.Id {
+			ref.Id = 54321
+		}
+	}
+	action.Preamble.Id = 54321
+	action.Params[0].Id = 3
+
+	tables := next.Tables[:0]
+	for _, t := range next.Tables {
+		if t.Preamble.Name != "FabricIngress.forwarding.bridging" {
+			tables = append(tables, t)
+		}
+	}
+	next.Tables = tables
+
+	mpls := p4utils.FindTable(next, "FabricIngress.forwarding.mpls")
+	mpls.MatchFields = append(mpls.MatchFields, &p4info.MatchField{Id: 2, Name: "mpls_ttl", Bitwidth: 8,
+		Match: &p4info.MatchField_MatchType_{MatchType: p4info.MatchField_EXACT}})
+	return next
+}
+
+// Populates the store of device foo using the given P4Info with routes, bridging and MPLS entries
+func populateStore(ctx context.Context, t *testing.T, backend Backend, info *p4info.P4Info) {
+	store, err := NewStoreManager(backend, DefaultNamespace).Get(ctx, "foo", info)
+	assert.NoError(t, err)
+	updates := generateRoutes(info, 10, p4api.Update_INSERT)
+	for _, name := range []string{"FabricIngress.forwarding.bridging", "FabricIngress.forwarding.mpls"} {
+		table := p4utils.FindTable(info, name)
+		for i := 1; i <= 2; i++ {
+			entry := testutils.GenerateTableEntry(table, 0, nil)
+			entry.Match[0].GetExact().Value = []byte{byte(i)}
+			updates = append(updates, &p4api.Update{Type: p4api.Update_INSERT, Entity: &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}})
+		}
+	}
+	assert.NoError(t, store.Write(ctx, updates))
+}
+
+func TestMigration(t *testing.T) {
+	ctx := context.Background()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	next := nextP4Info(info)
+	assert.NotEqual(t, Fingerprint(info), Fingerprint(next))
+
+	backend := NewMemoryBackend()
+	populateStore(ctx, t, backend, info)
+
+	// Routes must be carried over by name; bridging entries and MPLS entries lacking the new field are reported
+	stores := NewStoreManager(backend, DefaultNamespace)
+	store, err := stores.Get(ctx, "foo", next)
+	assert.NoError(t, err)
+	report := stores.Migration("foo")
+	assert.Equal(t, Fingerprint(info), report.From)
+	assert.Equal(t, Fingerprint(next), report.To)
+	assert.Equal(t, 10, report.Migrated)
+	assert.Len(t, report.Failures, 4)
+	for _, failure := range report.Failures {
+		switch failure.Table {
+		case "FabricIngress.forwarding.bridging":
+			assert.True(t, errors.IsNotFound(failure.Err))
+		case "FabricIngress.forwarding.mpls":
+			assert.True(t, errors.IsInvalid(failure.Err))
+		default:
+			t.Errorf("unexpected failure: %+v", failure)
+		}
+	}
+
+	entities, err := api.ReadAll(store.Read(ctx, []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{}}}}))
+	assert.NoError(t, err)
+	assert.Len(t, entities, 10)
+	for _, entity := range entities {
+		entry := entity.GetTableEntry()
+		assert.Equal(t, uint32(12345), entry.TableId)
+		assert.Equal(t, uint32(54321), entry.Action.GetAction().ActionId)
+		assert.Equal(t, uint32(3), entry.Action.GetAction().Params[0].ParamId)
+	}
+
+	// Once migrated, the store must not be migrated again
+	stores = NewStoreManager(backend, DefaultNamespace)
+	_, err = stores.Get(ctx, "foo", next)
+	assert.NoError(t, err)
+	assert.Nil(t, stores.Migration("foo"))
+}
+
+func TestMigrationPartialStash(t *testing.T) {
+	ctx := context.Background()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	next := nextP4Info(info)
+
+	backend := NewMemoryBackend()
+	populateStore(ctx, t, backend, info)
+
+	// Simulate a migration interrupted while stashing, having stashed only one of the routes
+	routing := p4utils.FindTable(info, "FabricIngress.forwarding.routing_v4")
+	routes, err := backend.Map(ctx, tableMapName(DefaultNamespace, "foo", routing.Preamble.Id))
+	assert.NoError(t, err)
+	stash, err := backend.Map(ctx, migrationMapName(DefaultNamespace, "foo"))
+	assert.NoError(t, err)
+	stashed := false
+	assert.NoError(t, routes.List(ctx, func(entry *MapEntry) error {
+		if !stashed {
+			_, err := stash.Insert(ctx, fmt.Sprintf("%d/%s", routing.Preamble.Id, entry.Key), entry.Value)
+			stashed = true
+			return err
+		}
+		return nil
+	}))
+
+	// The partial stash must be discarded and all the routes migrated
+	stores := NewStoreManager(backend, DefaultNamespace)
+	store, err := stores.Get(ctx, "foo", next)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, stores.Migration("foo").Migrated)
+	readEntries(ctx, t, store, []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{TableId: 12345}}}}, 10)
+	_, err = stash.Get(ctx, stashCompleteKey)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+// Backend whose map of the given name fails insertions while any failures remain
+type failingBackend struct {
+	Backend
+	name     string
+	failures int
+}
+
+func (b *failingBackend) Map(ctx context.Context, name string) (Map, error) {
+	m, err := b.Backend.Map(ctx, name)
+	if err != nil || name != b.name {
+		return m, err
+	}
+	return &failingMap{Map: m, backend: b}, nil
+}
+
+type failingMap struct {
+	Map
+	backend *failingBackend
+}
+
+func (m *failingMap) Insert(ctx context.Context, key string, value []byte) (*MapEntry, error) {
+	if m.backend.failures > 0 {
+		m.backend.failures--
+		return nil, errors.NewUnavailable("Backend unavailable")
+	}
+	return m.Map.Insert(ctx, key, value)
+}
+
+func TestMigrationBackendFailure(t *testing.T) {
+	ctx := context.Background()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	next := nextP4Info(info)
+
+	backend := NewMemoryBackend()
+	populateStore(ctx, t, backend, info)
+
+	// Backend failures must abort the migration rather than drop the entries, keeping the previous schema
+	failing := &failingBackend{Backend: backend, name: tableMapName(DefaultNamespace, "foo", 12345), failures: 1}
+	_, err = NewStoreManager(failing, DefaultNamespace).Get(ctx, "foo", next)
+	assert.True(t, errors.IsUnavailable(err))
+	schema, err := loadSchema(ctx, backend, DefaultNamespace, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, Fingerprint(info), Fingerprint(schema))
+
+	// The migration must be resumed from the stash, carrying over all the routes
+	stores := NewStoreManager(backend, DefaultNamespace)
+	store, err := stores.Get(ctx, "foo", next)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, stores.Migration("foo").Migrated)
+	assert.Len(t, stores.Migration("foo").Failures, 4)
+	readEntries(ctx, t, store, []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{TableId: 12345}}}}, 10)
+}
+
+func TestMigrationHook(t *testing.T) {
+	ctx := context.Background()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	next := nextP4Info(info)
+
+	backend := NewMemoryBackend()
+	populateStore(ctx, t, backend, info)
+
+	// Populate the new MPLS match field from the label
+	mpls := p4utils.FindTable(next, "FabricIngress.forwarding.mpls")
+	hook := func(from *p4info.P4Info, to *p4info.P4Info, original *p4api.TableEntry, migrated *p4api.TableEntry) (*p4api.TableEntry, error) {
+		if migrated.TableId == mpls.Preamble.Id {
+			migrated.Match = append(migrated.Match, &p4api.FieldMatch{FieldId: 2,
+				FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: migrated.Match[0].GetExact().Value}}})
+		}
+		return migrated, nil
+	}
+	stores := NewStoreManager(backend, DefaultNamespace, WithMigrationHook(hook))
+	store, err := stores.Get(ctx, "foo", next)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, stores.Migration("foo").Migrated)
+	assert.Len(t, stores.Migration("foo").Failures, 2)
+	readEntries(ctx, t, store, []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{TableId: mpls.Preamble.Id}}}}, 2)
+}
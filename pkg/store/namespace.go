@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"sort"
+	"strings"
+)
+
+// namespacesMapName is the name of the map registering all namespaces in which entity stores have been created
+const namespacesMapName = "control/namespaces"
+
+// defaultRoleName is the role name used for the P4Runtime default role, which has no name
+const defaultRoleName = "default"
+
+// Namespace scopes the entity stores of an application controlling devices using a particular P4Runtime role;
+// entity stores of different namespaces are kept apart, even if they are for the same device
+type Namespace struct {
+	App  string `json:"app"`
+	Role string `json:"role"`
+}
+
+// DefaultNamespace is the namespace of entity stores created without specifying one
+var DefaultNamespace = Namespace{App: "default", Role: defaultRoleName}
+
+// NewNamespace returns the namespace of the given application using the specified P4Runtime role; nil role
+// stands for the default role
+func NewNamespace(app string, role *p4api.Role) Namespace {
+	ns := Namespace{App: app, Role: role.GetName()}
+	if ns.Role == "" {
+		ns.Role = defaultRoleName
+	}
+	return ns
+}
+
+// WithNamespace sets the namespace of the entity store, keeping it apart from the stores of the same device in other
+// namespaces
+func WithNamespace(ns Namespace) Option {
+	return func(s *entityStore) {
+		s.namespace = ns
+	}
+}
+
+// String returns the namespace in the form of app/role
+func (ns Namespace) String() string {
+	return ns.App + "/" + ns.Role
+}
+
+// Validates that the namespace is fully specified and that its components cannot be confused with each other
+func (ns Namespace) validate() error {
+	if ns.App == "" || ns.Role == "" {
+		return errors.NewInvalid("Namespace %s must have both application and role", ns)
+	}
+	if strings.Contains(ns.App, "/") || strings.Contains(ns.Role, "/") {
+		return errors.NewInvalid("Namespace %s components must not contain '/'", ns)
+	}
+	return nil
+}
+
+// Returns the name of the map of the given kind belonging to the entity store of the specified device
+func (ns Namespace) mapName(id topo.ID, kind string) string {
+	return fmt.Sprintf("control/%s/%s/%s/%s", ns.App, ns.Role, id, kind)
+}
+
+// Records the given namespace in the registry of namespaces
+func registerNamespace(ctx context.Context, backend Backend, ns Namespace) error {
+	m, err := backend.Map(ctx, namespacesMapName)
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(ns)
+	if err != nil {
+		return errors.NewInvalid("Unable to encode namespace %s: %+v", ns, err)
+	}
+	if _, err = m.Insert(ctx, ns.String(), value); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// Returns all namespaces recorded in the registry of namespaces, ordered by application and role
+func listNamespaces(ctx context.Context, backend Backend) ([]Namespace, error) {
+	m, err := backend.Map(ctx, namespacesMapName)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make([]Namespace, 0)
+	if err = m.List(ctx, func(entry *MapEntry) error {
+		ns := Namespace{}
+		if err := json.Unmarshal(entry.Value, &ns); err != nil {
+			return errors.NewInternal("Unable to decode namespace %s: %+v", entry.Key, err)
+		}
+		namespaces = append(namespaces, ns)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].String() < namespaces[j].String() })
+	return namespaces, nil
+}
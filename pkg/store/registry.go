@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"sort"
+	"time"
+)
+
+// StoreRecord describes a device entity store registered in a namespace
+type StoreRecord struct {
+	// ID is the ID of the device
+	ID topo.ID `json:"id"`
+	// Fingerprint is the fingerprint of the P4Info with which the entities are persisted
+	Fingerprint string `json:"fingerprint"`
+	// Created is the time the store was first created
+	Created time.Time `json:"created"`
+	// MissingSince is the time since which the device has been continuously found not to exist by garbage
+	// collection; zero if the device has not been found missing
+	MissingSince time.Time `json:"missingSince,omitempty"`
+}
+
+// Returns the name of the map registering the device entity stores of the namespace
+func (ns Namespace) registryMapName() string {
+	return fmt.Sprintf("control/%s/%s/stores", ns.App, ns.Role)
+}
+
+// Records the entity store of the given device in the registry of the namespace, or refreshes its record
+func registerStore(ctx context.Context, backend Backend, ns Namespace, id topo.ID, fingerprint string) error {
+	return updateStoreRecord(ctx, backend, ns, id, func(record *StoreRecord) {
+		record.Fingerprint = fingerprint
+		record.MissingSince = time.Time{}
+	})
+}
+
+// Applies the given change to the record of the entity store of the specified device, creating the record first if
+// it does not exist yet
+func updateStoreRecord(ctx context.Context, backend Backend, ns Namespace, id topo.ID, change func(record *StoreRecord)) error {
+	m, err := backend.Map(ctx, ns.registryMapName())
+	if err != nil {
+		return err
+	}
+	for {
+		entry, err := m.Get(ctx, string(id))
+		record := &StoreRecord{ID: id, Created: time.Now()}
+		if err == nil {
+			if err = json.Unmarshal(entry.Value, record); err != nil {
+				return errors.NewInternal("Unable to decode record of store %s: %+v", id, err)
+			}
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+		change(record)
+		value, err := json.Marshal(record)
+		if err != nil {
+			return errors.NewInvalid("Unable to encode record of store %s: %+v", id, err)
+		}
+		if entry == nil {
+			_, err = m.Insert(ctx, string(id), value)
+		} else {
+			_, err = m.Update(ctx, string(id), value, entry.Revision)
+		}
+
+		// Retry if the record has been changed concurrently
+		if !errors.IsAlreadyExists(err) && !errors.IsConflict(err) && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+}
+
+// Removes the record of the entity store of the given device from the registry of the namespace
+func unregisterStore(ctx context.Context, backend Backend, ns Namespace, id topo.ID) error {
+	m, err := backend.Map(ctx, ns.registryMapName())
+	if err != nil {
+		return err
+	}
+	if _, err = m.Remove(ctx, string(id), 0); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Returns the records of all entity stores registered in the namespace, ordered by device ID
+func listStores(ctx context.Context, backend Backend, ns Namespace) ([]*StoreRecord, error) {
+	m, err := backend.Map(ctx, ns.registryMapName())
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*StoreRecord, 0)
+	if err = m.List(ctx, func(entry *MapEntry) error {
+		record := &StoreRecord{}
+		if err := json.Unmarshal(entry.Value, record); err != nil {
+			return errors.NewInternal("Unable to decode record of store %s: %+v", entry.Key, err)
+		}
+		records = append(records, record)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records, nil
+}
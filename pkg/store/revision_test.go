@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// Reads the single persisted entry along with its revision
+func readRevision(ctx context.Context, t *testing.T, store EntityStore) api.Revision {
+	it := store.Read(ctx, []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{}}}})
+	defer it.Close()
+	assert.True(t, it.Next())
+	assert.NotZero(t, it.Revision())
+	return it.Revision()
+}
+
+func TestConditionalWrite(t *testing.T) {
+	forEachBackend(t, testConditionalWrite)
+}
+
+func testConditionalWrite(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	replica1, err := NewEntityStore(ctx, backend, "foo", info)
+	assert.NoError(t, err)
+	replica2, err := NewEntityStore(ctx, backend, "foo", info, WithCache())
+	assert.NoError(t, err)
+
+	assert.NoError(t, replica1.Write(ctx, generateRoutes(info, 1, p4api.Update_INSERT)))
+	rev1 := readRevision(ctx, t, replica1)
+
+	// Inserts cannot be conditional
+	insert := generateRoutes(info, 1, p4api.Update_INSERT)
+	assert.True(t, errors.IsInvalid(replica1.Write(ctx, insert, IfRevision(insert[0], rev1))))
+
+	// Both replicas observed the same revision; only the first one to write it wins
+	modify1 := generateRoutes(info, 1, p4api.Update_MODIFY)
+	assert.NoError(t, replica1.Write(ctx, modify1, IfRevision(modify1[0], rev1)))
+	modify2 := generateRoutes(info, 1, p4api.Update_MODIFY)
+	assert.True(t, errors.IsConflict(replica2.Write(ctx, modify2, IfRevision(modify2[0], rev1))))
+
+	// Having read the current revision, the second replica can proceed
+	rev2 := readRevision(ctx, t, replica1)
+	assert.Greater(t, rev2, rev1)
+	assert.NoError(t, replica2.Write(ctx, modify2, IfRevision(modify2[0], rev2)))
+
+	// Stale deletes are rejected as well
+	remove := generateRoutes(info, 1, p4api.Update_DELETE)
+	assert.True(t, errors.IsConflict(replica1.Write(ctx, remove, IfRevision(remove[0], rev2))))
+	assert.NoError(t, replica1.Write(ctx, remove, IfRevision(remove[0], readRevision(ctx, t, replica1))))
+	readEntries(ctx, t, replica1, []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{}}}}, 0)
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// schemaKey is the key under which the P4Info used to structure the entity store is persisted
+const schemaKey = "p4info"
+
+// Fingerprint returns a fingerprint of the parts of the given P4Info which determine the structure of the entity
+// store and the meaning of the persisted entities, i.e. the tables, actions and types
+func Fingerprint(info *p4info.P4Info) string {
+	schema := &p4info.P4Info{Tables: info.Tables, Actions: info.Actions, TypeInfo: info.TypeInfo}
+	bytes, _ := proto.MarshalOptions{Deterministic: true}.Marshal(schema)
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:])
+}
+
+// Returns the name of the map holding the entries of the given table of the specified device
+func tableMapName(ns Namespace, id topo.ID, tableID uint32) string {
+	return ns.mapName(id, fmt.Sprintf("table-%d", tableID))
+}
+
+// Returns the name of the map holding the schema of the entity store of the specified device
+func schemaMapName(ns Namespace, id topo.ID) string {
+	return ns.mapName(id, "schema")
+}
+
+// Loads the P4Info persisted as the schema of the entity store of the specified device; returns nil if none
+func loadSchema(ctx context.Context, backend Backend, ns Namespace, id topo.ID) (*p4info.P4Info, error) {
+	m, err := backend.Map(ctx, schemaMapName(ns, id))
+	if err != nil {
+		return nil, err
+	}
+	entry, err := m.Get(ctx, schemaKey)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	info := &p4info.P4Info{}
+	if err = proto.Unmarshal(entry.Value, info); err != nil {
+		return nil, errors.NewInternal("Unable to decode schema of store %s: %+v", id, err)
+	}
+	return info, nil
+}
+
+// Persists the given P4Info as the schema of the entity store of the specified device
+func saveSchema(ctx context.Context, backend Backend, ns Namespace, id topo.ID, info *p4info.P4Info) error {
+	m, err := backend.Map(ctx, schemaMapName(ns, id))
+	if err != nil {
+		return err
+	}
+	value, err := proto.Marshal(info)
+	if err != nil {
+		return errors.NewInvalid("Unable to encode schema of store %s: %+v", id, err)
+	}
+	if _, err = m.Update(ctx, schemaKey, value, 0); errors.IsNotFound(err) {
+		_, err = m.Insert(ctx, schemaKey, value)
+	}
+	return err
+}
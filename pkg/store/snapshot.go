@@ -0,0 +1,296 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"io"
+	"sort"
+)
+
+// SnapshotVersion is the version of the snapshot format produced by Export. Version 2 records the intent of each
+// table entry alongside it; snapshots of version 1 carry bare entities, leaving their intents unknown.
+const SnapshotVersion = 2
+
+// Maximum length of a single line of a snapshot
+const maxSnapshotLineSize = 16 * 1024 * 1024
+
+// SnapshotHeader is the first line of a snapshot, describing the entities which follow it
+type SnapshotHeader struct {
+	// Version is the version of the snapshot format
+	Version int `json:"version"`
+	// Device is the ID of the device whose entities have been exported
+	Device topo.ID `json:"device"`
+	// Fingerprint is the fingerprint of the P4Info the entities comply with
+	Fingerprint string `json:"fingerprint"`
+}
+
+// ImportMode determines how imported entities are combined with those already persisted in the store
+type ImportMode int
+
+const (
+	// ImportMerge inserts or modifies the imported entities, leaving any other persisted entities intact
+	ImportMerge ImportMode = iota
+	// ImportReplace inserts or modifies the imported entities and deletes any other persisted entities
+	ImportReplace
+)
+
+// Line of a snapshot of version 2, holding an entity in the protobuf JSON format and the intent it belongs to
+type snapshotLine struct {
+	Entity json.RawMessage `json:"entity"`
+	Intent *api.Intent     `json:"intent,omitempty"`
+}
+
+// AllEntitiesQuery returns a query matching persisted entities of every kind
+func AllEntitiesQuery() []*p4api.Entity {
+	return []*p4api.Entity{
+		{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{}}},
+		{Entity: &p4api.Entity_CounterEntry{CounterEntry: &p4api.CounterEntry{}}},
+		{Entity: &p4api.Entity_DirectCounterEntry{DirectCounterEntry: &p4api.DirectCounterEntry{}}},
+		{Entity: &p4api.Entity_MeterEntry{MeterEntry: &p4api.MeterEntry{}}},
+		{Entity: &p4api.Entity_DirectMeterEntry{DirectMeterEntry: &p4api.DirectMeterEntry{}}},
+		{Entity: &p4api.Entity_ActionProfileMember{ActionProfileMember: &p4api.ActionProfileMember{}}},
+		{Entity: &p4api.Entity_ActionProfileGroup{ActionProfileGroup: &p4api.ActionProfileGroup{}}},
+		{Entity: &p4api.Entity_PacketReplicationEngineEntry{PacketReplicationEngineEntry: &p4api.PacketReplicationEngineEntry{
+			Type: &p4api.PacketReplicationEngineEntry_MulticastGroupEntry{MulticastGroupEntry: &p4api.MulticastGroupEntry{}}}}},
+		{Entity: &p4api.Entity_PacketReplicationEngineEntry{PacketReplicationEngineEntry: &p4api.PacketReplicationEngineEntry{
+			Type: &p4api.PacketReplicationEngineEntry_CloneSessionEntry{CloneSessionEntry: &p4api.CloneSessionEntry{}}}}},
+	}
+}
+
+// Export writes a snapshot of all persisted entities to the given writer. The snapshot is a JSON lines document,
+// whose first line is the snapshot header, followed by one line per entity, holding the entity in the protobuf JSON
+// format along with the intent to which it belongs, if any. Snapshots are limited to table entries for now; Export
+// fails with a NotSupported error if entities of any other kind are persisted.
+func (s *entityStore) Export(ctx context.Context, w io.Writer) error {
+	entities, intents, err := s.readSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	if err = s.encodeSnapshot(w, entities, intents); err != nil {
+		return err
+	}
+	log.Infof("Device %s: Exported %d entities", s.id, len(entities))
+	return nil
+}
+
+// Reads all persisted entities along with the intents to which they belong; the intent of an entity is nil if it
+// belongs to no intent. Fails with a NotSupported error if any of the entities is other than a table entry.
+func (s *entityStore) readSnapshot(ctx context.Context) ([]*p4api.Entity, []*api.Intent, error) {
+	ids := make([]uint32, 0, len(s.tables))
+	for id := range s.tables {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	entities := make([]*p4api.Entity, 0)
+	intents := make([]*api.Intent, 0)
+	for _, id := range ids {
+		if err := s.listTableIntents(ctx, s.tables[id], func(entry *p4api.TableEntry, intent *api.Intent, revision api.Revision) error {
+			entities = append(entities, &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}})
+			intents = append(intents, intent)
+			return nil
+		}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	others, err := api.ReadAll(s.Read(ctx, AllEntitiesQuery()[1:]))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(others) > 0 {
+		return nil, nil, errors.NewNotSupported("Device %s has %d persisted entities other than table entries; only table entries are supported",
+			s.id, len(others))
+	}
+	return entities, intents, nil
+}
+
+// Writes a snapshot of the given entities and their intents to the writer
+func (s *entityStore) encodeSnapshot(w io.Writer, entities []*p4api.Entity, intents []*api.Intent) error {
+	bw := bufio.NewWriter(w)
+	header, err := json.Marshal(&SnapshotHeader{Version: SnapshotVersion, Device: s.id, Fingerprint: Fingerprint(s.info)})
+	if err != nil {
+		return errors.NewInternal("Unable to encode snapshot header: %+v", err)
+	}
+	if err = writeSnapshotLine(bw, header); err != nil {
+		return err
+	}
+	for i, entity := range entities {
+		value, err := protojson.Marshal(entity)
+		if err != nil {
+			return errors.NewInternal("Unable to encode entity: %+v", err)
+		}
+		line, err := json.Marshal(&snapshotLine{Entity: value, Intent: intents[i]})
+		if err != nil {
+			return errors.NewInternal("Unable to encode entity: %+v", err)
+		}
+		if err = writeSnapshotLine(bw, line); err != nil {
+			return err
+		}
+	}
+	if err = bw.Flush(); err != nil {
+		return errors.NewUnavailable("Unable to write snapshot: %+v", err)
+	}
+	return nil
+}
+
+func writeSnapshotLine(w *bufio.Writer, line []byte) error {
+	if _, err := w.Write(line); err != nil {
+		return errors.NewUnavailable("Unable to write snapshot: %+v", err)
+	}
+	if err := w.WriteByte('\n'); err != nil {
+		return errors.NewUnavailable("Unable to write snapshot: %+v", err)
+	}
+	return nil
+}
+
+// Import reads a snapshot produced by Export from the given reader and persists its entities, combining them with
+// the already persisted entities according to the given mode. The snapshot must be of a supported version and must
+// have been exported using the same P4Info as the one of the store; the snapshot is validated in its entirety before
+// any of its entities are written. Snapshots holding entities other than table entries are rejected with a
+// NotSupported error. Imported table entries belong to the intents recorded in the snapshot; those of snapshots of
+// version 1 retain the intents of the persisted entries they modify.
+func (s *entityStore) Import(ctx context.Context, r io.Reader, mode ImportMode) error {
+	header, entities, intents, err := s.decodeSnapshot(r)
+	if err != nil {
+		return err
+	}
+	updates, err := s.importUpdates(ctx, entities, mode)
+	if err != nil {
+		return err
+	}
+	opts := make([]WriteOption, 0, len(entities))
+	if intents != nil {
+		// The leading updates persist the imported entries in the order of the snapshot
+		for i := range entities {
+			opts = append(opts, WithIntent(updates[i], intents[i]))
+		}
+	}
+	if err = s.Write(ctx, updates, opts...); err != nil {
+		return err
+	}
+	log.Infof("Device %s: Imported %d entities from snapshot of device %s", s.id, len(entities), header.Device)
+	return nil
+}
+
+// Reads a snapshot from the given reader, validating that it is of a supported version and that it complies with
+// the P4Info of the store; returns the entities along with their intents, which are nil if the snapshot does not
+// record them
+func (s *entityStore) decodeSnapshot(r io.Reader) (*SnapshotHeader, []*p4api.Entity, []*api.Intent, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSnapshotLineSize)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, nil, nil, errors.NewInvalid("Unable to read snapshot: %+v", err)
+		}
+		return nil, nil, nil, errors.NewInvalid("Snapshot is empty")
+	}
+	header := &SnapshotHeader{}
+	if err := json.Unmarshal(scanner.Bytes(), header); err != nil {
+		return nil, nil, nil, errors.NewInvalid("Unable to decode snapshot header: %+v", err)
+	}
+	if header.Version != 1 && header.Version != SnapshotVersion {
+		return nil, nil, nil, errors.NewInvalid("Unsupported snapshot version %d", header.Version)
+	}
+	if fingerprint := Fingerprint(s.info); header.Fingerprint != fingerprint {
+		return nil, nil, nil, errors.NewInvalid("Snapshot of device %s has P4Info fingerprint %s; expected %s", header.Device, header.Fingerprint, fingerprint)
+	}
+
+	entities := make([]*p4api.Entity, 0)
+	var intents []*api.Intent
+	if header.Version == SnapshotVersion {
+		intents = make([]*api.Intent, 0)
+	}
+	for line := 2; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		value := scanner.Bytes()
+		if intents != nil {
+			sl := &snapshotLine{}
+			if err := json.Unmarshal(value, sl); err != nil {
+				return nil, nil, nil, errors.NewInvalid("Unable to decode line %d of snapshot: %+v", line, err)
+			}
+			if sl.Intent != nil && sl.Intent.ID == "" {
+				return nil, nil, nil, errors.NewInvalid("Intent ID on line %d of snapshot must not be empty", line)
+			}
+			value = sl.Entity
+			intents = append(intents, sl.Intent)
+		}
+		entity := &p4api.Entity{}
+		if err := protojson.Unmarshal(value, entity); err != nil {
+			return nil, nil, nil, errors.NewInvalid("Unable to decode entity on line %d of snapshot: %+v", line, err)
+		}
+		if entity.GetTableEntry() == nil {
+			return nil, nil, nil, errors.NewNotSupported("Entity on line %d of snapshot is not a table entry; only table entries are supported", line)
+		}
+		entities = append(entities, entity)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, nil, errors.NewInvalid("Unable to read snapshot: %+v", err)
+	}
+	return header, entities, intents, nil
+}
+
+// Produces the updates which persist the given imported entities according to the import mode
+func (s *entityStore) importUpdates(ctx context.Context, entities []*p4api.Entity, mode ImportMode) ([]*p4api.Update, error) {
+	persisted, err := api.ReadAll(s.Read(ctx, []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{}}}}))
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[uint32]map[string]*p4api.Entity)
+	for _, entity := range persisted {
+		t, key, err := s.findTableAndKey(entity.GetTableEntry())
+		if err != nil {
+			return nil, err
+		}
+		if existing[t.info.Preamble.Id] == nil {
+			existing[t.info.Preamble.Id] = make(map[string]*p4api.Entity)
+		}
+		existing[t.info.Preamble.Id][key] = entity
+	}
+
+	updates := make([]*p4api.Update, 0, len(entities))
+	imported := make(map[uint32]map[string]bool)
+	for _, entity := range entities {
+		t, key, err := s.findTableAndKey(entity.GetTableEntry())
+		if err != nil {
+			return nil, err
+		}
+		id := t.info.Preamble.Id
+		if imported[id] == nil {
+			imported[id] = make(map[string]bool)
+		}
+		if imported[id][key] {
+			return nil, errors.NewInvalid("Snapshot contains duplicate entry of table %s", t.info.Preamble.Name)
+		}
+		imported[id][key] = true
+
+		updateType := p4api.Update_INSERT
+		if _, ok := existing[id][key]; ok {
+			updateType = p4api.Update_MODIFY
+		}
+		updates = append(updates, &p4api.Update{Type: updateType, Entity: entity})
+	}
+
+	if mode == ImportReplace {
+		for id, entries := range existing {
+			for key, entity := range entries {
+				if !imported[id][key] {
+					updates = append(updates, &p4api.Update{Type: p4api.Update_DELETE, Entity: entity})
+				}
+			}
+		}
+	}
+	return updates, nil
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"strings"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.TODO()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	backend := NewMemoryBackend()
+	query := []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{}}}}
+
+	golden, err := NewEntityStore(ctx, backend, "golden", info)
+	assert.NoError(t, err)
+	routes := generateRoutes(info, 10, p4api.Update_INSERT)
+	host := &api.Intent{ID: "host1", Labels: map[string]string{"tenant": "blue"}}
+	assert.NoError(t, golden.Write(ctx, routes, withIntents(routes[:5], host)...))
+
+	snapshot := &bytes.Buffer{}
+	assert.NoError(t, golden.Export(ctx, snapshot))
+	lines := strings.Split(strings.TrimSpace(snapshot.String()), "\n")
+	assert.Len(t, lines, 11)
+	assert.Contains(t, lines[0], Fingerprint(info))
+
+	// Replacement must remove entries absent from the snapshot and modify the others
+	replacement, err := NewEntityStore(ctx, backend, "replacement", info)
+	assert.NoError(t, err)
+	updates := generateRoutes(info, 15, p4api.Update_INSERT)
+	for _, update := range updates {
+		update.Entity.GetTableEntry().Action.GetAction().Params[0].Value = []byte{0xff}
+	}
+	stale := &api.Intent{ID: "stale"}
+	assert.NoError(t, replacement.Write(ctx, updates, withIntents(updates, stale)...))
+	assert.NoError(t, replacement.Import(ctx, bytes.NewReader(snapshot.Bytes()), ImportReplace))
+	entities := readEntries(ctx, t, replacement, query, 10)
+	for _, entity := range entities {
+		assert.NotEqual(t, []byte{0xff}, entity.GetTableEntry().Action.GetAction().Params[0].Value)
+	}
+
+	// Imported entries must belong to the intents recorded in the snapshot
+	readEntities(ctx, t, replacement.ReadByIntent(ctx, "stale"), 0)
+	for _, entity := range readEntities(ctx, t, replacement.ReadByIntent(ctx, "host1"), 5) {
+		intent, err := replacement.IntentOf(ctx, entity)
+		assert.NoError(t, err)
+		assert.Equal(t, host, intent)
+	}
+
+	// Merging must leave entries absent from the snapshot intact
+	merged, err := NewEntityStore(ctx, backend, "merged", info)
+	assert.NoError(t, err)
+	assert.NoError(t, merged.Write(ctx, updates))
+	assert.NoError(t, merged.Import(ctx, bytes.NewReader(snapshot.Bytes()), ImportMerge))
+	entities = readEntries(ctx, t, merged, query, 15)
+	modified := 0
+	for _, entity := range entities {
+		if !bytes.Equal([]byte{0xff}, entity.GetTableEntry().Action.GetAction().Params[0].Value) {
+			modified++
+		}
+	}
+	assert.Equal(t, 10, modified)
+	readEntities(ctx, t, merged.ReadByIntent(ctx, "host1"), 5)
+
+	// Snapshots of version 1 carry no intents; the imported entries retain the intents of those they modify
+	legacy := strings.Replace(lines[0], `"version":2`, `"version":1`, 1) + "\n"
+	for _, line := range lines[1:] {
+		sl := &snapshotLine{}
+		assert.NoError(t, json.Unmarshal([]byte(line), sl))
+		legacy += string(sl.Entity) + "\n"
+	}
+	modifies := generateRoutes(info, 5, p4api.Update_MODIFY)
+	assert.NoError(t, replacement.Write(ctx, modifies, withIntents(modifies, stale)...))
+	assert.NoError(t, replacement.Import(ctx, strings.NewReader(legacy), ImportReplace))
+	readEntities(ctx, t, replacement.ReadByIntent(ctx, "stale"), 5)
+	readEntities(ctx, t, replacement.ReadByIntent(ctx, "host1"), 0)
+
+	// Snapshots of other versions or P4Info must be rejected without any changes
+	other, err := NewEntityStore(ctx, backend, "other", nextP4Info(info))
+	assert.NoError(t, err)
+	err = other.Import(ctx, bytes.NewReader(snapshot.Bytes()), ImportMerge)
+	assert.True(t, errors.IsInvalid(err))
+	readEntries(ctx, t, other, query, 0)
+
+	versioned := strings.Replace(snapshot.String(), `"version":2`, `"version":99`, 1)
+	err = replacement.Import(ctx, strings.NewReader(versioned), ImportMerge)
+	assert.True(t, errors.IsInvalid(err))
+
+	duplicated := snapshot.String() + lines[1] + "\n"
+	err = replacement.Import(ctx, strings.NewReader(duplicated), ImportMerge)
+	assert.True(t, errors.IsInvalid(err))
+
+	err = replacement.Import(ctx, strings.NewReader(""), ImportMerge)
+	assert.True(t, errors.IsInvalid(err))
+
+	// Entities other than table entries are not persisted by the store and cannot be imported
+	group, err := protojson.Marshal(&p4api.Entity{Entity: &p4api.Entity_PacketReplicationEngineEntry{PacketReplicationEngineEntry: &p4api.PacketReplicationEngineEntry{
+		Type: &p4api.PacketReplicationEngineEntry_MulticastGroupEntry{MulticastGroupEntry: &p4api.MulticastGroupEntry{MulticastGroupId: 1}}}}})
+	assert.NoError(t, err)
+	unsupported := lines[0] + "\n" + `{"entity":` + string(group) + "}\n"
+	err = replacement.Import(ctx, strings.NewReader(unsupported), ImportMerge)
+	assert.True(t, errors.IsNotSupported(err))
+}
+
+// Returns the write options associating each of the given updates with the intent
+func withIntents(updates []*p4api.Update, intent *api.Intent) []WriteOption {
+	opts := make([]WriteOption, 0, len(updates))
+	for _, update := range updates {
+		opts = append(opts, WithIntent(update, intent))
+	}
+	return opts
+}
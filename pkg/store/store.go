@@ -6,11 +6,8 @@ package store
 
 import (
 	"context"
-	"fmt"
-	"github.com/atomix/go-sdk/pkg/generic"
-	"github.com/atomix/go-sdk/pkg/primitive"
-	_map "github.com/atomix/go-sdk/pkg/primitive/map"
 	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-control/pkg/api"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
 	p4api "github.com/p4lang/p4runtime/go/p4/v1"
@@ -26,38 +23,133 @@ type EntityStore interface {
 	// P4Info returns the P4Info used to structure the store and validate entries
 	P4Info() *p4info.P4Info
 
-	// Read accepts a query in form of a list of partially populated entities and returns any
-	// matching entities on the specified channel
-	Read(ctx context.Context, query []*p4api.Entity, ch chan<- *p4api.Entity) []error
+	// Read accepts a query in form of a list of partially populated entities and returns an iterator
+	// over any matching entities; errors are reported by the iterator for each of the queries. Table entry
+	// queries specifying field matches, or the default entry, yield only the entry with the same key, if any.
+	Read(ctx context.Context, query []*p4api.Entity) api.EntityIterator
 
-	// Write persists the specified list of updates.
-	Write(ctx context.Context, updates []*p4api.Update) error
+	// Write persists the specified list of updates; updates may be made conditional on the revision of the
+	// persisted entity using the IfRevision option and may associate the entities with an intent using the
+	// WithIntent option. The table entry updates of each table take effect atomically if the backend supports
+	// batches of map operations; otherwise, a failed write may leave some of its updates applied.
+	Write(ctx context.Context, updates []*p4api.Update, opts ...WriteOption) error
+
+	// ReadByIntent returns an iterator over all persisted entities belonging to the given intent, as associated with
+	// the entities when written using the WithIntent option
+	ReadByIntent(ctx context.Context, id string) api.EntityIterator
+
+	// ReadByAction returns an iterator over all table entries using the given action
+	ReadByAction(ctx context.Context, actionID uint32) api.EntityIterator
+
+	// ReadByActionParam returns an iterator over all table entries using the given action with the specified
+	// parameter set to the given value, e.g. all routes pointing at a particular next hop
+	ReadByActionParam(ctx context.Context, actionID uint32, paramID uint32, value []byte) api.EntityIterator
+
+	// ReadByMatchField returns an iterator over all entries of the given table whose specified match field has the
+	// given value
+	ReadByMatchField(ctx context.Context, tableID uint32, fieldID uint32, value []byte) api.EntityIterator
+
+	// ReadByLabel returns an iterator over all table entries belonging to intents having the given label with the
+	// specified value
+	ReadByLabel(ctx context.Context, label string, value string) api.EntityIterator
+
+	// IntentOf returns the intent to which the given persisted entity belongs; nil if it belongs to no intent
+	IntentOf(ctx context.Context, entity *p4api.Entity) (*api.Intent, error)
+
+	// Watch streams events describing changes of the persisted entities matching the given filter, made by any
+	// client of the store, until the context is cancelled; the channel is closed afterwards. Only table entries can
+	// be watched.
+	Watch(ctx context.Context, filter []*p4api.Entity, ch chan<- *Event) error
+
+	// CacheStats returns the statistics of the entity store cache, or nil if the cache is not enabled
+	CacheStats() *CacheStats
+
+	// Export writes a versioned snapshot of all persisted entities and their intents, tagged with the P4Info
+	// fingerprint; fails with a NotSupported error if entities other than table entries are persisted
+	Export(ctx context.Context, w io.Writer) error
+
+	// Import persists the entities of a snapshot produced by Export, after validating it against the P4Info of the
+	// store; the imported entities are either merged with the persisted ones, or replace them altogether
+	Import(ctx context.Context, r io.Reader, mode ImportMode) error
+
+	// Checkpoint captures all persisted entities under the given name, subject to the limit of the total size of
+	// checkpoints of the store; fails with a NotSupported error if entities other than table entries are persisted
+	Checkpoint(ctx context.Context, name string) (*api.Checkpoint, error)
+
+	// Checkpoints returns descriptors of all checkpoints of the store, ordered by their creation time
+	Checkpoints(ctx context.Context) ([]*api.Checkpoint, error)
+
+	// CheckpointEntities returns the entities captured by the given checkpoint along with the intents to which they
+	// belong; the intent of an entity is nil if it belongs to no intent, and the intents are nil altogether if the
+	// checkpoint predates recording of intents
+	CheckpointEntities(ctx context.Context, name string) ([]*p4api.Entity, []*api.Intent, error)
+
+	// DeleteCheckpoint deletes the given checkpoint
+	DeleteCheckpoint(ctx context.Context, name string) error
 }
 
 type entityStore struct {
 	EntityStore
-	client primitive.Client
-	id     topo.ID
-	info   *p4info.P4Info
+	backend   Backend
+	namespace Namespace
+	id        topo.ID
+	info      *p4info.P4Info
+
+	writeConcurrency int
+
+	cached      bool
+	cacheCtx    context.Context
+	cacheCancel context.CancelFunc
+	cacheHits   uint64
+	cacheMisses uint64
+
+	indexes    []Index
+	indexDefs  []indexDef
+	indexHits  uint64
+	indexScans uint64
+
+	checkpointMu    sync.Mutex
+	checkpoints     Map
+	checkpointLimit int
 
 	mu     sync.RWMutex
 	tables map[uint32]*table
 	// TODO: Insert Atomix primitives to track table, group, meter, etc. entries
 }
 
-// NewEntityStore creates a new P4 entity store for the specified device
-func NewEntityStore(ctx context.Context, client primitive.Client, id topo.ID, info *p4info.P4Info) (EntityStore, error) {
+// NewEntityStore creates a new P4 entity store for the specified device, kept in the given backend
+func NewEntityStore(ctx context.Context, backend Backend, id topo.ID, info *p4info.P4Info, opts ...Option) (EntityStore, error) {
 	s := &entityStore{
-		client: client,
-		id:     id,
-		info:   info,
-		tables: make(map[uint32]*table),
+		backend:          backend,
+		namespace:        DefaultNamespace,
+		id:               id,
+		info:             info,
+		tables:           make(map[uint32]*table),
+		writeConcurrency: DefaultWriteConcurrency,
+		checkpointLimit:  DefaultCheckpointLimit,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.resolveIndexes(); err != nil {
+		return nil, err
 	}
 
 	// Preload/create stores for the required sets of entities, e.g. tables, counters, meters, etc.
 	if err := s.loadTables(ctx, info.Tables); err != nil {
 		return nil, err
 	}
+	checkpoints, err := backend.Map(ctx, s.namespace.mapName(id, "checkpoints"))
+	if err != nil {
+		return nil, err
+	}
+	s.checkpoints = checkpoints
+	if s.cached {
+		if err := s.startCaches(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	// s.loadCounters(info.Counters)
 	// s.loadMeters(info.Meters)
@@ -69,12 +161,9 @@ func NewEntityStore(ctx context.Context, client primitive.Client, id topo.ID, in
 
 func (s *entityStore) loadTables(ctx context.Context, tables []*p4info.Table) error {
 	for _, t := range tables {
-		emap, err := _map.NewBuilder[string, *p4api.TableEntry](s.client, fmt.Sprintf("control-%s-table-%d", s.id, t.Preamble.Id)).
-			Tag("onos-control", "p4rt-entities").
-			Codec(generic.Proto[*p4api.TableEntry](&p4api.TableEntry{})).
-			Get(ctx)
+		emap, err := s.backend.Map(ctx, tableMapName(s.namespace, s.id, t.Preamble.Id))
 		if err != nil {
-			return errors.FromAtomix(err)
+			return err
 		}
 		s.tables[t.Preamble.Id] = &table{entries: emap, info: t}
 	}
@@ -85,29 +174,30 @@ func (s *entityStore) loadTables(ctx context.Context, tables []*p4info.Table) er
 func (s *entityStore) Purge(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.stopCaches()
 	for _, t := range s.tables {
 		if err := t.purge(ctx); err != nil {
 			return err
 		}
 	}
-	return nil
+	if err := clearMap(ctx, s.checkpoints); err != nil {
+		return err
+	}
+	return s.checkpoints.Close(ctx)
 }
 
 func (t *table) purge(ctx context.Context) error {
-	stream, err := t.entries.List(ctx)
-	if err != nil {
-		return errors.FromAtomix(err)
+	keys := make([]string, 0)
+	if err := t.entries.List(ctx, func(entry *MapEntry) error {
+		keys = append(keys, entry.Key)
+		return nil
+	}); err != nil {
+		return err
 	}
-	for {
-		v, err := stream.Next()
-		if err != nil {
-			if err == io.EOF {
-				return t.entries.Close(ctx)
-			}
-			return err
-		}
-		_, _ = t.entries.Remove(ctx, v.Key)
+	for _, key := range keys {
+		_, _ = t.entries.Remove(ctx, key, 0)
 	}
+	return t.entries.Close(ctx)
 }
 
 // ID returns the ID of the device whose control entities it persists.
@@ -115,43 +205,38 @@ func (s *entityStore) ID() topo.ID {
 	return s.id
 }
 
-// Read accepts a query in form of a list of partially populated entities and returns any
-// matching entities on the specified channel
-func (s *entityStore) Read(ctx context.Context, query []*p4api.Entity, ch chan<- *p4api.Entity) []error {
-	// Allocate the same number of errors as there are requests - expressed as entities
-	errs := make([]error, len(query))
-
-	for i, request := range query {
-		errs[i] = s.processRead(ctx, request, ch)
-	}
-	close(ch)
-	return errs
+// Read accepts a query in form of a list of partially populated entities and returns an iterator
+// over any matching entities
+func (s *entityStore) Read(ctx context.Context, query []*p4api.Entity) api.EntityIterator {
+	return api.NewEntityIterator(ctx, len(query), func(ctx context.Context, i int, emit func(entity *p4api.Entity, revision api.Revision) error) error {
+		return s.processRead(ctx, query[i], emit)
+	})
 }
 
-func (s *entityStore) processRead(ctx context.Context, query *p4api.Entity, ch chan<- *p4api.Entity) error {
+func (s *entityStore) processRead(ctx context.Context, query *p4api.Entity, emit func(entity *p4api.Entity, revision api.Revision) error) error {
 	switch {
 	case query.GetTableEntry() != nil:
-		return s.readTableEntries(ctx, query.GetTableEntry(), ch)
+		return s.readTableEntries(ctx, query.GetTableEntry(), emit)
 	case query.GetCounterEntry() != nil:
-		return s.readCounterEntries(ctx, query.GetCounterEntry(), ch)
+		return s.readCounterEntries(ctx, query.GetCounterEntry(), emit)
 	case query.GetDirectCounterEntry() != nil:
-		return s.readDirectCounterEntries(ctx, query.GetDirectCounterEntry(), ch)
+		return s.readDirectCounterEntries(ctx, query.GetDirectCounterEntry(), emit)
 	case query.GetMeterEntry() != nil:
-		return s.readMeterEntries(ctx, query.GetMeterEntry(), ch)
+		return s.readMeterEntries(ctx, query.GetMeterEntry(), emit)
 	case query.GetDirectMeterEntry() != nil:
-		return s.readDirectMeterEntries(ctx, query.GetDirectMeterEntry(), ch)
+		return s.readDirectMeterEntries(ctx, query.GetDirectMeterEntry(), emit)
 
 	case query.GetActionProfileGroup() != nil:
-		return s.readActionProfileGroups(ctx, query.GetActionProfileGroup(), ch)
+		return s.readActionProfileGroups(ctx, query.GetActionProfileGroup(), emit)
 	case query.GetActionProfileMember() != nil:
-		return s.readActionProfileMembers(ctx, query.GetActionProfileMember(), ch)
+		return s.readActionProfileMembers(ctx, query.GetActionProfileMember(), emit)
 
 	case query.GetPacketReplicationEngineEntry() != nil:
 		switch {
 		case query.GetPacketReplicationEngineEntry().GetMulticastGroupEntry() != nil:
-			return s.readMulticastGroupEntries(ctx, query.GetPacketReplicationEngineEntry().GetMulticastGroupEntry(), ch)
+			return s.readMulticastGroupEntries(ctx, query.GetPacketReplicationEngineEntry().GetMulticastGroupEntry(), emit)
 		case query.GetPacketReplicationEngineEntry().GetCloneSessionEntry() != nil:
-			return s.readCloneSessionEntries(ctx, query.GetPacketReplicationEngineEntry().GetCloneSessionEntry(), ch)
+			return s.readCloneSessionEntries(ctx, query.GetPacketReplicationEngineEntry().GetCloneSessionEntry(), emit)
 		}
 
 	case query.GetRegisterEntry() != nil:
@@ -163,9 +248,33 @@ func (s *entityStore) processRead(ctx context.Context, query *p4api.Entity, ch c
 	return nil
 }
 
-// Write persists the specified list of updates.
-func (s *entityStore) Write(ctx context.Context, updates []*p4api.Update) error {
+// Write persists the specified list of updates. Table entry updates are grouped per table; the updates of each
+// table are applied as a single atomic batch if the backend maps support it, as those of the memory and file
+// backends do, and otherwise grouped per entry. The batches and groups are applied concurrently, up to the write
+// concurrency of the store, with updates of the same entry applied in order. Updates are validated before any of
+// them are applied; if any update fails to be applied, the error of the earliest failed update is returned, while
+// the updates of other tables, or with non-atomic backends any other updates, may have been applied. Conditional
+// updates whose expected revision does not match that of the persisted entity fail with a Conflict error.
+func (s *entityStore) Write(ctx context.Context, updates []*p4api.Update, opts ...WriteOption) error {
+	options := &writeOptions{revisions: make(map[*p4api.Update]api.Revision), intents: make(map[*p4api.Update]*api.Intent)}
+	for _, opt := range opts {
+		opt(options)
+	}
+	batches, err := s.groupTableUpdates(updates, options)
+	if err != nil {
+		return err
+	}
+
 	for _, update := range updates {
+		if update.Entity.GetTableEntry() != nil {
+			continue
+		}
+		if _, ok := options.revisions[update]; ok {
+			return errors.NewInvalid("Conditional updates are supported only for table entries")
+		}
+		if _, ok := options.intents[update]; ok {
+			return errors.NewInvalid("Intents are supported only for table entries")
+		}
 		switch {
 		case update.Type == p4api.Update_INSERT:
 			if err := s.processModify(ctx, update, true); err != nil {
@@ -183,15 +292,13 @@ func (s *entityStore) Write(ctx context.Context, updates []*p4api.Update) error
 			}
 		}
 	}
-	return nil
+	return s.applyTableUpdates(ctx, batches)
 }
 
 func (s *entityStore) processModify(ctx context.Context, update *p4api.Update, isInsert bool) error {
 	entity := update.Entity
 	var err error
 	switch {
-	case entity.GetTableEntry() != nil:
-		err = s.modifyTableEntry(ctx, entity.GetTableEntry(), isInsert)
 	case entity.GetCounterEntry() != nil:
 		err = s.modifyCounterEntry(ctx, entity.GetCounterEntry(), isInsert)
 	case entity.GetDirectCounterEntry() != nil:
@@ -231,8 +338,6 @@ func (s *entityStore) processDelete(ctx context.Context, update *p4api.Update) e
 	entity := update.Entity
 	var err error
 	switch {
-	case entity.GetTableEntry() != nil:
-		err = s.removeTableEntry(ctx, entity.GetTableEntry())
 	case entity.GetCounterEntry() != nil:
 		return errors.NewInvalid("counter cannot be deleted")
 	case entity.GetDirectCounterEntry() != nil:
@@ -6,7 +6,7 @@ package store
 
 import (
 	"context"
-	"github.com/atomix/go-sdk/pkg/test"
+	"github.com/onosproject/onos-control/pkg/api"
 	"github.com/onosproject/onos-net-lib/pkg/p4utils"
 	testutils "github.com/onosproject/onos-net-lib/pkg/test"
 	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
@@ -17,14 +17,16 @@ import (
 )
 
 func TestStoreBasics(t *testing.T) {
-	client := test.NewClient()
+	forEachBackend(t, testStoreBasics)
+}
 
+func testStoreBasics(t *testing.T, backend Backend) {
 	ctx := context.TODO()
 
 	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
 	assert.NoError(t, err)
 
-	store, err := NewEntityStore(ctx, client, "foo", info)
+	store, err := NewEntityStore(ctx, backend, "foo", info)
 	assert.NoError(t, err)
 
 	es := store.(*entityStore)
@@ -86,17 +88,10 @@ func generateRandomUpdates(info *p4info.P4Info) []*p4api.Update {
 }
 
 func readEntries(ctx context.Context, t *testing.T, store EntityStore, query []*p4api.Entity, count int) []*p4api.Entity {
-	ch := make(chan *p4api.Entity, 1024)
-	errs := store.Read(ctx, query, ch)
-	for _, er := range errs {
-		assert.NoError(t, er)
-	}
+	entities, err := api.ReadAll(store.Read(ctx, query))
+	assert.NoError(t, err)
 
 	// Validate that we got all entries
-	entities := make([]*p4api.Entity, 0, count)
-	for e := range ch {
-		entities = append(entities, e)
-	}
 	assert.Len(t, entities, count)
 	return entities
 }
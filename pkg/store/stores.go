@@ -7,19 +7,25 @@ package store
 
 import (
 	"context"
-	"github.com/atomix/go-sdk/pkg/primitive"
 	"github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
 	"sync"
+	"time"
 )
 
 var log = logging.GetLogger("store")
 
 // Stores is an abstraction of an entity capable of tracking multiple entity stores created
-// on behalf of different devices.
+// on behalf of different devices, all within a single namespace.
 type Stores interface {
+	// Namespace returns the namespace of the entity stores; stores in other namespaces are never affected
+	Namespace() Namespace
+
+	// Namespaces returns all namespaces in which entity stores have been created using the same backend
+	Namespaces(ctx context.Context) ([]Namespace, error)
+
 	// Get returns an entity store for the specified device. A new one will be created if it doesn't
 	// already exist. Returns error if the backing storage system is unavailable.
 	Get(ctx context.Context, id topo.ID, info *p4info.P4Info) (EntityStore, error)
@@ -29,23 +35,71 @@ type Stores interface {
 	// that this list itself is not persisted.
 	GetAll() []EntityStore
 
-	// Purge purges the given device entity store and all the data within it.
+	// List returns the records of all device entity stores of the namespace, including those created during
+	// earlier runs; such stores can be retrieved using Get without specifying P4Info.
+	List(ctx context.Context) ([]*StoreRecord, error)
+
+	// CollectGarbage purges the entity stores of devices which the checker has continuously found not to exist
+	// for at least the grace period; returns the IDs of the devices whose stores were purged
+	CollectGarbage(ctx context.Context, checker DeviceChecker, grace time.Duration) ([]topo.ID, error)
+
+	// Purge purges the given device entity store and all the data within it; entity stores of the same device in
+	// other namespaces are left intact.
 	Purge(ctx context.Context, id topo.ID) error
+
+	// Migration returns the report of the migration performed when the given device entity store was retrieved
+	// with a P4Info different from the one its entities have been persisted with; nil if there was none
+	Migration(id topo.ID) *MigrationReport
+}
+
+// StoresOption is a stores manager configuration option
+type StoresOption func(sm *storeManager)
+
+// WithMigrationHook sets the hook used to migrate table entries whose fields changed between P4Info versions
+func WithMigrationHook(hook MigrationHook) StoresOption {
+	return func(sm *storeManager) {
+		sm.hook = hook
+	}
+}
+
+// WithStoreOptions sets the options, e.g. WithIndexes, WithCache, WithWriteConcurrency or WithCheckpointLimit, with
+// which the entity stores of all devices are created; the namespace of the stores is always that of the manager
+func WithStoreOptions(opts ...Option) StoresOption {
+	return func(sm *storeManager) {
+		sm.options = append(sm.options, opts...)
+	}
 }
 
 type storeManager struct {
 	Stores
-	mu     sync.RWMutex
-	client primitive.Client
-	stores map[topo.ID]EntityStore
+	mu         sync.RWMutex
+	backend    Backend
+	namespace  Namespace
+	registered bool
+	hook       MigrationHook
+	options    []Option
+	stores     map[topo.ID]EntityStore
+	migrations map[topo.ID]*MigrationReport
 }
 
-// NewStoreManager creates a new stores manager
-func NewStoreManager(client primitive.Client) Stores {
-	return &storeManager{client: client, stores: make(map[topo.ID]EntityStore, 0)}
+// NewStoreManager creates a new stores manager keeping the entity stores in the given backend, within the
+// specified namespace
+func NewStoreManager(backend Backend, namespace Namespace, opts ...StoresOption) Stores {
+	sm := &storeManager{
+		backend:    backend,
+		namespace:  namespace,
+		stores:     make(map[topo.ID]EntityStore, 0),
+		migrations: make(map[topo.ID]*MigrationReport),
+	}
+	for _, opt := range opts {
+		opt(sm)
+	}
+	return sm
 }
 
-// Get returns an entity store for the specified device.
+// Get returns an entity store for the specified device. When creating the store, its persisted entities are
+// migrated if they have been persisted with a P4Info different from the given one. If no P4Info is given, the
+// P4Info with which the entities have been persisted is used, if any.
 func (sm *storeManager) Get(ctx context.Context, id topo.ID, info *p4info.P4Info) (EntityStore, error) {
 	sm.mu.RLock()
 	store, ok := sm.stores[id]
@@ -55,7 +109,12 @@ func (sm *storeManager) Get(ctx context.Context, id topo.ID, info *p4info.P4Info
 	}
 
 	if info == nil {
-		return nil, errors.NewNotFound("Store not found for %s", id)
+		var err error
+		if info, err = loadSchema(ctx, sm.backend, sm.namespace, id); err != nil {
+			return nil, err
+		} else if info == nil {
+			return nil, errors.NewNotFound("Store not found for %s", id)
+		}
 	}
 
 	sm.mu.Lock()
@@ -66,11 +125,32 @@ func (sm *storeManager) Get(ctx context.Context, id topo.ID, info *p4info.P4Info
 		return store, nil
 	}
 
+	if err := sm.namespace.validate(); err != nil {
+		return nil, err
+	}
+	if !sm.registered {
+		if err := registerNamespace(ctx, sm.backend, sm.namespace); err != nil {
+			return nil, err
+		}
+		sm.registered = true
+	}
+
 	var err error
-	log.Infof("Creating store %s", id)
-	store, err = NewEntityStore(ctx, sm.client, id, info)
+	log.Infof("Creating store %s in namespace %s", id, sm.namespace)
+	opts := append(append(make([]Option, 0, len(sm.options)+1), sm.options...), WithNamespace(sm.namespace))
+	store, err = NewEntityStore(ctx, sm.backend, id, info, opts...)
 	if err != nil {
-		return nil, errors.FromAtomix(err)
+		return nil, err
+	}
+	report, err := migrate(ctx, sm.backend, store.(*entityStore), sm.hook)
+	if err != nil {
+		return nil, err
+	}
+	if report != nil {
+		sm.migrations[id] = report
+	}
+	if err = registerStore(ctx, sm.backend, sm.namespace, id, Fingerprint(info)); err != nil {
+		return nil, err
 	}
 	sm.stores[id] = store
 	return store, nil
@@ -87,12 +167,52 @@ func (sm *storeManager) GetAll() []EntityStore {
 	return stores
 }
 
+// Namespace returns the namespace of the entity stores
+func (sm *storeManager) Namespace() Namespace {
+	return sm.namespace
+}
+
+// Namespaces returns all namespaces in which entity stores have been created using the same backend
+func (sm *storeManager) Namespaces(ctx context.Context) ([]Namespace, error) {
+	return listNamespaces(ctx, sm.backend)
+}
+
+// List returns the records of all device entity stores of the namespace
+func (sm *storeManager) List(ctx context.Context) ([]*StoreRecord, error) {
+	return listStores(ctx, sm.backend, sm.namespace)
+}
+
+// Migration returns the report of the migration performed when the given device entity store was retrieved
+func (sm *storeManager) Migration(id topo.ID) *MigrationReport {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.migrations[id]
+}
+
 // Purge purges the given device entity store and all the data within it.
 func (sm *storeManager) Purge(ctx context.Context, id topo.ID) error {
 	store, err := sm.Get(ctx, id, nil)
 	if err != nil {
 		return err
 	}
-	log.Infof("Purging store %s", id)
-	return store.(*entityStore).Purge(ctx)
+	log.Infof("Purging store %s in namespace %s", id, sm.namespace)
+	if err = store.(*entityStore).Purge(ctx); err != nil {
+		return err
+	}
+	schema, err := sm.backend.Map(ctx, schemaMapName(sm.namespace, id))
+	if err != nil {
+		return err
+	}
+	if err = clearMap(ctx, schema); err != nil {
+		return err
+	}
+	if err = unregisterStore(ctx, sm.backend, sm.namespace, id); err != nil {
+		return err
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	delete(sm.stores, id)
+	delete(sm.migrations, id)
+	return nil
 }
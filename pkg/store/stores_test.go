@@ -8,7 +8,9 @@ import (
 	"context"
 	"github.com/atomix/go-sdk/pkg/test"
 	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -21,7 +23,7 @@ func TestStoresBasics(t *testing.T) {
 	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
 	assert.NoError(t, err)
 
-	stores := NewStoreManager(client)
+	stores := NewStoreManager(NewAtomixBackend(client), DefaultNamespace)
 
 	fooStore, err := stores.Get(ctx, "foo", info)
 	assert.NoError(t, err)
@@ -45,3 +47,42 @@ func TestStoresBasics(t *testing.T) {
 	err = stores.Purge(ctx, "bar")
 	assert.NoError(t, err)
 }
+
+func TestStoresNamespaces(t *testing.T) {
+	ctx := context.TODO()
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	backend := NewMemoryBackend()
+	query := []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{}}}}
+
+	role := &p4api.Role{Name: "fabric"}
+	app1 := NewStoreManager(backend, NewNamespace("app1", role))
+	app2 := NewStoreManager(backend, NewNamespace("app2", role))
+	app2Default := NewStoreManager(backend, NewNamespace("app2", nil))
+	assert.Equal(t, Namespace{App: "app2", Role: "default"}, app2Default.Namespace())
+
+	// Stores of the same device in different namespaces must be kept apart
+	for i, stores := range []Stores{app1, app2, app2Default} {
+		store, err := stores.Get(ctx, "foo", info)
+		assert.NoError(t, err)
+		assert.NoError(t, store.Write(ctx, generateRoutes(info, i+1, p4api.Update_INSERT)))
+	}
+	for i, stores := range []Stores{app1, app2, app2Default} {
+		store, err := stores.Get(ctx, "foo", nil)
+		assert.NoError(t, err)
+		readEntries(ctx, t, store, query, i+1)
+	}
+
+	namespaces, err := app1.Namespaces(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []Namespace{{App: "app1", Role: "fabric"}, {App: "app2", Role: "default"}, {App: "app2", Role: "fabric"}}, namespaces)
+
+	// Purging a store must not affect the stores of the same device in other namespaces
+	assert.NoError(t, app1.Purge(ctx, "foo"))
+	store, err := app2.Get(ctx, "foo", nil)
+	assert.NoError(t, err)
+	readEntries(ctx, t, store, query, 2)
+
+	_, err = NewStoreManager(backend, Namespace{App: "a/b", Role: "c"}).Get(ctx, "foo", info)
+	assert.True(t, errors.IsInvalid(err))
+}
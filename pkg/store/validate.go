@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4info "github.com/p4lang/p4runtime/go/p4/config/v1"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"math/bits"
+)
+
+// ValidateEntity validates that the given entity complies with the P4Info. Table entries must refer to an existing
+// table, specify only its match fields, all of its exact match fields unless they are default entries, and use an
+// action permitted in the table with all of its parameters; all values must fit within their bit widths.
+func ValidateEntity(info *p4info.P4Info, entity *p4api.Entity) error {
+	switch {
+	case entity.GetTableEntry() != nil:
+		return validateTableEntry(info, entity.GetTableEntry(), nil)
+	default:
+		return nil
+	}
+}
+
+// Decides which exact match fields and action parameters must not be missing from table entries; nil functions, or
+// nil requirements altogether, require all of them
+type requirements struct {
+	matchField  func(table *p4info.Table, field *p4info.MatchField) bool
+	actionParam func(action *p4info.Action, param *p4info.Action_Param) bool
+}
+
+func (r *requirements) requiresMatchField(table *p4info.Table, field *p4info.MatchField) bool {
+	return r == nil || r.matchField == nil || r.matchField(table, field)
+}
+
+func (r *requirements) requiresActionParam(action *p4info.Action, param *p4info.Action_Param) bool {
+	return r == nil || r.actionParam == nil || r.actionParam(action, param)
+}
+
+func validateTableEntry(info *p4info.P4Info, entry *p4api.TableEntry, required *requirements) error {
+	table := api.FindTableByID(info, entry.TableId)
+	if table == nil {
+		return errors.NewInvalid("No such table %d", entry.TableId)
+	}
+	if entry.IsDefaultAction && len(entry.Match) > 0 {
+		return errors.NewInvalid("Default entry of table %s cannot have any match fields", table.Preamble.Name)
+	}
+
+	present := make(map[uint32]bool)
+	for _, m := range entry.Match {
+		f := api.FindMatchFieldByID(table, m.FieldId)
+		if f == nil {
+			return errors.NewInvalid("Table %s has no match field %d", table.Preamble.Name, m.FieldId)
+		}
+		if present[f.Id] {
+			return errors.NewInvalid("Match field %s of table %s specified more than once", f.Name, table.Preamble.Name)
+		}
+		if err := validateWidth(f.Name, matchValue(m), f.Bitwidth); err != nil {
+			return err
+		}
+		present[f.Id] = true
+	}
+	if !entry.IsDefaultAction {
+		for _, f := range table.MatchFields {
+			if f.GetMatchType() == p4info.MatchField_EXACT && !present[f.Id] && required.requiresMatchField(table, f) {
+				return errors.NewInvalid("Table %s requires exact match field %s", table.Preamble.Name, f.Name)
+			}
+		}
+	}
+
+	switch {
+	case entry.Action.GetAction() != nil:
+		return validateAction(info, table, entry.Action.GetAction(), required)
+	case entry.Action.GetActionProfileActionSet() != nil:
+		for _, pa := range entry.Action.GetActionProfileActionSet().ActionProfileActions {
+			if err := validateAction(info, table, pa.Action, required); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateAction(info *p4info.P4Info, table *p4info.Table, action *p4api.Action, required *requirements) error {
+	a := api.FindActionByID(info, action.GetActionId())
+	if a == nil {
+		return errors.NewInvalid("No such action %d", action.GetActionId())
+	}
+	if !api.HasActionRef(table, a.Preamble.Id) {
+		return errors.NewInvalid("Action %s is not permitted in table %s", a.Preamble.Name, table.Preamble.Name)
+	}
+	present := make(map[uint32]bool)
+	for _, p := range action.Params {
+		ap := api.FindParamByID(a, p.ParamId)
+		if ap == nil {
+			return errors.NewInvalid("Action %s has no parameter %d", a.Preamble.Name, p.ParamId)
+		}
+		if err := validateWidth(ap.Name, p.Value, ap.Bitwidth); err != nil {
+			return err
+		}
+		present[ap.Id] = true
+	}
+	for _, ap := range a.Params {
+		if !present[ap.Id] && required.requiresActionParam(a, ap) {
+			return errors.NewInvalid("Action %s requires parameter %s", a.Preamble.Name, ap.Name)
+		}
+	}
+	return nil
+}
+
+// Returns the value of the given field match
+func matchValue(m *p4api.FieldMatch) []byte {
+	switch {
+	case m.GetExact() != nil:
+		return m.GetExact().Value
+	case m.GetLpm() != nil:
+		return m.GetLpm().Value
+	case m.GetTernary() != nil:
+		return m.GetTernary().Value
+	case m.GetRange() != nil:
+		return m.GetRange().High
+	case m.GetOptional() != nil:
+		return m.GetOptional().Value
+	}
+	return nil
+}
+
+// Validates that the given value fits within the specified bit width; zero width means the width is not fixed
+func validateWidth(name string, value []byte, bitwidth int32) error {
+	if bitwidth == 0 {
+		return nil
+	}
+	n := 0
+	for i, b := range value {
+		if b != 0 {
+			n = (len(value)-i-1)*8 + bits.Len8(b)
+			break
+		}
+	}
+	if n > int(bitwidth) {
+		return errors.NewInvalid("Value of %s does not fit within %d bits", name, bitwidth)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+func TestValidateEntity(t *testing.T) {
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	route := generateRoutes(info, 1, p4api.Update_INSERT)[0].Entity
+	assert.NoError(t, ValidateEntity(info, route))
+
+	invalid := func(change func(entry *p4api.TableEntry)) {
+		entity := proto.Clone(route).(*p4api.Entity)
+		change(entity.GetTableEntry())
+		assert.True(t, errors.IsInvalid(ValidateEntity(info, entity)))
+	}
+	invalid(func(entry *p4api.TableEntry) { entry.TableId = 1 })
+	invalid(func(entry *p4api.TableEntry) { entry.Match[0].FieldId = 7 })
+	invalid(func(entry *p4api.TableEntry) { entry.Match = append(entry.Match, entry.Match[0]) })
+	invalid(func(entry *p4api.TableEntry) { entry.Match[0].GetLpm().Value = []byte{1, 2, 3, 4, 5} })
+	invalid(func(entry *p4api.TableEntry) { entry.IsDefaultAction = true })
+	invalid(func(entry *p4api.TableEntry) { entry.Action.GetAction().ActionId = 1 })
+	invalid(func(entry *p4api.TableEntry) { entry.Action.GetAction().Params = nil })
+	invalid(func(entry *p4api.TableEntry) { entry.Action.GetAction().Params[0].ParamId = 9 })
+
+	mpls := p4utils.FindTable(info, "FabricIngress.forwarding.mpls")
+	entry := &p4api.TableEntry{TableId: mpls.Preamble.Id, Action: route.GetTableEntry().Action}
+	err = ValidateEntity(info, &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}})
+	assert.True(t, errors.IsInvalid(err))
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-control/pkg/api"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"sync"
+)
+
+// EventType is the type of change of a persisted entity
+type EventType int
+
+const (
+	// EntityInserted represents insertion of a new entity
+	EntityInserted EventType = iota
+	// EntityUpdated represents update of an existing entity
+	EntityUpdated
+	// EntityRemoved represents removal of an existing entity
+	EntityRemoved
+)
+
+// Event describes a change of a persisted entity. For removals, the entity and the revision are those of the
+// entity just prior to its removal.
+type Event struct {
+	Type     EventType
+	Entity   *p4api.Entity
+	Revision api.Revision
+}
+
+// Watch streams events describing changes of the persisted entities matching the given filter, made by any
+// client of the store, until the context is cancelled; the channel is closed afterwards. The filter has the
+// same form as a read query; an empty filter matches all entities. Only table entries can be watched, as they are the
+// only entities persisted by the store; filters of other kinds of entities are not supported. Only changes made after
+// Watch returns are guaranteed to be reported.
+func (s *entityStore) Watch(ctx context.Context, filter []*p4api.Entity, ch chan<- *Event) error {
+	for _, f := range filter {
+		if f.GetTableEntry() == nil {
+			return errors.NewNotSupported("Only table entries can be watched")
+		}
+	}
+
+	s.mu.RLock()
+	tables := make([]*table, 0, len(s.tables))
+	queries := make(map[uint32][]*p4api.TableEntry)
+	for id, t := range s.tables {
+		if tq, ok := tableQueries(id, filter); ok {
+			tables = append(tables, t)
+			queries[id] = tq
+		}
+	}
+	s.mu.RUnlock()
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	streams := make([]chan *MapEvent, len(tables))
+	for i, t := range tables {
+		streams[i] = make(chan *MapEvent)
+		if err := t.entries.Watch(watchCtx, streams[i]); err != nil {
+			cancel()
+			return err
+		}
+	}
+
+	wg := &sync.WaitGroup{}
+	for i, t := range tables {
+		wg.Add(1)
+		go func(t *table, events <-chan *MapEvent) {
+			defer wg.Done()
+			if err := s.watchTable(watchCtx, t, events, queries[t.info.Preamble.Id], ch); err != nil {
+				log.Warnf("Device %s: Unable to watch table %s: %+v", s.id, t.info.Preamble.Name, err)
+			}
+		}(t, streams[i])
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+		close(ch)
+	}()
+	return nil
+}
+
+// Returns the table entry queries of the given filter applicable to the specified table, if any
+func tableQueries(id uint32, filter []*p4api.Entity) ([]*p4api.TableEntry, bool) {
+	if len(filter) == 0 {
+		return []*p4api.TableEntry{{}}, true
+	}
+	queries := make([]*p4api.TableEntry, 0)
+	for _, f := range filter {
+		if q := f.GetTableEntry(); q != nil && (q.TableId == 0 || q.TableId == id) {
+			queries = append(queries, q)
+		}
+	}
+	return queries, len(queries) > 0
+}
+
+func (s *entityStore) watchTable(ctx context.Context, t *table, events <-chan *MapEvent, queries []*p4api.TableEntry, ch chan<- *Event) error {
+	defer func() {
+		for range events {
+		}
+	}()
+	for me := range events {
+		te, err := decodeTableEntry(me.Entry)
+		if err != nil {
+			return err
+		}
+		if !s.matchesAny(te, queries) {
+			continue
+		}
+		event := &Event{
+			Type:     me.Type,
+			Entity:   &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: te}},
+			Revision: me.Entry.Revision,
+		}
+		select {
+		case ch <- event:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	if ctx.Err() == nil {
+		return errors.NewUnavailable("Event stream ended")
+	}
+	return nil
+}
+
+// Returns true if the entry matches any of the given queries
+func (s *entityStore) matchesAny(entry *p4api.TableEntry, queries []*p4api.TableEntry) bool {
+	for _, q := range queries {
+		if s.matchesQuery(entry, q) {
+			return true
+		}
+	}
+	return false
+}
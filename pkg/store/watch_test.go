@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/p4utils"
+	testutils "github.com/onosproject/onos-net-lib/pkg/test"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	forEachBackend(t, testWatch)
+}
+
+func testWatch(t *testing.T, backend Backend) {
+	ctx, cancel := context.WithCancel(context.Background())
+	info, err := p4utils.LoadP4Info("../../test/p4info.txt")
+	assert.NoError(t, err)
+	store, err := NewEntityStore(ctx, backend, "foo", info)
+	assert.NoError(t, err)
+
+	// Entities other than table entries cannot be watched
+	counters := []*p4api.Entity{{Entity: &p4api.Entity_CounterEntry{CounterEntry: &p4api.CounterEntry{}}}}
+	assert.True(t, errors.IsNotSupported(store.Watch(ctx, counters, make(chan *Event))))
+
+	// Watch only the routing table
+	routing := p4utils.FindTable(info, "FabricIngress.forwarding.routing_v4")
+	ch := make(chan *Event)
+	assert.NoError(t, store.Watch(ctx, []*p4api.Entity{{Entity: &p4api.Entity_TableEntry{TableEntry: &p4api.TableEntry{TableId: routing.Preamble.Id}}}}, ch))
+
+	bridging := p4utils.FindTable(info, "FabricIngress.forwarding.bridging")
+	entry := testutils.GenerateTableEntry(routing, 1, nil)
+	assert.NoError(t, store.Write(ctx, []*p4api.Update{
+		{Type: p4api.Update_INSERT, Entity: &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: testutils.GenerateTableEntry(bridging, 1, nil)}}},
+		{Type: p4api.Update_INSERT, Entity: &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}},
+		{Type: p4api.Update_MODIFY, Entity: &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}},
+		{Type: p4api.Update_DELETE, Entity: &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}},
+	}))
+
+	inserted := nextEvent(t, ch)
+	assert.Equal(t, EntityInserted, inserted.Type)
+	assert.Equal(t, routing.Preamble.Id, inserted.Entity.GetTableEntry().TableId)
+	updated := nextEvent(t, ch)
+	assert.Equal(t, EntityUpdated, updated.Type)
+	assert.Greater(t, updated.Revision, inserted.Revision)
+	removed := nextEvent(t, ch)
+	assert.Equal(t, EntityRemoved, removed.Type)
+	assert.Equal(t, updated.Revision, removed.Revision)
+
+	// Cancelling the context must close the channel
+	cancel()
+	for range ch {
+	}
+}
+
+func nextEvent(t *testing.T, ch <-chan *Event) *Event {
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return nil
+	}
+}
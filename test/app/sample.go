@@ -10,6 +10,7 @@ import (
 	"github.com/atomix/go-sdk/pkg/client"
 	"github.com/onosproject/onos-control/pkg/api"
 	"github.com/onosproject/onos-control/pkg/controller"
+	"github.com/onosproject/onos-control/pkg/store"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-net-lib/pkg/p4utils"
 )
@@ -20,7 +21,7 @@ var log = logging.GetLogger("sample")
 func InitExample() error {
 	// Node: Consider creating a StratumRoleBuilder
 	role := p4utils.NewStratumRole("sample", 0, []byte{}, false, false)
-	devices := controller.NewController(role, client.NewClient())
+	devices := controller.NewController("sample", role, store.NewAtomixBackend(client.NewClient()))
 
 	// Note: Consider including a utility to easily add all devices from onos-topo using a realm label.
 	// This would fetch all devices matching the realm-label and the required onos.topo.StratumAgents, and
@@ -0,0 +1,8 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fabric contains the Go bindings generated for the test fabric pipeline
+package fabric
+
+//go:generate go run ../../cmd/p4gen -p4info ../p4info.txt -package fabric -out p4info.go
@@ -0,0 +1,2316 @@
+// Code generated by p4gen from p4info.txt. DO NOT EDIT.
+
+package fabric
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+)
+
+// Value is the type of match field, action parameter and packet metadata values; values of up to 64 bits
+// are represented as unsigned integers and wider values as byte strings
+type Value interface {
+	uint64 | []byte
+}
+
+// Ternary is the value and mask of a ternary match
+type Ternary[T Value] struct {
+	Value T
+	Mask  T
+}
+
+// LPM is the value and prefix length of a longest-prefix match
+type LPM[T Value] struct {
+	Value     T
+	PrefixLen int32
+}
+
+// Range is the inclusive bounds of a range match
+type Range[T Value] struct {
+	Low  T
+	High T
+}
+
+// Optional is the value of an optional match
+type Optional[T Value] struct {
+	Value T
+}
+
+// Action is an action of the pipeline
+type Action interface {
+	// P4Action returns the P4Runtime representation of the action; returns error if any of its parameters does not
+	// fit in its bitwidth
+	P4Action() (*p4api.Action, error)
+}
+
+// Encodes the value into its canonical byte string
+func encode[T Value](value T) []byte {
+	switch v := any(value).(type) {
+	case uint64:
+		b := make([]byte, 0, 8)
+		for shift := 56; shift >= 0; shift -= 8 {
+			if c := byte(v >> shift); c != 0 || len(b) > 0 || shift == 0 {
+				b = append(b, c)
+			}
+		}
+		return b
+	case []byte:
+		return v
+	}
+	return nil
+}
+
+// Accumulates the first error encountered while encoding values
+type encoder struct {
+	err error
+}
+
+// Encodes the value of the named field into its canonical byte string, recording an error if an integer value does
+// not fit in the given bitwidth; values with no bitwidth, i.e. strings, are not checked
+func encodeField[T Value](enc *encoder, value T, bitwidth int32, name string) []byte {
+	if v, ok := any(value).(uint64); ok && bitwidth > 0 && bitwidth < 64 && v>>bitwidth != 0 && enc.err == nil {
+		enc.err = errors.NewInvalid("Value %d of %s does not fit in %d bits", v, name, bitwidth)
+	}
+	return encode(value)
+}
+
+// Decodes the byte string into a value
+func decode[T Value](b []byte) T {
+	var value T
+	switch v := any(&value).(type) {
+	case *uint64:
+		for _, c := range b {
+			*v = *v<<8 | uint64(c)
+		}
+	case *[]byte:
+		*v = b
+	}
+	return value
+}
+
+// Pipeline table IDs
+const (
+	// FabricEgressDscpRewriterRewriterTableID is the ID of table FabricEgress.dscp_rewriter.rewriter
+	FabricEgressDscpRewriterRewriterTableID uint32 = 38849959
+	// FabricEgressEgressNextEgressVlanTableID is the ID of table FabricEgress.egress_next.egress_vlan
+	FabricEgressEgressNextEgressVlanTableID uint32 = 40271115
+	// FabricEgressPktIoEgressSwitchInfoTableID is the ID of table FabricEgress.pkt_io_egress.switch_info
+	FabricEgressPktIoEgressSwitchInfoTableID uint32 = 37174246
+	// FabricEgressStatsFlowsTableID is the ID of table FabricEgress.stats.flows
+	FabricEgressStatsFlowsTableID uint32 = 38468888
+	// FabricIngressAclAclTableID is the ID of table FabricIngress.acl.acl
+	FabricIngressAclAclTableID uint32 = 39601850
+	// FabricIngressFilteringFwdClassifierTableID is the ID of table FabricIngress.filtering.fwd_classifier
+	FabricIngressFilteringFwdClassifierTableID uint32 = 47458892
+	// FabricIngressFilteringIngressPortVlanTableID is the ID of table FabricIngress.filtering.ingress_port_vlan
+	FabricIngressFilteringIngressPortVlanTableID uint32 = 42758823
+	// FabricIngressForwardingBridgingTableID is the ID of table FabricIngress.forwarding.bridging
+	FabricIngressForwardingBridgingTableID uint32 = 36104978
+	// FabricIngressForwardingMplsTableID is the ID of table FabricIngress.forwarding.mpls
+	FabricIngressForwardingMplsTableID uint32 = 34710083
+	// FabricIngressForwardingRoutingV4TableID is the ID of table FabricIngress.forwarding.routing_v4
+	FabricIngressForwardingRoutingV4TableID uint32 = 45300881
+	// FabricIngressForwardingRoutingV6TableID is the ID of table FabricIngress.forwarding.routing_v6
+	FabricIngressForwardingRoutingV6TableID uint32 = 45220903
+	// FabricIngressNextHashedTableID is the ID of table FabricIngress.next.hashed
+	FabricIngressNextHashedTableID uint32 = 42948706
+	// FabricIngressNextMulticastTableID is the ID of table FabricIngress.next.multicast
+	FabricIngressNextMulticastTableID uint32 = 37579609
+	// FabricIngressPktIoIgSwitchInfoTableID is the ID of table FabricIngress.pkt_io.ig_switch_info
+	FabricIngressPktIoIgSwitchInfoTableID uint32 = 42835074
+	// FabricIngressPreNextNextMplsTableID is the ID of table FabricIngress.pre_next.next_mpls
+	FabricIngressPreNextNextMplsTableID uint32 = 47358279
+	// FabricIngressPreNextNextVlanTableID is the ID of table FabricIngress.pre_next.next_vlan
+	FabricIngressPreNextNextVlanTableID uint32 = 40653657
+	// FabricIngressQosDefaultTcTableID is the ID of table FabricIngress.qos.default_tc
+	FabricIngressQosDefaultTcTableID uint32 = 44938274
+	// FabricIngressQosQueuesTableID is the ID of table FabricIngress.qos.queues
+	FabricIngressQosQueuesTableID uint32 = 46891572
+	// FabricIngressSliceTcClassifierClassifierTableID is the ID of table FabricIngress.slice_tc_classifier.classifier
+	FabricIngressSliceTcClassifierClassifierTableID uint32 = 36334997
+	// FabricIngressStatsFlowsTableID is the ID of table FabricIngress.stats.flows
+	FabricIngressStatsFlowsTableID uint32 = 41690810
+)
+
+// Pipeline action IDs
+const (
+	// FabricEgressDscpRewriterClearActionID is the ID of action FabricEgress.dscp_rewriter.clear
+	FabricEgressDscpRewriterClearActionID uint32 = 24120545
+	// FabricEgressDscpRewriterRewriteActionID is the ID of action FabricEgress.dscp_rewriter.rewrite
+	FabricEgressDscpRewriterRewriteActionID uint32 = 27951287
+	// FabricEgressEgressNextDropActionID is the ID of action FabricEgress.egress_next.drop
+	FabricEgressEgressNextDropActionID uint32 = 30812542
+	// FabricEgressEgressNextPopVlanActionID is the ID of action FabricEgress.egress_next.pop_vlan
+	FabricEgressEgressNextPopVlanActionID uint32 = 17183246
+	// FabricEgressEgressNextPushVlanActionID is the ID of action FabricEgress.egress_next.push_vlan
+	FabricEgressEgressNextPushVlanActionID uint32 = 30307755
+	// FabricEgressPktIoEgressSetSwitchInfoActionID is the ID of action FabricEgress.pkt_io_egress.set_switch_info
+	FabricEgressPktIoEgressSetSwitchInfoActionID uint32 = 32804382
+	// FabricEgressStatsCountActionID is the ID of action FabricEgress.stats.count
+	FabricEgressStatsCountActionID uint32 = 26838724
+	// FabricIngressAclCopyToCpuActionID is the ID of action FabricIngress.acl.copy_to_cpu
+	FabricIngressAclCopyToCpuActionID uint32 = 21161133
+	// FabricIngressAclDropActionID is the ID of action FabricIngress.acl.drop
+	FabricIngressAclDropActionID uint32 = 23570973
+	// FabricIngressAclNopAclActionID is the ID of action FabricIngress.acl.nop_acl
+	FabricIngressAclNopAclActionID uint32 = 29607214
+	// FabricIngressAclPuntToCpuActionID is the ID of action FabricIngress.acl.punt_to_cpu
+	FabricIngressAclPuntToCpuActionID uint32 = 23579892
+	// FabricIngressAclSetNextIdAclActionID is the ID of action FabricIngress.acl.set_next_id_acl
+	FabricIngressAclSetNextIdAclActionID uint32 = 23623126
+	// FabricIngressAclSetOutputPortActionID is the ID of action FabricIngress.acl.set_output_port
+	FabricIngressAclSetOutputPortActionID uint32 = 24507494
+	// FabricIngressFilteringDenyActionID is the ID of action FabricIngress.filtering.deny
+	FabricIngressFilteringDenyActionID uint32 = 17164167
+	// FabricIngressFilteringPermitActionID is the ID of action FabricIngress.filtering.permit
+	FabricIngressFilteringPermitActionID uint32 = 24158268
+	// FabricIngressFilteringPermitWithInternalVlanActionID is the ID of action FabricIngress.filtering.permit_with_internal_vlan
+	FabricIngressFilteringPermitWithInternalVlanActionID uint32 = 24266015
+	// FabricIngressFilteringSetForwardingTypeActionID is the ID of action FabricIngress.filtering.set_forwarding_type
+	FabricIngressFilteringSetForwardingTypeActionID uint32 = 25032921
+	// FabricIngressForwardingDropRoutingV4ActionID is the ID of action FabricIngress.forwarding.drop_routing_v4
+	FabricIngressForwardingDropRoutingV4ActionID uint32 = 17639597
+	// FabricIngressForwardingDropRoutingV6ActionID is the ID of action FabricIngress.forwarding.drop_routing_v6
+	FabricIngressForwardingDropRoutingV6ActionID uint32 = 24646532
+	// FabricIngressForwardingNopRoutingV4ActionID is the ID of action FabricIngress.forwarding.nop_routing_v4
+	FabricIngressForwardingNopRoutingV4ActionID uint32 = 29124955
+	// FabricIngressForwardingPopMplsAndNextActionID is the ID of action FabricIngress.forwarding.pop_mpls_and_next
+	FabricIngressForwardingPopMplsAndNextActionID uint32 = 30066030
+	// FabricIngressForwardingSetNextIdBridgingActionID is the ID of action FabricIngress.forwarding.set_next_id_bridging
+	FabricIngressForwardingSetNextIdBridgingActionID uint32 = 21791748
+	// FabricIngressForwardingSetNextIdRoutingV4ActionID is the ID of action FabricIngress.forwarding.set_next_id_routing_v4
+	FabricIngressForwardingSetNextIdRoutingV4ActionID uint32 = 19792090
+	// FabricIngressForwardingSetNextIdRoutingV6ActionID is the ID of action FabricIngress.forwarding.set_next_id_routing_v6
+	FabricIngressForwardingSetNextIdRoutingV6ActionID uint32 = 21856023
+	// FabricIngressNextOutputHashedActionID is the ID of action FabricIngress.next.output_hashed
+	FabricIngressNextOutputHashedActionID uint32 = 27301117
+	// FabricIngressNextResetMcastGroupIdActionID is the ID of action FabricIngress.next.reset_mcast_group_id
+	FabricIngressNextResetMcastGroupIdActionID uint32 = 23637707
+	// FabricIngressNextRoutingHashedActionID is the ID of action FabricIngress.next.routing_hashed
+	FabricIngressNextRoutingHashedActionID uint32 = 20985706
+	// FabricIngressNextSetMcastGroupIdActionID is the ID of action FabricIngress.next.set_mcast_group_id
+	FabricIngressNextSetMcastGroupIdActionID uint32 = 21629581
+	// FabricIngressPktIoSetSwitchInfoActionID is the ID of action FabricIngress.pkt_io.set_switch_info
+	FabricIngressPktIoSetSwitchInfoActionID uint32 = 18121573
+	// FabricIngressPreNextSetMplsLabelActionID is the ID of action FabricIngress.pre_next.set_mpls_label
+	FabricIngressPreNextSetMplsLabelActionID uint32 = 22765924
+	// FabricIngressPreNextSetVlanActionID is the ID of action FabricIngress.pre_next.set_vlan
+	FabricIngressPreNextSetVlanActionID uint32 = 33475378
+	// FabricIngressQosMeterDropActionID is the ID of action FabricIngress.qos.meter_drop
+	FabricIngressQosMeterDropActionID uint32 = 28214351
+	// FabricIngressQosSetDefaultTcActionID is the ID of action FabricIngress.qos.set_default_tc
+	FabricIngressQosSetDefaultTcActionID uint32 = 23587909
+	// FabricIngressQosSetQueueActionID is the ID of action FabricIngress.qos.set_queue
+	FabricIngressQosSetQueueActionID uint32 = 32116918
+	// FabricIngressSliceTcClassifierNoClassificationActionID is the ID of action FabricIngress.slice_tc_classifier.no_classification
+	FabricIngressSliceTcClassifierNoClassificationActionID uint32 = 30111108
+	// FabricIngressSliceTcClassifierSetSliceIdTcActionID is the ID of action FabricIngress.slice_tc_classifier.set_slice_id_tc
+	FabricIngressSliceTcClassifierSetSliceIdTcActionID uint32 = 23786376
+	// FabricIngressSliceTcClassifierTrustDscpActionID is the ID of action FabricIngress.slice_tc_classifier.trust_dscp
+	FabricIngressSliceTcClassifierTrustDscpActionID uint32 = 25983516
+	// FabricIngressStatsCountActionID is the ID of action FabricIngress.stats.count
+	FabricIngressStatsCountActionID uint32 = 21929788
+	// NoActionActionID is the ID of action NoAction
+	NoActionActionID uint32 = 21257015
+	// NopActionID is the ID of action nop
+	NopActionID uint32 = 28485346
+)
+
+// FabricEgressDscpRewriterRewriterTableAction is an action allowed for table FabricEgress.dscp_rewriter.rewriter
+type FabricEgressDscpRewriterRewriterTableAction interface {
+	Action
+	isFabricEgressDscpRewriterRewriterTableAction()
+}
+
+// FabricEgressDscpRewriterRewriterEntry is an entry of table FabricEgress.dscp_rewriter.rewriter
+type FabricEgressDscpRewriterRewriterEntry struct {
+	EgPort          uint64
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricEgressDscpRewriterRewriterTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricEgressDscpRewriterRewriterEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricEgressDscpRewriterRewriterTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.EgPort, 32, "eg_port")}}})
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricEgressDscpRewriterRewriterEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricEgressDscpRewriterRewriterEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricEgressEgressNextEgressVlanTableAction is an action allowed for table FabricEgress.egress_next.egress_vlan
+type FabricEgressEgressNextEgressVlanTableAction interface {
+	Action
+	isFabricEgressEgressNextEgressVlanTableAction()
+}
+
+// FabricEgressEgressNextEgressVlanEntry is an entry of table FabricEgress.egress_next.egress_vlan
+type FabricEgressEgressNextEgressVlanEntry struct {
+	VlanId          uint64
+	EgPort          uint64
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricEgressEgressNextEgressVlanTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricEgressEgressNextEgressVlanEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricEgressEgressNextEgressVlanTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.VlanId, 12, "vlan_id")}}})
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 2, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.EgPort, 32, "eg_port")}}})
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricEgressEgressNextEgressVlanEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricEgressEgressNextEgressVlanEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricEgressPktIoEgressSwitchInfoTableAction is an action allowed for table FabricEgress.pkt_io_egress.switch_info
+type FabricEgressPktIoEgressSwitchInfoTableAction interface {
+	Action
+	isFabricEgressPktIoEgressSwitchInfoTableAction()
+}
+
+// FabricEgressPktIoEgressSwitchInfoEntry is an entry of table FabricEgress.pkt_io_egress.switch_info
+type FabricEgressPktIoEgressSwitchInfoEntry struct {
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricEgressPktIoEgressSwitchInfoTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricEgressPktIoEgressSwitchInfoEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricEgressPktIoEgressSwitchInfoTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricEgressPktIoEgressSwitchInfoEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricEgressPktIoEgressSwitchInfoEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricEgressStatsFlowsTableAction is an action allowed for table FabricEgress.stats.flows
+type FabricEgressStatsFlowsTableAction interface {
+	Action
+	isFabricEgressStatsFlowsTableAction()
+}
+
+// FabricEgressStatsFlowsEntry is an entry of table FabricEgress.stats.flows
+type FabricEgressStatsFlowsEntry struct {
+	StatsFlowId     uint64
+	EgPort          uint64
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricEgressStatsFlowsTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricEgressStatsFlowsEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricEgressStatsFlowsTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.StatsFlowId, 10, "stats_flow_id")}}})
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 2, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.EgPort, 32, "eg_port")}}})
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricEgressStatsFlowsEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricEgressStatsFlowsEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressAclAclTableAction is an action allowed for table FabricIngress.acl.acl
+type FabricIngressAclAclTableAction interface {
+	Action
+	isFabricIngressAclAclTableAction()
+}
+
+// FabricIngressAclAclEntry is an entry of table FabricIngress.acl.acl
+type FabricIngressAclAclEntry struct {
+	IgPort          *Ternary[uint64]
+	EthDst          *Ternary[uint64]
+	EthSrc          *Ternary[uint64]
+	VlanId          *Ternary[uint64]
+	EthType         *Ternary[uint64]
+	Ipv4Src         *Ternary[uint64]
+	Ipv4Dst         *Ternary[uint64]
+	IpProto         *Ternary[uint64]
+	IcmpType        *Ternary[uint64]
+	IcmpCode        *Ternary[uint64]
+	L4Sport         *Ternary[uint64]
+	L4Dport         *Ternary[uint64]
+	IgPortType      *Ternary[uint64]
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressAclAclTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressAclAclEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressAclAclTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		if e.IgPort != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.IgPort.Value, 32, "ig_port"), Mask: encodeField(enc, e.IgPort.Mask, 32, "ig_port")}}})
+		}
+		if e.EthDst != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 2, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.EthDst.Value, 48, "eth_dst"), Mask: encodeField(enc, e.EthDst.Mask, 48, "eth_dst")}}})
+		}
+		if e.EthSrc != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 3, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.EthSrc.Value, 48, "eth_src"), Mask: encodeField(enc, e.EthSrc.Mask, 48, "eth_src")}}})
+		}
+		if e.VlanId != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 4, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.VlanId.Value, 12, "vlan_id"), Mask: encodeField(enc, e.VlanId.Mask, 12, "vlan_id")}}})
+		}
+		if e.EthType != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 5, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.EthType.Value, 16, "eth_type"), Mask: encodeField(enc, e.EthType.Mask, 16, "eth_type")}}})
+		}
+		if e.Ipv4Src != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 6, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.Ipv4Src.Value, 32, "ipv4_src"), Mask: encodeField(enc, e.Ipv4Src.Mask, 32, "ipv4_src")}}})
+		}
+		if e.Ipv4Dst != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 7, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.Ipv4Dst.Value, 32, "ipv4_dst"), Mask: encodeField(enc, e.Ipv4Dst.Mask, 32, "ipv4_dst")}}})
+		}
+		if e.IpProto != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 8, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.IpProto.Value, 8, "ip_proto"), Mask: encodeField(enc, e.IpProto.Mask, 8, "ip_proto")}}})
+		}
+		if e.IcmpType != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 9, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.IcmpType.Value, 8, "icmp_type"), Mask: encodeField(enc, e.IcmpType.Mask, 8, "icmp_type")}}})
+		}
+		if e.IcmpCode != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 10, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.IcmpCode.Value, 8, "icmp_code"), Mask: encodeField(enc, e.IcmpCode.Mask, 8, "icmp_code")}}})
+		}
+		if e.L4Sport != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 11, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.L4Sport.Value, 16, "l4_sport"), Mask: encodeField(enc, e.L4Sport.Mask, 16, "l4_sport")}}})
+		}
+		if e.L4Dport != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 12, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.L4Dport.Value, 16, "l4_dport"), Mask: encodeField(enc, e.L4Dport.Mask, 16, "l4_dport")}}})
+		}
+		if e.IgPortType != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 13, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.IgPortType.Value, 2, "ig_port_type"), Mask: encodeField(enc, e.IgPortType.Mask, 2, "ig_port_type")}}})
+		}
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressAclAclEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressAclAclEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressFilteringFwdClassifierTableAction is an action allowed for table FabricIngress.filtering.fwd_classifier
+type FabricIngressFilteringFwdClassifierTableAction interface {
+	Action
+	isFabricIngressFilteringFwdClassifierTableAction()
+}
+
+// FabricIngressFilteringFwdClassifierEntry is an entry of table FabricIngress.filtering.fwd_classifier
+type FabricIngressFilteringFwdClassifierEntry struct {
+	IgPort          uint64
+	EthDst          *Ternary[uint64]
+	EthType         *Ternary[uint64]
+	IpEthType       uint64
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressFilteringFwdClassifierTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressFilteringFwdClassifierEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressFilteringFwdClassifierTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.IgPort, 32, "ig_port")}}})
+		if e.EthDst != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 2, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.EthDst.Value, 48, "eth_dst"), Mask: encodeField(enc, e.EthDst.Mask, 48, "eth_dst")}}})
+		}
+		if e.EthType != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 3, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.EthType.Value, 16, "eth_type"), Mask: encodeField(enc, e.EthType.Mask, 16, "eth_type")}}})
+		}
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 4, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.IpEthType, 16, "ip_eth_type")}}})
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressFilteringFwdClassifierEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressFilteringFwdClassifierEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressFilteringIngressPortVlanTableAction is an action allowed for table FabricIngress.filtering.ingress_port_vlan
+type FabricIngressFilteringIngressPortVlanTableAction interface {
+	Action
+	isFabricIngressFilteringIngressPortVlanTableAction()
+}
+
+// FabricIngressFilteringIngressPortVlanEntry is an entry of table FabricIngress.filtering.ingress_port_vlan
+type FabricIngressFilteringIngressPortVlanEntry struct {
+	IgPort          uint64
+	VlanIsValid     uint64
+	VlanId          *Ternary[uint64]
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressFilteringIngressPortVlanTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressFilteringIngressPortVlanEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressFilteringIngressPortVlanTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.IgPort, 32, "ig_port")}}})
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 2, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.VlanIsValid, 1, "vlan_is_valid")}}})
+		if e.VlanId != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 3, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.VlanId.Value, 12, "vlan_id"), Mask: encodeField(enc, e.VlanId.Mask, 12, "vlan_id")}}})
+		}
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressFilteringIngressPortVlanEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressFilteringIngressPortVlanEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressForwardingBridgingTableAction is an action allowed for table FabricIngress.forwarding.bridging
+type FabricIngressForwardingBridgingTableAction interface {
+	Action
+	isFabricIngressForwardingBridgingTableAction()
+}
+
+// FabricIngressForwardingBridgingEntry is an entry of table FabricIngress.forwarding.bridging
+type FabricIngressForwardingBridgingEntry struct {
+	VlanId          uint64
+	EthDst          *Ternary[uint64]
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressForwardingBridgingTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressForwardingBridgingEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressForwardingBridgingTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.VlanId, 12, "vlan_id")}}})
+		if e.EthDst != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 2, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.EthDst.Value, 48, "eth_dst"), Mask: encodeField(enc, e.EthDst.Mask, 48, "eth_dst")}}})
+		}
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressForwardingBridgingEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressForwardingBridgingEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressForwardingMplsTableAction is an action allowed for table FabricIngress.forwarding.mpls
+type FabricIngressForwardingMplsTableAction interface {
+	Action
+	isFabricIngressForwardingMplsTableAction()
+}
+
+// FabricIngressForwardingMplsEntry is an entry of table FabricIngress.forwarding.mpls
+type FabricIngressForwardingMplsEntry struct {
+	MplsLabel       uint64
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressForwardingMplsTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressForwardingMplsEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressForwardingMplsTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.MplsLabel, 20, "mpls_label")}}})
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressForwardingMplsEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressForwardingMplsEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressForwardingRoutingV4TableAction is an action allowed for table FabricIngress.forwarding.routing_v4
+type FabricIngressForwardingRoutingV4TableAction interface {
+	Action
+	isFabricIngressForwardingRoutingV4TableAction()
+}
+
+// FabricIngressForwardingRoutingV4Entry is an entry of table FabricIngress.forwarding.routing_v4
+type FabricIngressForwardingRoutingV4Entry struct {
+	Ipv4Dst         *LPM[uint64]
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressForwardingRoutingV4TableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressForwardingRoutingV4Entry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressForwardingRoutingV4TableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		if e.Ipv4Dst != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Lpm{Lpm: &p4api.FieldMatch_LPM{Value: encodeField(enc, e.Ipv4Dst.Value, 32, "ipv4_dst"), PrefixLen: e.Ipv4Dst.PrefixLen}}})
+		}
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressForwardingRoutingV4Entry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressForwardingRoutingV4Entry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressForwardingRoutingV6TableAction is an action allowed for table FabricIngress.forwarding.routing_v6
+type FabricIngressForwardingRoutingV6TableAction interface {
+	Action
+	isFabricIngressForwardingRoutingV6TableAction()
+}
+
+// FabricIngressForwardingRoutingV6Entry is an entry of table FabricIngress.forwarding.routing_v6
+type FabricIngressForwardingRoutingV6Entry struct {
+	Ipv6Dst         *LPM[[]byte]
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressForwardingRoutingV6TableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressForwardingRoutingV6Entry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressForwardingRoutingV6TableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		if e.Ipv6Dst != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Lpm{Lpm: &p4api.FieldMatch_LPM{Value: encodeField(enc, e.Ipv6Dst.Value, 128, "ipv6_dst"), PrefixLen: e.Ipv6Dst.PrefixLen}}})
+		}
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressForwardingRoutingV6Entry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressForwardingRoutingV6Entry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressNextHashedTableAction is an action allowed for table FabricIngress.next.hashed
+type FabricIngressNextHashedTableAction interface {
+	Action
+	isFabricIngressNextHashedTableAction()
+}
+
+// FabricIngressNextHashedEntry is an entry of table FabricIngress.next.hashed
+type FabricIngressNextHashedEntry struct {
+	NextId          uint64
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressNextHashedTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressNextHashedEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressNextHashedTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.NextId, 32, "next_id")}}})
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressNextHashedEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressNextHashedEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressNextMulticastTableAction is an action allowed for table FabricIngress.next.multicast
+type FabricIngressNextMulticastTableAction interface {
+	Action
+	isFabricIngressNextMulticastTableAction()
+}
+
+// FabricIngressNextMulticastEntry is an entry of table FabricIngress.next.multicast
+type FabricIngressNextMulticastEntry struct {
+	NextId          uint64
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressNextMulticastTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressNextMulticastEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressNextMulticastTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.NextId, 32, "next_id")}}})
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressNextMulticastEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressNextMulticastEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressPktIoIgSwitchInfoTableAction is an action allowed for table FabricIngress.pkt_io.ig_switch_info
+type FabricIngressPktIoIgSwitchInfoTableAction interface {
+	Action
+	isFabricIngressPktIoIgSwitchInfoTableAction()
+}
+
+// FabricIngressPktIoIgSwitchInfoEntry is an entry of table FabricIngress.pkt_io.ig_switch_info
+type FabricIngressPktIoIgSwitchInfoEntry struct {
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressPktIoIgSwitchInfoTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressPktIoIgSwitchInfoEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressPktIoIgSwitchInfoTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressPktIoIgSwitchInfoEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressPktIoIgSwitchInfoEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressPreNextNextMplsTableAction is an action allowed for table FabricIngress.pre_next.next_mpls
+type FabricIngressPreNextNextMplsTableAction interface {
+	Action
+	isFabricIngressPreNextNextMplsTableAction()
+}
+
+// FabricIngressPreNextNextMplsEntry is an entry of table FabricIngress.pre_next.next_mpls
+type FabricIngressPreNextNextMplsEntry struct {
+	NextId          uint64
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressPreNextNextMplsTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressPreNextNextMplsEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressPreNextNextMplsTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.NextId, 32, "next_id")}}})
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressPreNextNextMplsEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressPreNextNextMplsEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressPreNextNextVlanTableAction is an action allowed for table FabricIngress.pre_next.next_vlan
+type FabricIngressPreNextNextVlanTableAction interface {
+	Action
+	isFabricIngressPreNextNextVlanTableAction()
+}
+
+// FabricIngressPreNextNextVlanEntry is an entry of table FabricIngress.pre_next.next_vlan
+type FabricIngressPreNextNextVlanEntry struct {
+	NextId          uint64
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressPreNextNextVlanTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressPreNextNextVlanEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressPreNextNextVlanTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.NextId, 32, "next_id")}}})
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressPreNextNextVlanEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressPreNextNextVlanEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressQosDefaultTcTableAction is an action allowed for table FabricIngress.qos.default_tc
+type FabricIngressQosDefaultTcTableAction interface {
+	Action
+	isFabricIngressQosDefaultTcTableAction()
+}
+
+// FabricIngressQosDefaultTcEntry is an entry of table FabricIngress.qos.default_tc
+type FabricIngressQosDefaultTcEntry struct {
+	SliceTc         *Ternary[uint64]
+	TcUnknown       uint64
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressQosDefaultTcTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressQosDefaultTcEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressQosDefaultTcTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		if e.SliceTc != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.SliceTc.Value, 6, "slice_tc"), Mask: encodeField(enc, e.SliceTc.Mask, 6, "slice_tc")}}})
+		}
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 2, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.TcUnknown, 1, "tc_unknown")}}})
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressQosDefaultTcEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressQosDefaultTcEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressQosQueuesTableAction is an action allowed for table FabricIngress.qos.queues
+type FabricIngressQosQueuesTableAction interface {
+	Action
+	isFabricIngressQosQueuesTableAction()
+}
+
+// FabricIngressQosQueuesEntry is an entry of table FabricIngress.qos.queues
+type FabricIngressQosQueuesEntry struct {
+	SliceTc         uint64
+	Color           *Ternary[uint64]
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressQosQueuesTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressQosQueuesEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressQosQueuesTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.SliceTc, 6, "slice_tc")}}})
+		if e.Color != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 2, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.Color.Value, 2, "color"), Mask: encodeField(enc, e.Color.Mask, 2, "color")}}})
+		}
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressQosQueuesEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressQosQueuesEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressSliceTcClassifierClassifierTableAction is an action allowed for table FabricIngress.slice_tc_classifier.classifier
+type FabricIngressSliceTcClassifierClassifierTableAction interface {
+	Action
+	isFabricIngressSliceTcClassifierClassifierTableAction()
+}
+
+// FabricIngressSliceTcClassifierClassifierEntry is an entry of table FabricIngress.slice_tc_classifier.classifier
+type FabricIngressSliceTcClassifierClassifierEntry struct {
+	IgPort          *Ternary[uint64]
+	Ipv4Src         *Ternary[uint64]
+	Ipv4Dst         *Ternary[uint64]
+	IpProto         *Ternary[uint64]
+	L4Sport         *Ternary[uint64]
+	L4Dport         *Ternary[uint64]
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressSliceTcClassifierClassifierTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressSliceTcClassifierClassifierEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressSliceTcClassifierClassifierTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		if e.IgPort != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.IgPort.Value, 32, "ig_port"), Mask: encodeField(enc, e.IgPort.Mask, 32, "ig_port")}}})
+		}
+		if e.Ipv4Src != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 2, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.Ipv4Src.Value, 32, "ipv4_src"), Mask: encodeField(enc, e.Ipv4Src.Mask, 32, "ipv4_src")}}})
+		}
+		if e.Ipv4Dst != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 3, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.Ipv4Dst.Value, 32, "ipv4_dst"), Mask: encodeField(enc, e.Ipv4Dst.Mask, 32, "ipv4_dst")}}})
+		}
+		if e.IpProto != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 4, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.IpProto.Value, 8, "ip_proto"), Mask: encodeField(enc, e.IpProto.Mask, 8, "ip_proto")}}})
+		}
+		if e.L4Sport != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 5, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.L4Sport.Value, 16, "l4_sport"), Mask: encodeField(enc, e.L4Sport.Mask, 16, "l4_sport")}}})
+		}
+		if e.L4Dport != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 6, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.L4Dport.Value, 16, "l4_dport"), Mask: encodeField(enc, e.L4Dport.Mask, 16, "l4_dport")}}})
+		}
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressSliceTcClassifierClassifierEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressSliceTcClassifierClassifierEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricIngressStatsFlowsTableAction is an action allowed for table FabricIngress.stats.flows
+type FabricIngressStatsFlowsTableAction interface {
+	Action
+	isFabricIngressStatsFlowsTableAction()
+}
+
+// FabricIngressStatsFlowsEntry is an entry of table FabricIngress.stats.flows
+type FabricIngressStatsFlowsEntry struct {
+	Ipv4Src         *Ternary[uint64]
+	Ipv4Dst         *Ternary[uint64]
+	IpProto         *Ternary[uint64]
+	L4Sport         *Ternary[uint64]
+	L4Dport         *Ternary[uint64]
+	IgPort          uint64
+	Priority        int32
+	IsDefaultAction bool
+	Action          FabricIngressStatsFlowsTableAction
+}
+
+// TableEntry returns the P4Runtime representation of the entry; the default entry has no matches. Returns error
+// if any of the values does not fit in the bitwidth of its match field or action parameter.
+func (e *FabricIngressStatsFlowsEntry) TableEntry() (*p4api.TableEntry, error) {
+	entry := &p4api.TableEntry{TableId: FabricIngressStatsFlowsTableID, Priority: e.Priority, IsDefaultAction: e.IsDefaultAction}
+	enc := &encoder{}
+	if !e.IsDefaultAction {
+		if e.Ipv4Src != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 1, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.Ipv4Src.Value, 32, "ipv4_src"), Mask: encodeField(enc, e.Ipv4Src.Mask, 32, "ipv4_src")}}})
+		}
+		if e.Ipv4Dst != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 2, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.Ipv4Dst.Value, 32, "ipv4_dst"), Mask: encodeField(enc, e.Ipv4Dst.Mask, 32, "ipv4_dst")}}})
+		}
+		if e.IpProto != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 3, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.IpProto.Value, 8, "ip_proto"), Mask: encodeField(enc, e.IpProto.Mask, 8, "ip_proto")}}})
+		}
+		if e.L4Sport != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 4, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.L4Sport.Value, 16, "l4_sport"), Mask: encodeField(enc, e.L4Sport.Mask, 16, "l4_sport")}}})
+		}
+		if e.L4Dport != nil {
+			entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 5, FieldMatchType: &p4api.FieldMatch_Ternary_{Ternary: &p4api.FieldMatch_Ternary{Value: encodeField(enc, e.L4Dport.Value, 16, "l4_dport"), Mask: encodeField(enc, e.L4Dport.Mask, 16, "l4_dport")}}})
+		}
+		entry.Match = append(entry.Match, &p4api.FieldMatch{FieldId: 6, FieldMatchType: &p4api.FieldMatch_Exact_{Exact: &p4api.FieldMatch_Exact{Value: encodeField(enc, e.IgPort, 32, "ig_port")}}})
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	if e.Action != nil {
+		action, err := e.Action.P4Action()
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = &p4api.TableAction{Type: &p4api.TableAction_Action{Action: action}}
+	}
+	return entry, nil
+}
+
+// Entity returns the entry as a P4Runtime entity
+func (e *FabricIngressStatsFlowsEntry) Entity() (*p4api.Entity, error) {
+	entry, err := e.TableEntry()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Entity{Entity: &p4api.Entity_TableEntry{TableEntry: entry}}, nil
+}
+
+// Update returns an update of the given type carrying the entry
+func (e *FabricIngressStatsFlowsEntry) Update(updateType p4api.Update_Type) (*p4api.Update, error) {
+	entity, err := e.Entity()
+	if err != nil {
+		return nil, err
+	}
+	return &p4api.Update{Type: updateType, Entity: entity}, nil
+}
+
+// FabricEgressDscpRewriterClearAction is action FabricEgress.dscp_rewriter.clear
+type FabricEgressDscpRewriterClearAction struct {
+}
+
+// NewFabricEgressDscpRewriterClearAction returns action FabricEgress.dscp_rewriter.clear with the given parameters
+func NewFabricEgressDscpRewriterClearAction() *FabricEgressDscpRewriterClearAction {
+	return &FabricEgressDscpRewriterClearAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricEgressDscpRewriterClearAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricEgressDscpRewriterClearActionID}
+	return action, nil
+}
+
+func (a *FabricEgressDscpRewriterClearAction) isFabricEgressDscpRewriterRewriterTableAction() {}
+
+// FabricEgressDscpRewriterRewriteAction is action FabricEgress.dscp_rewriter.rewrite
+type FabricEgressDscpRewriterRewriteAction struct {
+}
+
+// NewFabricEgressDscpRewriterRewriteAction returns action FabricEgress.dscp_rewriter.rewrite with the given parameters
+func NewFabricEgressDscpRewriterRewriteAction() *FabricEgressDscpRewriterRewriteAction {
+	return &FabricEgressDscpRewriterRewriteAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricEgressDscpRewriterRewriteAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricEgressDscpRewriterRewriteActionID}
+	return action, nil
+}
+
+func (a *FabricEgressDscpRewriterRewriteAction) isFabricEgressDscpRewriterRewriterTableAction() {}
+
+// FabricEgressEgressNextDropAction is action FabricEgress.egress_next.drop
+type FabricEgressEgressNextDropAction struct {
+}
+
+// NewFabricEgressEgressNextDropAction returns action FabricEgress.egress_next.drop with the given parameters
+func NewFabricEgressEgressNextDropAction() *FabricEgressEgressNextDropAction {
+	return &FabricEgressEgressNextDropAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricEgressEgressNextDropAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricEgressEgressNextDropActionID}
+	return action, nil
+}
+
+func (a *FabricEgressEgressNextDropAction) isFabricEgressEgressNextEgressVlanTableAction() {}
+
+// FabricEgressEgressNextPopVlanAction is action FabricEgress.egress_next.pop_vlan
+type FabricEgressEgressNextPopVlanAction struct {
+}
+
+// NewFabricEgressEgressNextPopVlanAction returns action FabricEgress.egress_next.pop_vlan with the given parameters
+func NewFabricEgressEgressNextPopVlanAction() *FabricEgressEgressNextPopVlanAction {
+	return &FabricEgressEgressNextPopVlanAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricEgressEgressNextPopVlanAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricEgressEgressNextPopVlanActionID}
+	return action, nil
+}
+
+func (a *FabricEgressEgressNextPopVlanAction) isFabricEgressEgressNextEgressVlanTableAction() {}
+
+// FabricEgressEgressNextPushVlanAction is action FabricEgress.egress_next.push_vlan
+type FabricEgressEgressNextPushVlanAction struct {
+}
+
+// NewFabricEgressEgressNextPushVlanAction returns action FabricEgress.egress_next.push_vlan with the given parameters
+func NewFabricEgressEgressNextPushVlanAction() *FabricEgressEgressNextPushVlanAction {
+	return &FabricEgressEgressNextPushVlanAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricEgressEgressNextPushVlanAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricEgressEgressNextPushVlanActionID}
+	return action, nil
+}
+
+func (a *FabricEgressEgressNextPushVlanAction) isFabricEgressEgressNextEgressVlanTableAction() {}
+
+// FabricEgressPktIoEgressSetSwitchInfoAction is action FabricEgress.pkt_io_egress.set_switch_info
+type FabricEgressPktIoEgressSetSwitchInfoAction struct {
+	CpuPort uint64
+}
+
+// NewFabricEgressPktIoEgressSetSwitchInfoAction returns action FabricEgress.pkt_io_egress.set_switch_info with the given parameters
+func NewFabricEgressPktIoEgressSetSwitchInfoAction(cpuPort uint64) *FabricEgressPktIoEgressSetSwitchInfoAction {
+	return &FabricEgressPktIoEgressSetSwitchInfoAction{CpuPort: cpuPort}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricEgressPktIoEgressSetSwitchInfoAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricEgressPktIoEgressSetSwitchInfoActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.CpuPort, 32, "cpu_port")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricEgressPktIoEgressSetSwitchInfoAction) isFabricEgressPktIoEgressSwitchInfoTableAction() {
+}
+
+// FabricEgressStatsCountAction is action FabricEgress.stats.count
+type FabricEgressStatsCountAction struct {
+}
+
+// NewFabricEgressStatsCountAction returns action FabricEgress.stats.count with the given parameters
+func NewFabricEgressStatsCountAction() *FabricEgressStatsCountAction {
+	return &FabricEgressStatsCountAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricEgressStatsCountAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricEgressStatsCountActionID}
+	return action, nil
+}
+
+func (a *FabricEgressStatsCountAction) isFabricEgressStatsFlowsTableAction() {}
+
+// FabricIngressAclCopyToCpuAction is action FabricIngress.acl.copy_to_cpu
+type FabricIngressAclCopyToCpuAction struct {
+	SetRoleAgentId uint64
+}
+
+// NewFabricIngressAclCopyToCpuAction returns action FabricIngress.acl.copy_to_cpu with the given parameters
+func NewFabricIngressAclCopyToCpuAction(setRoleAgentId uint64) *FabricIngressAclCopyToCpuAction {
+	return &FabricIngressAclCopyToCpuAction{SetRoleAgentId: setRoleAgentId}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressAclCopyToCpuAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressAclCopyToCpuActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.SetRoleAgentId, 4, "set_role_agent_id")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressAclCopyToCpuAction) isFabricIngressAclAclTableAction() {}
+
+// FabricIngressAclDropAction is action FabricIngress.acl.drop
+type FabricIngressAclDropAction struct {
+}
+
+// NewFabricIngressAclDropAction returns action FabricIngress.acl.drop with the given parameters
+func NewFabricIngressAclDropAction() *FabricIngressAclDropAction {
+	return &FabricIngressAclDropAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressAclDropAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressAclDropActionID}
+	return action, nil
+}
+
+func (a *FabricIngressAclDropAction) isFabricIngressAclAclTableAction() {}
+
+// FabricIngressAclNopAclAction is action FabricIngress.acl.nop_acl
+type FabricIngressAclNopAclAction struct {
+}
+
+// NewFabricIngressAclNopAclAction returns action FabricIngress.acl.nop_acl with the given parameters
+func NewFabricIngressAclNopAclAction() *FabricIngressAclNopAclAction {
+	return &FabricIngressAclNopAclAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressAclNopAclAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressAclNopAclActionID}
+	return action, nil
+}
+
+func (a *FabricIngressAclNopAclAction) isFabricIngressAclAclTableAction() {}
+
+// FabricIngressAclPuntToCpuAction is action FabricIngress.acl.punt_to_cpu
+type FabricIngressAclPuntToCpuAction struct {
+	SetRoleAgentId uint64
+}
+
+// NewFabricIngressAclPuntToCpuAction returns action FabricIngress.acl.punt_to_cpu with the given parameters
+func NewFabricIngressAclPuntToCpuAction(setRoleAgentId uint64) *FabricIngressAclPuntToCpuAction {
+	return &FabricIngressAclPuntToCpuAction{SetRoleAgentId: setRoleAgentId}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressAclPuntToCpuAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressAclPuntToCpuActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.SetRoleAgentId, 4, "set_role_agent_id")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressAclPuntToCpuAction) isFabricIngressAclAclTableAction() {}
+
+// FabricIngressAclSetNextIdAclAction is action FabricIngress.acl.set_next_id_acl
+type FabricIngressAclSetNextIdAclAction struct {
+	NextId uint64
+}
+
+// NewFabricIngressAclSetNextIdAclAction returns action FabricIngress.acl.set_next_id_acl with the given parameters
+func NewFabricIngressAclSetNextIdAclAction(nextId uint64) *FabricIngressAclSetNextIdAclAction {
+	return &FabricIngressAclSetNextIdAclAction{NextId: nextId}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressAclSetNextIdAclAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressAclSetNextIdAclActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.NextId, 32, "next_id")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressAclSetNextIdAclAction) isFabricIngressAclAclTableAction() {}
+
+// FabricIngressAclSetOutputPortAction is action FabricIngress.acl.set_output_port
+type FabricIngressAclSetOutputPortAction struct {
+	PortNum uint64
+}
+
+// NewFabricIngressAclSetOutputPortAction returns action FabricIngress.acl.set_output_port with the given parameters
+func NewFabricIngressAclSetOutputPortAction(portNum uint64) *FabricIngressAclSetOutputPortAction {
+	return &FabricIngressAclSetOutputPortAction{PortNum: portNum}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressAclSetOutputPortAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressAclSetOutputPortActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.PortNum, 32, "port_num")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressAclSetOutputPortAction) isFabricIngressAclAclTableAction() {}
+
+// FabricIngressFilteringDenyAction is action FabricIngress.filtering.deny
+type FabricIngressFilteringDenyAction struct {
+}
+
+// NewFabricIngressFilteringDenyAction returns action FabricIngress.filtering.deny with the given parameters
+func NewFabricIngressFilteringDenyAction() *FabricIngressFilteringDenyAction {
+	return &FabricIngressFilteringDenyAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressFilteringDenyAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressFilteringDenyActionID}
+	return action, nil
+}
+
+func (a *FabricIngressFilteringDenyAction) isFabricIngressFilteringIngressPortVlanTableAction() {}
+
+// FabricIngressFilteringPermitAction is action FabricIngress.filtering.permit
+type FabricIngressFilteringPermitAction struct {
+	PortType uint64
+}
+
+// NewFabricIngressFilteringPermitAction returns action FabricIngress.filtering.permit with the given parameters
+func NewFabricIngressFilteringPermitAction(portType uint64) *FabricIngressFilteringPermitAction {
+	return &FabricIngressFilteringPermitAction{PortType: portType}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressFilteringPermitAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressFilteringPermitActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.PortType, 2, "port_type")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressFilteringPermitAction) isFabricIngressFilteringIngressPortVlanTableAction() {}
+
+// FabricIngressFilteringPermitWithInternalVlanAction is action FabricIngress.filtering.permit_with_internal_vlan
+type FabricIngressFilteringPermitWithInternalVlanAction struct {
+	VlanId   uint64
+	PortType uint64
+}
+
+// NewFabricIngressFilteringPermitWithInternalVlanAction returns action FabricIngress.filtering.permit_with_internal_vlan with the given parameters
+func NewFabricIngressFilteringPermitWithInternalVlanAction(vlanId uint64, portType uint64) *FabricIngressFilteringPermitWithInternalVlanAction {
+	return &FabricIngressFilteringPermitWithInternalVlanAction{VlanId: vlanId, PortType: portType}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressFilteringPermitWithInternalVlanAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressFilteringPermitWithInternalVlanActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.VlanId, 12, "vlan_id")})
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 2, Value: encodeField(enc, a.PortType, 2, "port_type")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressFilteringPermitWithInternalVlanAction) isFabricIngressFilteringIngressPortVlanTableAction() {
+}
+
+// FabricIngressFilteringSetForwardingTypeAction is action FabricIngress.filtering.set_forwarding_type
+type FabricIngressFilteringSetForwardingTypeAction struct {
+	FwdType uint64
+}
+
+// NewFabricIngressFilteringSetForwardingTypeAction returns action FabricIngress.filtering.set_forwarding_type with the given parameters
+func NewFabricIngressFilteringSetForwardingTypeAction(fwdType uint64) *FabricIngressFilteringSetForwardingTypeAction {
+	return &FabricIngressFilteringSetForwardingTypeAction{FwdType: fwdType}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressFilteringSetForwardingTypeAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressFilteringSetForwardingTypeActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.FwdType, 3, "fwd_type")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressFilteringSetForwardingTypeAction) isFabricIngressFilteringFwdClassifierTableAction() {
+}
+
+// FabricIngressForwardingDropRoutingV4Action is action FabricIngress.forwarding.drop_routing_v4
+type FabricIngressForwardingDropRoutingV4Action struct {
+}
+
+// NewFabricIngressForwardingDropRoutingV4Action returns action FabricIngress.forwarding.drop_routing_v4 with the given parameters
+func NewFabricIngressForwardingDropRoutingV4Action() *FabricIngressForwardingDropRoutingV4Action {
+	return &FabricIngressForwardingDropRoutingV4Action{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressForwardingDropRoutingV4Action) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressForwardingDropRoutingV4ActionID}
+	return action, nil
+}
+
+func (a *FabricIngressForwardingDropRoutingV4Action) isFabricIngressForwardingRoutingV4TableAction() {
+}
+
+// FabricIngressForwardingDropRoutingV6Action is action FabricIngress.forwarding.drop_routing_v6
+type FabricIngressForwardingDropRoutingV6Action struct {
+}
+
+// NewFabricIngressForwardingDropRoutingV6Action returns action FabricIngress.forwarding.drop_routing_v6 with the given parameters
+func NewFabricIngressForwardingDropRoutingV6Action() *FabricIngressForwardingDropRoutingV6Action {
+	return &FabricIngressForwardingDropRoutingV6Action{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressForwardingDropRoutingV6Action) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressForwardingDropRoutingV6ActionID}
+	return action, nil
+}
+
+func (a *FabricIngressForwardingDropRoutingV6Action) isFabricIngressForwardingRoutingV6TableAction() {
+}
+
+// FabricIngressForwardingNopRoutingV4Action is action FabricIngress.forwarding.nop_routing_v4
+type FabricIngressForwardingNopRoutingV4Action struct {
+}
+
+// NewFabricIngressForwardingNopRoutingV4Action returns action FabricIngress.forwarding.nop_routing_v4 with the given parameters
+func NewFabricIngressForwardingNopRoutingV4Action() *FabricIngressForwardingNopRoutingV4Action {
+	return &FabricIngressForwardingNopRoutingV4Action{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressForwardingNopRoutingV4Action) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressForwardingNopRoutingV4ActionID}
+	return action, nil
+}
+
+func (a *FabricIngressForwardingNopRoutingV4Action) isFabricIngressForwardingRoutingV4TableAction() {}
+
+// FabricIngressForwardingPopMplsAndNextAction is action FabricIngress.forwarding.pop_mpls_and_next
+type FabricIngressForwardingPopMplsAndNextAction struct {
+	NextId uint64
+}
+
+// NewFabricIngressForwardingPopMplsAndNextAction returns action FabricIngress.forwarding.pop_mpls_and_next with the given parameters
+func NewFabricIngressForwardingPopMplsAndNextAction(nextId uint64) *FabricIngressForwardingPopMplsAndNextAction {
+	return &FabricIngressForwardingPopMplsAndNextAction{NextId: nextId}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressForwardingPopMplsAndNextAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressForwardingPopMplsAndNextActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.NextId, 32, "next_id")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressForwardingPopMplsAndNextAction) isFabricIngressForwardingMplsTableAction() {}
+
+// FabricIngressForwardingSetNextIdBridgingAction is action FabricIngress.forwarding.set_next_id_bridging
+type FabricIngressForwardingSetNextIdBridgingAction struct {
+	NextId uint64
+}
+
+// NewFabricIngressForwardingSetNextIdBridgingAction returns action FabricIngress.forwarding.set_next_id_bridging with the given parameters
+func NewFabricIngressForwardingSetNextIdBridgingAction(nextId uint64) *FabricIngressForwardingSetNextIdBridgingAction {
+	return &FabricIngressForwardingSetNextIdBridgingAction{NextId: nextId}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressForwardingSetNextIdBridgingAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressForwardingSetNextIdBridgingActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.NextId, 32, "next_id")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressForwardingSetNextIdBridgingAction) isFabricIngressForwardingBridgingTableAction() {
+}
+
+// FabricIngressForwardingSetNextIdRoutingV4Action is action FabricIngress.forwarding.set_next_id_routing_v4
+type FabricIngressForwardingSetNextIdRoutingV4Action struct {
+	NextId uint64
+}
+
+// NewFabricIngressForwardingSetNextIdRoutingV4Action returns action FabricIngress.forwarding.set_next_id_routing_v4 with the given parameters
+func NewFabricIngressForwardingSetNextIdRoutingV4Action(nextId uint64) *FabricIngressForwardingSetNextIdRoutingV4Action {
+	return &FabricIngressForwardingSetNextIdRoutingV4Action{NextId: nextId}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressForwardingSetNextIdRoutingV4Action) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressForwardingSetNextIdRoutingV4ActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.NextId, 32, "next_id")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressForwardingSetNextIdRoutingV4Action) isFabricIngressForwardingRoutingV4TableAction() {
+}
+
+// FabricIngressForwardingSetNextIdRoutingV6Action is action FabricIngress.forwarding.set_next_id_routing_v6
+type FabricIngressForwardingSetNextIdRoutingV6Action struct {
+	NextId uint64
+}
+
+// NewFabricIngressForwardingSetNextIdRoutingV6Action returns action FabricIngress.forwarding.set_next_id_routing_v6 with the given parameters
+func NewFabricIngressForwardingSetNextIdRoutingV6Action(nextId uint64) *FabricIngressForwardingSetNextIdRoutingV6Action {
+	return &FabricIngressForwardingSetNextIdRoutingV6Action{NextId: nextId}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressForwardingSetNextIdRoutingV6Action) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressForwardingSetNextIdRoutingV6ActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.NextId, 32, "next_id")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressForwardingSetNextIdRoutingV6Action) isFabricIngressForwardingRoutingV6TableAction() {
+}
+
+// FabricIngressNextOutputHashedAction is action FabricIngress.next.output_hashed
+type FabricIngressNextOutputHashedAction struct {
+	PortNum uint64
+}
+
+// NewFabricIngressNextOutputHashedAction returns action FabricIngress.next.output_hashed with the given parameters
+func NewFabricIngressNextOutputHashedAction(portNum uint64) *FabricIngressNextOutputHashedAction {
+	return &FabricIngressNextOutputHashedAction{PortNum: portNum}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressNextOutputHashedAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressNextOutputHashedActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.PortNum, 32, "port_num")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressNextOutputHashedAction) isFabricIngressNextHashedTableAction() {}
+
+// FabricIngressNextResetMcastGroupIdAction is action FabricIngress.next.reset_mcast_group_id
+type FabricIngressNextResetMcastGroupIdAction struct {
+}
+
+// NewFabricIngressNextResetMcastGroupIdAction returns action FabricIngress.next.reset_mcast_group_id with the given parameters
+func NewFabricIngressNextResetMcastGroupIdAction() *FabricIngressNextResetMcastGroupIdAction {
+	return &FabricIngressNextResetMcastGroupIdAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressNextResetMcastGroupIdAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressNextResetMcastGroupIdActionID}
+	return action, nil
+}
+
+func (a *FabricIngressNextResetMcastGroupIdAction) isFabricIngressNextMulticastTableAction() {}
+
+// FabricIngressNextRoutingHashedAction is action FabricIngress.next.routing_hashed
+type FabricIngressNextRoutingHashedAction struct {
+	PortNum uint64
+	Smac    uint64
+	Dmac    uint64
+}
+
+// NewFabricIngressNextRoutingHashedAction returns action FabricIngress.next.routing_hashed with the given parameters
+func NewFabricIngressNextRoutingHashedAction(portNum uint64, smac uint64, dmac uint64) *FabricIngressNextRoutingHashedAction {
+	return &FabricIngressNextRoutingHashedAction{PortNum: portNum, Smac: smac, Dmac: dmac}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressNextRoutingHashedAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressNextRoutingHashedActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.PortNum, 32, "port_num")})
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 2, Value: encodeField(enc, a.Smac, 48, "smac")})
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 3, Value: encodeField(enc, a.Dmac, 48, "dmac")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressNextRoutingHashedAction) isFabricIngressNextHashedTableAction() {}
+
+// FabricIngressNextSetMcastGroupIdAction is action FabricIngress.next.set_mcast_group_id
+type FabricIngressNextSetMcastGroupIdAction struct {
+	GroupId uint64
+}
+
+// NewFabricIngressNextSetMcastGroupIdAction returns action FabricIngress.next.set_mcast_group_id with the given parameters
+func NewFabricIngressNextSetMcastGroupIdAction(groupId uint64) *FabricIngressNextSetMcastGroupIdAction {
+	return &FabricIngressNextSetMcastGroupIdAction{GroupId: groupId}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressNextSetMcastGroupIdAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressNextSetMcastGroupIdActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.GroupId, 16, "group_id")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressNextSetMcastGroupIdAction) isFabricIngressNextMulticastTableAction() {}
+
+// FabricIngressPktIoSetSwitchInfoAction is action FabricIngress.pkt_io.set_switch_info
+type FabricIngressPktIoSetSwitchInfoAction struct {
+	EthCpuPort uint64
+}
+
+// NewFabricIngressPktIoSetSwitchInfoAction returns action FabricIngress.pkt_io.set_switch_info with the given parameters
+func NewFabricIngressPktIoSetSwitchInfoAction(ethCpuPort uint64) *FabricIngressPktIoSetSwitchInfoAction {
+	return &FabricIngressPktIoSetSwitchInfoAction{EthCpuPort: ethCpuPort}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressPktIoSetSwitchInfoAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressPktIoSetSwitchInfoActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.EthCpuPort, 32, "eth_cpu_port")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressPktIoSetSwitchInfoAction) isFabricIngressPktIoIgSwitchInfoTableAction() {}
+
+// FabricIngressPreNextSetMplsLabelAction is action FabricIngress.pre_next.set_mpls_label
+type FabricIngressPreNextSetMplsLabelAction struct {
+	Label uint64
+}
+
+// NewFabricIngressPreNextSetMplsLabelAction returns action FabricIngress.pre_next.set_mpls_label with the given parameters
+func NewFabricIngressPreNextSetMplsLabelAction(label uint64) *FabricIngressPreNextSetMplsLabelAction {
+	return &FabricIngressPreNextSetMplsLabelAction{Label: label}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressPreNextSetMplsLabelAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressPreNextSetMplsLabelActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.Label, 20, "label")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressPreNextSetMplsLabelAction) isFabricIngressPreNextNextMplsTableAction() {}
+
+// FabricIngressPreNextSetVlanAction is action FabricIngress.pre_next.set_vlan
+type FabricIngressPreNextSetVlanAction struct {
+	VlanId uint64
+}
+
+// NewFabricIngressPreNextSetVlanAction returns action FabricIngress.pre_next.set_vlan with the given parameters
+func NewFabricIngressPreNextSetVlanAction(vlanId uint64) *FabricIngressPreNextSetVlanAction {
+	return &FabricIngressPreNextSetVlanAction{VlanId: vlanId}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressPreNextSetVlanAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressPreNextSetVlanActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.VlanId, 12, "vlan_id")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressPreNextSetVlanAction) isFabricIngressPreNextNextVlanTableAction() {}
+
+// FabricIngressQosMeterDropAction is action FabricIngress.qos.meter_drop
+type FabricIngressQosMeterDropAction struct {
+}
+
+// NewFabricIngressQosMeterDropAction returns action FabricIngress.qos.meter_drop with the given parameters
+func NewFabricIngressQosMeterDropAction() *FabricIngressQosMeterDropAction {
+	return &FabricIngressQosMeterDropAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressQosMeterDropAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressQosMeterDropActionID}
+	return action, nil
+}
+
+func (a *FabricIngressQosMeterDropAction) isFabricIngressQosQueuesTableAction() {}
+
+// FabricIngressQosSetDefaultTcAction is action FabricIngress.qos.set_default_tc
+type FabricIngressQosSetDefaultTcAction struct {
+	Tc uint64
+}
+
+// NewFabricIngressQosSetDefaultTcAction returns action FabricIngress.qos.set_default_tc with the given parameters
+func NewFabricIngressQosSetDefaultTcAction(tc uint64) *FabricIngressQosSetDefaultTcAction {
+	return &FabricIngressQosSetDefaultTcAction{Tc: tc}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressQosSetDefaultTcAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressQosSetDefaultTcActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.Tc, 2, "tc")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressQosSetDefaultTcAction) isFabricIngressQosDefaultTcTableAction() {}
+
+// FabricIngressQosSetQueueAction is action FabricIngress.qos.set_queue
+type FabricIngressQosSetQueueAction struct {
+	Qid uint64
+}
+
+// NewFabricIngressQosSetQueueAction returns action FabricIngress.qos.set_queue with the given parameters
+func NewFabricIngressQosSetQueueAction(qid uint64) *FabricIngressQosSetQueueAction {
+	return &FabricIngressQosSetQueueAction{Qid: qid}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressQosSetQueueAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressQosSetQueueActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.Qid, 5, "qid")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressQosSetQueueAction) isFabricIngressQosQueuesTableAction() {}
+
+// FabricIngressSliceTcClassifierNoClassificationAction is action FabricIngress.slice_tc_classifier.no_classification
+type FabricIngressSliceTcClassifierNoClassificationAction struct {
+}
+
+// NewFabricIngressSliceTcClassifierNoClassificationAction returns action FabricIngress.slice_tc_classifier.no_classification with the given parameters
+func NewFabricIngressSliceTcClassifierNoClassificationAction() *FabricIngressSliceTcClassifierNoClassificationAction {
+	return &FabricIngressSliceTcClassifierNoClassificationAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressSliceTcClassifierNoClassificationAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressSliceTcClassifierNoClassificationActionID}
+	return action, nil
+}
+
+func (a *FabricIngressSliceTcClassifierNoClassificationAction) isFabricIngressSliceTcClassifierClassifierTableAction() {
+}
+
+// FabricIngressSliceTcClassifierSetSliceIdTcAction is action FabricIngress.slice_tc_classifier.set_slice_id_tc
+type FabricIngressSliceTcClassifierSetSliceIdTcAction struct {
+	SliceId uint64
+	Tc      uint64
+}
+
+// NewFabricIngressSliceTcClassifierSetSliceIdTcAction returns action FabricIngress.slice_tc_classifier.set_slice_id_tc with the given parameters
+func NewFabricIngressSliceTcClassifierSetSliceIdTcAction(sliceId uint64, tc uint64) *FabricIngressSliceTcClassifierSetSliceIdTcAction {
+	return &FabricIngressSliceTcClassifierSetSliceIdTcAction{SliceId: sliceId, Tc: tc}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressSliceTcClassifierSetSliceIdTcAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressSliceTcClassifierSetSliceIdTcActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.SliceId, 4, "slice_id")})
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 2, Value: encodeField(enc, a.Tc, 2, "tc")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressSliceTcClassifierSetSliceIdTcAction) isFabricIngressSliceTcClassifierClassifierTableAction() {
+}
+
+// FabricIngressSliceTcClassifierTrustDscpAction is action FabricIngress.slice_tc_classifier.trust_dscp
+type FabricIngressSliceTcClassifierTrustDscpAction struct {
+}
+
+// NewFabricIngressSliceTcClassifierTrustDscpAction returns action FabricIngress.slice_tc_classifier.trust_dscp with the given parameters
+func NewFabricIngressSliceTcClassifierTrustDscpAction() *FabricIngressSliceTcClassifierTrustDscpAction {
+	return &FabricIngressSliceTcClassifierTrustDscpAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressSliceTcClassifierTrustDscpAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressSliceTcClassifierTrustDscpActionID}
+	return action, nil
+}
+
+func (a *FabricIngressSliceTcClassifierTrustDscpAction) isFabricIngressSliceTcClassifierClassifierTableAction() {
+}
+
+// FabricIngressStatsCountAction is action FabricIngress.stats.count
+type FabricIngressStatsCountAction struct {
+	FlowId uint64
+}
+
+// NewFabricIngressStatsCountAction returns action FabricIngress.stats.count with the given parameters
+func NewFabricIngressStatsCountAction(flowId uint64) *FabricIngressStatsCountAction {
+	return &FabricIngressStatsCountAction{FlowId: flowId}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *FabricIngressStatsCountAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: FabricIngressStatsCountActionID}
+	enc := &encoder{}
+	action.Params = append(action.Params, &p4api.Action_Param{ParamId: 1, Value: encodeField(enc, a.FlowId, 10, "flow_id")})
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return action, nil
+}
+
+func (a *FabricIngressStatsCountAction) isFabricIngressStatsFlowsTableAction() {}
+
+// NoActionAction is action NoAction
+type NoActionAction struct {
+}
+
+// NewNoActionAction returns action NoAction with the given parameters
+func NewNoActionAction() *NoActionAction {
+	return &NoActionAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *NoActionAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: NoActionActionID}
+	return action, nil
+}
+
+// NopAction is action nop
+type NopAction struct {
+}
+
+// NewNopAction returns action nop with the given parameters
+func NewNopAction() *NopAction {
+	return &NopAction{}
+}
+
+// P4Action returns the P4Runtime representation of the action
+func (a *NopAction) P4Action() (*p4api.Action, error) {
+	action := &p4api.Action{ActionId: NopActionID}
+	return action, nil
+}
+
+func (a *NopAction) isFabricIngressPktIoIgSwitchInfoTableAction()    {}
+func (a *NopAction) isFabricIngressForwardingBridgingTableAction()   {}
+func (a *NopAction) isFabricIngressForwardingMplsTableAction()       {}
+func (a *NopAction) isFabricIngressForwardingRoutingV4TableAction()  {}
+func (a *NopAction) isFabricIngressForwardingRoutingV6TableAction()  {}
+func (a *NopAction) isFabricIngressPreNextNextMplsTableAction()      {}
+func (a *NopAction) isFabricIngressPreNextNextVlanTableAction()      {}
+func (a *NopAction) isFabricIngressNextHashedTableAction()           {}
+func (a *NopAction) isFabricIngressQosDefaultTcTableAction()         {}
+func (a *NopAction) isFabricEgressPktIoEgressSwitchInfoTableAction() {}
+func (a *NopAction) isFabricEgressDscpRewriterRewriterTableAction()  {}
+
+// PacketInMetadata holds the metadata of controller header packet_in, without any padding
+type PacketInMetadata struct {
+	IngressPort uint64
+	RoleAgentId uint64
+}
+
+// PacketMetadata returns the P4Runtime representation of the metadata; returns error if any of the values does
+// not fit in its bitwidth
+func (m *PacketInMetadata) PacketMetadata() ([]*p4api.PacketMetadata, error) {
+	enc := &encoder{}
+	metadata := []*p4api.PacketMetadata{
+		{MetadataId: 2, Value: encodeField(enc, m.IngressPort, 32, "ingress_port")},
+		{MetadataId: 4, Value: encodeField(enc, m.RoleAgentId, 4, "role_agent_id")},
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return metadata, nil
+}
+
+// DecodePacketInMetadata decodes the given P4Runtime metadata, ignoring any unknown metadata
+func DecodePacketInMetadata(metadata []*p4api.PacketMetadata) *PacketInMetadata {
+	m := &PacketInMetadata{}
+	for _, md := range metadata {
+		switch md.MetadataId {
+		case 2:
+			m.IngressPort = decode[uint64](md.Value)
+		case 4:
+			m.RoleAgentId = decode[uint64](md.Value)
+		}
+	}
+	return m
+}
+
+// PacketOutMetadata holds the metadata of controller header packet_out, without any padding
+type PacketOutMetadata struct {
+	EgressPort      uint64
+	QueueId         uint64
+	CpuLoopbackMode uint64
+	DoForwarding    uint64
+	OverrideIngress uint64
+	IngressPort     uint64
+	EtherType       uint64
+}
+
+// PacketMetadata returns the P4Runtime representation of the metadata; returns error if any of the values does
+// not fit in its bitwidth
+func (m *PacketOutMetadata) PacketMetadata() ([]*p4api.PacketMetadata, error) {
+	enc := &encoder{}
+	metadata := []*p4api.PacketMetadata{
+		{MetadataId: 2, Value: encodeField(enc, m.EgressPort, 32, "egress_port")},
+		{MetadataId: 4, Value: encodeField(enc, m.QueueId, 5, "queue_id")},
+		{MetadataId: 6, Value: encodeField(enc, m.CpuLoopbackMode, 2, "cpu_loopback_mode")},
+		{MetadataId: 7, Value: encodeField(enc, m.DoForwarding, 1, "do_forwarding")},
+		{MetadataId: 9, Value: encodeField(enc, m.OverrideIngress, 1, "override_ingress")},
+		{MetadataId: 10, Value: encodeField(enc, m.IngressPort, 32, "ingress_port")},
+		{MetadataId: 12, Value: encodeField(enc, m.EtherType, 16, "ether_type")},
+	}
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return metadata, nil
+}
+
+// DecodePacketOutMetadata decodes the given P4Runtime metadata, ignoring any unknown metadata
+func DecodePacketOutMetadata(metadata []*p4api.PacketMetadata) *PacketOutMetadata {
+	m := &PacketOutMetadata{}
+	for _, md := range metadata {
+		switch md.MetadataId {
+		case 2:
+			m.EgressPort = decode[uint64](md.Value)
+		case 4:
+			m.QueueId = decode[uint64](md.Value)
+		case 6:
+			m.CpuLoopbackMode = decode[uint64](md.Value)
+		case 7:
+			m.DoForwarding = decode[uint64](md.Value)
+		case 9:
+			m.OverrideIngress = decode[uint64](md.Value)
+		case 10:
+			m.IngressPort = decode[uint64](md.Value)
+		case 12:
+			m.EtherType = decode[uint64](md.Value)
+		}
+	}
+	return m
+}
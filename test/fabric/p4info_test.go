@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fabric
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	p4api "github.com/p4lang/p4runtime/go/p4/v1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGeneratedBindings(t *testing.T) {
+	entry := &FabricIngressStatsFlowsEntry{
+		Ipv4Src:  &Ternary[uint64]{Value: 0x0a000000, Mask: 0xff000000},
+		IgPort:   260,
+		Priority: 10,
+		Action:   NewFabricIngressStatsCountAction(5),
+	}
+	update, err := entry.Update(p4api.Update_INSERT)
+	assert.NoError(t, err)
+	te := update.Entity.GetTableEntry()
+	assert.Equal(t, FabricIngressStatsFlowsTableID, te.TableId)
+	assert.Len(t, te.Match, 2)
+	assert.Equal(t, []byte{10, 0, 0, 0}, te.Match[0].GetTernary().Value)
+	assert.Equal(t, []byte{1, 4}, te.Match[1].GetExact().Value)
+	assert.Equal(t, FabricIngressStatsCountActionID, te.Action.GetAction().ActionId)
+	assert.Equal(t, []byte{5}, te.Action.GetAction().Params[0].Value)
+
+	// Default entries carry no matches, whatever the values of the match fields
+	bridging := &FabricIngressForwardingBridgingEntry{VlanId: 10, IsDefaultAction: true,
+		Action: NewFabricIngressForwardingSetNextIdBridgingAction(3)}
+	te, err = bridging.TableEntry()
+	assert.NoError(t, err)
+	assert.True(t, te.IsDefaultAction)
+	assert.Len(t, te.Match, 0)
+
+	// Values must fit in the bitwidth of their match fields
+	bridging = &FabricIngressForwardingBridgingEntry{VlanId: 4096, Action: NewFabricIngressForwardingSetNextIdBridgingAction(3)}
+	_, err = bridging.Entity()
+	assert.True(t, errors.IsInvalid(err))
+	bridging.VlanId = 4095
+	_, err = bridging.Entity()
+	assert.NoError(t, err)
+
+	packetIn := DecodePacketInMetadata([]*p4api.PacketMetadata{{MetadataId: 2, Value: []byte{1, 4}}, {MetadataId: 99, Value: []byte{1}}})
+	assert.Equal(t, uint64(260), packetIn.IngressPort)
+	packetOut := &PacketOutMetadata{EgressPort: 7}
+	metadata, err := packetOut.PacketMetadata()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{7}, metadata[0].Value)
+	assert.Equal(t, []byte{0}, metadata[1].Value)
+}